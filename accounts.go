@@ -0,0 +1,169 @@
+package awsri
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationsTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// stsAPI は複数アカウントの探索で使うSTS APIのインターフェース
+type stsAPI interface {
+	stscreds.AssumeRoleAPIClient
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+// organizationsAPI は複数アカウントの探索で使うOrganizations APIのインターフェース
+type organizationsAPI interface {
+	organizations.ListAccountsAPIClient
+}
+
+// accountTarget は探索するアカウントとその認証情報を表す構造体
+type accountTarget struct {
+	id  string // 現在の認証情報のみで探索する場合は空
+	cfg aws.Config
+}
+
+// label はログメッセージに付けるアカウントの識別子を返す
+func (a accountTarget) label() string {
+	if a.id == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (account %s)", a.id)
+}
+
+var accountIDPattern = regexp.MustCompile(`^\d{12}$`)
+
+// multiAccount は複数アカウントを探索するかどうかを返す
+func (c *GenerateCommand) multiAccount() bool {
+	return c.opts.AccountsFile != "" || c.opts.Org
+}
+
+// targetAccounts は探索するアカウントを決める
+// --accounts-fileと--orgのどちらも指定されていない場合は現在の認証情報のみを使う
+func (c *GenerateCommand) targetAccounts(ctx context.Context, cfg aws.Config) ([]accountTarget, error) {
+	if !c.multiAccount() {
+		return []accountTarget{{cfg: cfg}}, nil
+	}
+
+	var accountIDs []string
+	if c.opts.AccountsFile != "" {
+		ids, err := loadAccountsFile(c.opts.AccountsFile)
+		if err != nil {
+			return nil, err
+		}
+		accountIDs = append(accountIDs, ids...)
+	}
+	if c.opts.Org {
+		ids, err := listOrganizationAccounts(ctx, c.newOrganizationsClient(cfg))
+		if err != nil {
+			return nil, err
+		}
+		accountIDs = append(accountIDs, ids...)
+	}
+
+	stsClient := c.newSTSClient(cfg)
+	identity, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+	callerAccountID := aws.ToString(identity.Account)
+
+	var targets []accountTarget
+	seen := make(map[string]bool)
+	for _, accountID := range accountIDs {
+		if seen[accountID] {
+			continue
+		}
+		seen[accountID] = true
+
+		// 呼び出し元のアカウントには通常ロールがないため、現在の認証情報を使う
+		if accountID == callerAccountID {
+			targets = append(targets, accountTarget{id: accountID, cfg: cfg})
+			continue
+		}
+
+		targets = append(targets, accountTarget{
+			id:  accountID,
+			cfg: assumeRoleConfig(cfg, stsClient, roleARN(accountID, c.opts.RoleName)),
+		})
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no accounts to scan")
+	}
+
+	return targets, nil
+}
+
+// roleARN はメンバーアカウントで引き受けるロールのARNを返す
+func roleARN(accountID, roleName string) string {
+	return fmt.Sprintf("arn:aws:iam::%s:role/%s", accountID, roleName)
+}
+
+// assumeRoleConfig はロールを引き受けて認証情報を取得するcfgのコピーを返す
+func assumeRoleConfig(cfg aws.Config, client stscreds.AssumeRoleAPIClient, roleARN string) aws.Config {
+	assumed := cfg.Copy()
+	provider := stscreds.NewAssumeRoleProvider(client, roleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = "awsri"
+	})
+	assumed.Credentials = aws.NewCredentialsCache(provider)
+	return assumed
+}
+
+// loadAccountsFile はファイルからアカウントIDを読み込む
+// 1行に1つのアカウントIDを書き、空行と'#'で始まる行は無視する
+func loadAccountsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open accounts file: %w", err)
+	}
+	defer f.Close()
+
+	var accountIDs []string
+	scanner := bufio.NewScanner(f)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !accountIDPattern.MatchString(line) {
+			return nil, fmt.Errorf("invalid account ID at %s:%d: %s", path, lineNumber, line)
+		}
+		accountIDs = append(accountIDs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read accounts file: %w", err)
+	}
+
+	return accountIDs, nil
+}
+
+// listOrganizationAccounts はOrganizationの有効なメンバーアカウントを返す
+func listOrganizationAccounts(ctx context.Context, client organizationsAPI) ([]string, error) {
+	var accountIDs []string
+	paginator := organizations.NewListAccountsPaginator(client, &organizations.ListAccountsInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list organization accounts: %w", err)
+		}
+		for _, account := range result.Accounts {
+			if account.Status != organizationsTypes.AccountStatusActive {
+				continue
+			}
+			accountIDs = append(accountIDs, aws.ToString(account.Id))
+		}
+	}
+	return accountIDs, nil
+}
@@ -0,0 +1,166 @@
+package awsri
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	organizationsTypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	stsTypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+)
+
+// fakeSTS is a local stand-in for STS
+type fakeSTS struct {
+	callerAccount string
+	assumedRoles  []string
+}
+
+func (f *fakeSTS) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	return &sts.GetCallerIdentityOutput{Account: aws.String(f.callerAccount)}, nil
+}
+
+func (f *fakeSTS) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	f.assumedRoles = append(f.assumedRoles, aws.ToString(params.RoleArn))
+	return &sts.AssumeRoleOutput{
+		Credentials: &stsTypes.Credentials{
+			AccessKeyId:     aws.String("AKID-" + aws.ToString(params.RoleArn)),
+			SecretAccessKey: aws.String("secret"),
+			SessionToken:    aws.String("token"),
+			Expiration:      aws.Time(time.Now().Add(time.Hour)),
+		},
+	}, nil
+}
+
+// fakeOrganizations is a local stand-in for Organizations returning two pages of accounts
+type fakeOrganizations struct{}
+
+func (f *fakeOrganizations) ListAccounts(ctx context.Context, params *organizations.ListAccountsInput, optFns ...func(*organizations.Options)) (*organizations.ListAccountsOutput, error) {
+	if params.NextToken == nil {
+		return &organizations.ListAccountsOutput{
+			Accounts: []organizationsTypes.Account{
+				{Id: aws.String("111111111111"), Status: organizationsTypes.AccountStatusActive},
+				{Id: aws.String("222222222222"), Status: organizationsTypes.AccountStatusSuspended},
+			},
+			NextToken: aws.String("page2"),
+		}, nil
+	}
+	return &organizations.ListAccountsOutput{
+		Accounts: []organizationsTypes.Account{
+			{Id: aws.String("333333333333"), Status: organizationsTypes.AccountStatusActive},
+		},
+	}, nil
+}
+
+func TestGenerateMultiAccount(t *testing.T) {
+	accountsFile := filepath.Join(t.TempDir(), "accounts.txt")
+	if err := os.WriteFile(accountsFile, []byte("# payer\n111111111111\n\n444444444444\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write accounts file: %v", err)
+	}
+
+	fake := &fakeSTS{callerAccount: "111111111111"}
+	cmd := NewGenerateCommand(GenerateOption{
+		Region:       "ap-northeast-1",
+		Regions:      []string{"ap-northeast-1", "us-east-1"},
+		AccountsFile: accountsFile,
+		Org:          true,
+		RoleName:     "AuditRole",
+	})
+	cmd.newSTSClient = func(cfg aws.Config) stsAPI { return fake }
+	cmd.newOrganizationsClient = func(cfg aws.Config) organizationsAPI { return &fakeOrganizations{} }
+	var mu sync.Mutex
+	accessKeys := make(map[string]bool)
+	cmd.discover = func(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+		// 引き受けたロールの認証情報で呼び出されていることを確認する
+		creds, err := cfg.Credentials.Retrieve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		mu.Lock()
+		accessKeys[creds.AccessKeyID] = true
+		mu.Unlock()
+
+		return []InstanceInfo{
+			{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, Description: "postgresql"},
+			{ServiceType: "elasticache", InstanceType: "cache.m5.large", Count: 2, Description: "redis"},
+		}, nil
+	}
+
+	baseCfg := aws.Config{
+		Region: "ap-northeast-1",
+		Credentials: aws.NewCredentialsCache(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKID-base", SecretAccessKey: "secret"}, nil
+		})),
+	}
+
+	accounts, err := cmd.targetAccounts(context.Background(), baseCfg)
+	if err != nil {
+		t.Fatalf("Failed to resolve accounts: %v", err)
+	}
+	var ids []string
+	for _, account := range accounts {
+		ids = append(ids, account.id)
+	}
+	expectedIDs := []string{"111111111111", "444444444444", "333333333333"}
+	if !reflect.DeepEqual(ids, expectedIDs) {
+		t.Errorf("Accounts mismatch.\nExpected: %v\nGot: %v", expectedIDs, ids)
+	}
+
+	instances, _, err := cmd.scan(context.Background(), accounts, cmd.targetRegions())
+	if err != nil {
+		t.Fatalf("Failed to scan: %v", err)
+	}
+
+	// 呼び出し元のアカウント以外ではロールを引き受ける
+	sort.Strings(fake.assumedRoles)
+	expectedRoles := []string{
+		"arn:aws:iam::333333333333:role/AuditRole",
+		"arn:aws:iam::444444444444:role/AuditRole",
+	}
+	if !reflect.DeepEqual(fake.assumedRoles, expectedRoles) {
+		t.Errorf("Assumed roles mismatch.\nExpected: %v\nGot: %v", expectedRoles, fake.assumedRoles)
+	}
+
+	expectedKeys := map[string]bool{
+		"AKID-base": true,
+		"AKID-arn:aws:iam::333333333333:role/AuditRole": true,
+		"AKID-arn:aws:iam::444444444444:role/AuditRole": true,
+	}
+	if !reflect.DeepEqual(accessKeys, expectedKeys) {
+		t.Errorf("Credentials mismatch.\nExpected: %v\nGot: %v", expectedKeys, accessKeys)
+	}
+
+	aggregated := aggregateInstances(instances)
+	if len(aggregated) != 4 {
+		t.Fatalf("Expected 4 aggregated lines (2 services x 2 regions), got %d: %+v", len(aggregated), aggregated)
+	}
+	for _, instance := range aggregated {
+		if instance.ServiceType != "rds" {
+			continue
+		}
+		if instance.Count != 3 {
+			t.Errorf("Expected RDS count 3 in %s, got %d", instance.Region, instance.Count)
+		}
+		expectedAccounts := map[string]int{"111111111111": 1, "333333333333": 1, "444444444444": 1}
+		if !reflect.DeepEqual(instance.Accounts, expectedAccounts) {
+			t.Errorf("Per-account breakdown mismatch.\nExpected: %v\nGot: %v", expectedAccounts, instance.Accounts)
+		}
+	}
+}
+
+func TestLoadAccountsFileInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.txt")
+	if err := os.WriteFile(path, []byte("12345\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write accounts file: %v", err)
+	}
+	if _, err := loadAccountsFile(path); err == nil {
+		t.Error("Expected error for invalid account ID")
+	}
+}
@@ -15,29 +15,63 @@ type GlobalOptions struct {
 }
 
 type CLI struct {
-	RDS                 RDSOption                 `cmd:"rds" help:"RDS"`
-	Elasticache         ElasticacheOption         `cmd:"elasticache" help:"ElastiCache"`
-	ComputeSavingsPlans ComputeSavingsPlansOption `cmd:"compute-savings-plans" help:"Compute Savings Plans"`
-	Total               TotalOption               `cmd:"total" help:"Calculate total cost of multiple RIs"`
-	Generate            GenerateOption            `cmd:"generate" help:"Generate total command arguments from AWS account"`
-	Version             struct{}                  `cmd:"version" help:"show version"`
+	RDS                   RDSOption                   `cmd:"rds" help:"RDS"`
+	Elasticache           ElasticacheOption           `cmd:"elasticache" help:"ElastiCache"`
+	Opensearch            OpenSearchOption            `cmd:"opensearch" help:"OpenSearch Service"`
+	Redshift              RedshiftOption              `cmd:"redshift" help:"Redshift (provisioned clusters)"`
+	Memorydb              MemoryDBOption              `cmd:"memorydb" help:"MemoryDB"`
+	Dynamodb              DynamoDBOption              `cmd:"dynamodb" help:"DynamoDB reserved capacity"`
+	Docdb                 DocDBOption                 `cmd:"docdb" help:"DocumentDB"`
+	Neptune               NeptuneOption               `cmd:"neptune" help:"Neptune"`
+	EC2RI                 EC2RIOption                 `cmd:"ec2-ri" name:"ec2-ri" help:"EC2 Standard and Convertible Reserved Instances"`
+	EC2Compare            EC2CompareOption            `cmd:"ec2-compare" name:"ec2-compare" help:"Compare EC2 Savings Plans and Reserved Instances side by side"`
+	ComputeSavingsPlans   ComputeSavingsPlansOption   `cmd:"compute-savings-plans" help:"Compute Savings Plans"`
+	SagemakerSavingsPlans SageMakerSavingsPlansOption `cmd:"sagemaker-savings-plans" help:"SageMaker Savings Plans"`
+	SpOptimize            SPOptimizeOption            `cmd:"sp-optimize" help:"Find the Savings Plans commitment that maximizes net savings from hourly spend"`
+	Cur                   CUROption                   `cmd:"cur" help:"Generate total and Savings Plans commands from the running hours in Cost and Usage Report exports"`
+	Utilization           UtilizationOption           `cmd:"utilization" help:"Report underused reservations and Savings Plans and uncovered on-demand spend from Cost Explorer"`
+	Recommendations       RecommendationsOption       `cmd:"recommendations" help:"Compare Cost Explorer purchase recommendations with awsri's estimates for a manifest"`
+	Expirations           ExpirationsOption           `cmd:"expirations" help:"List active reservations and Savings Plans by end date with renewal prices"`
+	Total                 TotalOption                 `cmd:"total" help:"Calculate total cost of multiple RIs"`
+	Generate              GenerateOption              `cmd:"generate" help:"Generate total command arguments from AWS account"`
+	Version               struct{}                    `cmd:"version" help:"show version"`
 }
 
 type TotalOption struct {
-	RDSInstances         []string `name:"rds" help:"RDS instances in format: instance-type:count:product-description:multi-az"`
-	ElasticacheInstances []string `name:"elasticache" help:"ElastiCache instances in format: node-type:count:product-description"`
-	Duration             int      `name:"duration" default:"1" help:"Duration in years (1 or 3)"`
-	OfferingType         string   `name:"offering-type" default:"Partial Upfront" help:"Offering type (No Upfront, Partial Upfront, All Upfront)"`
+	RDSInstances         []string `name:"rds" help:"RDS instances in format: instance-type:count:product-description:multi-az[:region]"`
+	ElasticacheInstances []string `name:"elasticache" help:"ElastiCache instances in format: node-type:count:product-description[:region]"`
+	OpenSearchInstances  []string `name:"opensearch" help:"OpenSearch instances in format: instance-type:count[:region]"`
+	RedshiftNodes        []string `name:"redshift" help:"Redshift nodes in format: node-type:count[:region]"`
+	MemoryDBNodes        []string `name:"memorydb" help:"MemoryDB nodes in format: node-type:count[:region]"`
+	DynamoDBCapacity     []string `name:"dynamodb" help:"DynamoDB provisioned capacity in format: read|write:units[:region]"`
+	DocDBInstances       []string `name:"docdb" help:"DocumentDB instances in format: instance-class:count[:region]"`
+	NeptuneInstances     []string `name:"neptune" help:"Neptune instances in format: instance-class:count[:region]"`
+	Manifest             string   `name:"manifest" help:"Path to a manifest JSON file generated by 'awsri generate --output=json'"`
+	Region               string   `name:"region" default:"ap-northeast-1" help:"Default AWS region for instances without a region"`
+	Duration             int      `name:"duration" help:"Duration in years (1 or 3; default: the manifest's duration, or 1)"`
+	OfferingType         string   `name:"offering-type" help:"Offering type (No Upfront, Partial Upfront, All Upfront; default: the manifest's offering type, or Partial Upfront)"`
 	Format               string   `name:"format" default:"table" help:"Output format (table, csv)"`
+	Normalize            bool     `name:"normalize" help:"Convert size-flexible RDS lines into normalization units and buy the smallest class of each family"`
+
+	ScheduleOption `embed:""`
 }
 
 type GenerateOption struct {
-	Region            string `name:"region" default:"ap-northeast-1" help:"AWS region"`
-	RDSEngine         string `name:"rds-engine" default:"postgresql" help:"Default engine type for RDS instances"`
-	ElastiCacheEngine string `name:"elasticache-engine" default:"redis" help:"Default engine type for ElastiCache instances"`
-	Duration          int    `name:"duration" default:"1" help:"Duration in years (1 or 3)"`
-	OfferingType      string `name:"offering-type" default:"Partial Upfront" help:"Offering type (No Upfront, Partial Upfront, All Upfront)"`
-	Output            string `name:"output" default:"command" help:"Output format (command, args, json)"`
+	Region            string   `name:"region" default:"ap-northeast-1" help:"AWS region"`
+	Regions           []string `name:"regions" sep:"," help:"AWS regions to scan in parallel (comma separated, or 'all')"`
+	RDSEngine         string   `name:"rds-engine" default:"postgresql" help:"Default engine type for RDS instances"`
+	ElastiCacheEngine string   `name:"elasticache-engine" default:"redis" help:"Default engine type for ElastiCache instances"`
+	Duration          int      `name:"duration" default:"1" help:"Duration in years (1 or 3)"`
+	OfferingType      string   `name:"offering-type" default:"Partial Upfront" help:"Offering type (No Upfront, Partial Upfront, All Upfront)"`
+	Output            string   `name:"output" default:"command" help:"Output format (command, args, json)"`
+	AccountsFile      string   `name:"accounts-file" help:"Path to a file listing member account IDs (one per line) to scan via AssumeRole"`
+	Org               bool     `name:"org" help:"Scan all active member accounts of the AWS Organization via AssumeRole"`
+	RoleName          string   `name:"role-name" default:"OrganizationAccountAccessRole" help:"IAM role name to assume in each member account"`
+	Uncovered         bool     `name:"uncovered" help:"Subtract active RDS, ElastiCache, DocumentDB and Neptune reservations and emit only the uncovered demand"`
+	IncludeTags       []string `name:"include-tag" help:"Only include resources with the tag (key=value or key, repeatable)"`
+	ExcludeTags       []string `name:"exclude-tag" help:"Exclude resources with the tag (key=value or key, repeatable)"`
+	GroupByTag        string   `name:"group-by-tag" help:"Split lines by the value of the tag key (e.g. a cost allocation tag)"`
+	MinAge            string   `name:"min-age" help:"Skip instances created more recently than this (e.g. 90d, 720h)"`
 }
 
 func RunCLI(ctx context.Context, args []string) error {
@@ -70,9 +104,33 @@ func Dispatch(ctx context.Context, command string, cli *CLI) error {
 	case "elasticache":
 		cmd := NewElastiCacheCommand(cli.Elasticache)
 		return cmd.Run(ctx)
+	case "opensearch":
+		cmd := NewOpenSearchCommand(cli.Opensearch)
+		return cmd.Run(ctx)
+	case "redshift":
+		cmd := NewRedshiftCommand(cli.Redshift)
+		return cmd.Run(ctx)
+	case "memorydb":
+		cmd := NewMemoryDBCommand(cli.Memorydb)
+		return cmd.Run(ctx)
+	case "dynamodb":
+		cmd := NewDynamoDBCommand(cli.Dynamodb)
+		return cmd.Run(ctx)
+	case "docdb":
+		cmd := NewDocDBCommand(cli.Docdb)
+		return cmd.Run(ctx)
+	case "neptune":
+		cmd := NewNeptuneCommand(cli.Neptune)
+		return cmd.Run(ctx)
+	case "ec2-ri":
+		cmd := NewEC2RICommand(cli.EC2RI)
+		return cmd.Run(ctx)
+	case "ec2-compare":
+		cmd := NewEC2CompareCommand(cli.EC2Compare)
+		return cmd.Run(ctx)
 	case "compute-savings-plans":
 		if len(parts) < 2 {
-			return fmt.Errorf("compute-savings-plans requires a subcommand (fargate or ec2)")
+			return fmt.Errorf("compute-savings-plans requires a subcommand (fargate, ec2 or lambda)")
 		}
 		subcommand := parts[1]
 		switch subcommand {
@@ -82,9 +140,30 @@ func Dispatch(ctx context.Context, command string, cli *CLI) error {
 		case "ec2":
 			cmd := NewEC2Command(cli.ComputeSavingsPlans.Ec2)
 			return cmd.Run(ctx)
+		case "lambda":
+			cmd := NewLambdaCommand(cli.ComputeSavingsPlans.Lambda)
+			return cmd.Run(ctx)
 		default:
-			return fmt.Errorf("unknown subcommand for compute-savings-plans: %s (must be fargate or ec2)", subcommand)
+			return fmt.Errorf("unknown subcommand for compute-savings-plans: %s (must be fargate, ec2 or lambda)", subcommand)
 		}
+	case "sagemaker-savings-plans":
+		cmd := NewSageMakerSavingsPlansCommand(cli.SagemakerSavingsPlans)
+		return cmd.Run(ctx)
+	case "sp-optimize":
+		cmd := NewSPOptimizeCommand(cli.SpOptimize)
+		return cmd.Run(ctx)
+	case "cur":
+		cmd := NewCURCommand(cli.Cur)
+		return cmd.Run(ctx)
+	case "utilization":
+		cmd := NewUtilizationCommand(cli.Utilization)
+		return cmd.Run(ctx)
+	case "recommendations":
+		cmd := NewRecommendationsCommand(cli.Recommendations)
+		return cmd.Run(ctx)
+	case "expirations":
+		cmd := NewExpirationsCommand(cli.Expirations)
+		return cmd.Run(ctx)
 	case "total":
 		cmd := NewTotalCommand(cli.Total)
 		return cmd.Run(ctx)
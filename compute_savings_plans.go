@@ -3,4 +3,5 @@ package awsri
 type ComputeSavingsPlansOption struct {
 	Fargate FargateOption `cmd:"fargate" help:"Fargate Savings Plan"`
 	Ec2     EC2Option     `cmd:"" name:"ec2" help:"EC2 Compute Savings Plan"`
+	Lambda  LambdaOption  `cmd:"lambda" help:"Lambda Compute Savings Plan"`
 }
@@ -0,0 +1,581 @@
+package awsri
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type CUROption struct {
+	Files             []string `name:"file" required:"" help:"CUR 2.0 export file in CSV, optionally gzip-compressed, or Parquet (repeatable)"`
+	Start             string   `name:"start" help:"Start of the usage window (YYYY-MM-DD, inclusive; default: first hour in the files)"`
+	End               string   `name:"end" help:"End of the usage window (YYYY-MM-DD, exclusive; default: last hour in the files)"`
+	Statistic         string   `name:"statistic" default:"average" help:"Running instances per line (average, minimum over the hours of the window)"`
+	RDSEngine         string   `name:"rds-engine" default:"postgresql" help:"Engine for RDS usage without a database engine attribute"`
+	ElastiCacheEngine string   `name:"elasticache-engine" default:"redis" help:"Engine for ElastiCache usage without a cache engine attribute"`
+	Duration          int      `name:"duration" default:"1" help:"Duration in years (1 or 3)"`
+	OfferingType      string   `name:"offering-type" default:"Partial Upfront" help:"Offering type (No Upfront, Partial Upfront, All Upfront)"`
+	Output            string   `name:"output" default:"command" help:"Output format (command, json)"`
+}
+
+type CURCommand struct {
+	opts CUROption
+}
+
+// curLineItemTypes はオンデマンド換算の使用量として集計する明細の種類
+// RIやSavings Plansが適用された使用量も実際に稼働した時間として含める
+var curLineItemTypes = map[string]bool{
+	"Usage":                   true,
+	"DiscountedUsage":         true,
+	"SavingsPlanCoveredUsage": true,
+}
+
+// curEC2Platforms はEC2のBoxUsageのオペレーションごとのOSとプリインストールソフトウェア（--os, --preinstalled-sw）
+var curEC2Platforms = map[string]EC2PlatformOption{
+	"RunInstances":      {OS: "linux"},
+	"RunInstances:0002": {OS: "windows"},
+	"RunInstances:0800": {OS: "windows", LicenseModel: "byol"},
+	"RunInstances:0010": {OS: "rhel"},
+	"RunInstances:1010": {OS: "rhel-ha"},
+	"RunInstances:000g": {OS: "suse"},
+	"RunInstances:0g00": {OS: "ubuntu-pro"},
+	"RunInstances:0202": {OS: "windows", PreInstalledSw: "sql-web"},
+	"RunInstances:0006": {OS: "windows", PreInstalledSw: "sql-std"},
+	"RunInstances:0102": {OS: "windows", PreInstalledSw: "sql-ent"},
+	"RunInstances:0200": {OS: "linux", PreInstalledSw: "sql-web"},
+	"RunInstances:0004": {OS: "linux", PreInstalledSw: "sql-std"},
+	"RunInstances:0100": {OS: "linux", PreInstalledSw: "sql-ent"},
+}
+
+// curUsageKey は使用量を集計する単位
+type curUsageKey struct {
+	serviceType  string // "rds", "elasticache", "ec2", "fargate"
+	instanceType string // Fargateはアーキテクチャ（x86_64, arm）
+	description  string // エンジン、EC2はオペレーション
+	multiAz      bool
+	region       string
+}
+
+// curFargateUsage はFargateの時間ごとのvCPU時間とGB時間
+type curFargateUsage struct {
+	vcpu   map[time.Time]float64
+	memory map[time.Time]float64
+}
+
+// curUsage はCURから集計した時間ごとの使用量
+type curUsage struct {
+	hours   map[curUsageKey]map[time.Time]float64
+	fargate map[curUsageKey]*curFargateUsage
+	first   time.Time
+	last    time.Time
+}
+
+func NewCURCommand(opts CUROption) *CURCommand {
+	return &CURCommand{opts: opts}
+}
+
+func (c *CURCommand) Run(ctx context.Context) error {
+	if c.opts.Statistic != "average" && c.opts.Statistic != "minimum" {
+		return fmt.Errorf("statistic must be average or minimum, got: %s", c.opts.Statistic)
+	}
+	start, end, err := c.window()
+	if err != nil {
+		return err
+	}
+
+	usage := newCURUsage()
+	for _, file := range c.opts.Files {
+		if err := c.readFile(file, usage, start, end); err != nil {
+			return err
+		}
+	}
+	if len(usage.hours) == 0 && len(usage.fargate) == 0 {
+		return fmt.Errorf("no RDS, ElastiCache, EC2 or Fargate usage found")
+	}
+
+	// 期間が指定されていない場合はファイルの最初と最後の時間を使う
+	if start.IsZero() {
+		start = usage.first
+	}
+	if end.IsZero() {
+		end = usage.last.Add(time.Hour)
+	}
+	hours := int(end.Sub(start).Hours())
+	fmt.Fprintf(os.Stderr, "Usage window: %s - %s (%d hours, %s running instances)\n",
+		start.Format(time.RFC3339), end.Format(time.RFC3339), hours, c.opts.Statistic)
+
+	instances := usage.instances(start, hours, c.opts.Statistic)
+
+	switch c.opts.Output {
+	case "command":
+		fmt.Println(c.formatCommandOutput(instances, usage.fargateCommands(start, hours)))
+	case "json":
+		manifest := NewManifest(instances, c.opts.Duration, c.opts.OfferingType)
+		jsonData, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+	default:
+		return fmt.Errorf("unsupported output format: %s", c.opts.Output)
+	}
+
+	return nil
+}
+
+// window は--startと--endの期間を返す（指定がない場合はゼロ値）
+func (c *CURCommand) window() (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+	if c.opts.Start != "" {
+		if start, err = time.Parse("2006-01-02", c.opts.Start); err != nil {
+			return start, end, fmt.Errorf("invalid start: %s (expected YYYY-MM-DD)", c.opts.Start)
+		}
+	}
+	if c.opts.End != "" {
+		if end, err = time.Parse("2006-01-02", c.opts.End); err != nil {
+			return start, end, fmt.Errorf("invalid end: %s (expected YYYY-MM-DD)", c.opts.End)
+		}
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return start, end, fmt.Errorf("start must be before end")
+	}
+	return start, end, nil
+}
+
+// readFile はCURのファイルを読み込んで使用量に加算する
+func (c *CURCommand) readFile(path string, usage *curUsage, start, end time.Time) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open CUR file: %w", err)
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(strings.ToLower(path), ".parquet") {
+		info, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := usage.readParquet(f, info.Size(), start, end, c.opts.RDSEngine, c.opts.ElastiCacheEngine); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return nil
+	}
+
+	var r io.Reader = f
+	if strings.HasSuffix(strings.ToLower(path), ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	if err := usage.read(r, start, end, c.opts.RDSEngine, c.opts.ElastiCacheEngine); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// formatCommandOutput はtotalとcompute-savings-plansのコマンドを出力する
+// EC2とFargateはtotalの対象外のため、Savings Plansのコマンドを行ごとに出力する
+func (c *CURCommand) formatCommandOutput(instances []InstanceInfo, fargateCommands []string) string {
+	var totalInstances []InstanceInfo
+	var lines []string
+	for _, instance := range instances {
+		if instance.ServiceType != "ec2" {
+			totalInstances = append(totalInstances, instance)
+			continue
+		}
+		platform := curEC2Platforms[instance.Description]
+		args := fmt.Sprintf("--region=%s --instance-type=%s --count=%d --os=%s", instance.Region, instance.InstanceType, instance.Count, platform.OS)
+		if platform.LicenseModel != "" {
+			args += " --license-model=" + platform.LicenseModel
+		}
+		if platform.PreInstalledSw != "" {
+			args += " --preinstalled-sw=" + platform.PreInstalledSw
+		}
+		lines = append(lines, "awsri compute-savings-plans ec2 "+args)
+	}
+
+	if len(totalInstances) > 0 {
+		generate := NewGenerateCommand(GenerateOption{Duration: c.opts.Duration, OfferingType: c.opts.OfferingType})
+		lines = append([]string{generate.formatCommandOutput(totalInstances)}, lines...)
+	}
+	lines = append(lines, fargateCommands...)
+	return strings.Join(lines, "\n")
+}
+
+func newCURUsage() *curUsage {
+	return &curUsage{
+		hours:   make(map[curUsageKey]map[time.Time]float64),
+		fargate: make(map[curUsageKey]*curFargateUsage),
+	}
+}
+
+// read はCURのCSVを読み込み、期間内の使用量を時間ごとに集計する
+func (u *curUsage) read(r io.Reader, start, end time.Time, rdsEngine, elastiCacheEngine string) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read CUR header: %w", err)
+	}
+	return u.readRecords(header, reader.Read, start, end, rdsEngine, elastiCacheEngine)
+}
+
+// readParquet はCURのParquetを読み込み、期間内の使用量を時間ごとに集計する
+// 各列の値はCSVと同じ文字列に変換してから集計する
+func (u *curUsage) readParquet(r io.ReaderAt, size int64, start, end time.Time, rdsEngine, elastiCacheEngine string) error {
+	file, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return fmt.Errorf("failed to open Parquet: %w", err)
+	}
+	fields := file.Schema().Fields()
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = field.Name()
+	}
+
+	reader := parquet.NewReader(file)
+	defer reader.Close()
+	next := func() ([]string, error) {
+		row := make(map[string]any)
+		if err := reader.Read(&row); err != nil {
+			return nil, err
+		}
+		record := make([]string, len(fields))
+		for i, field := range fields {
+			record[i] = parquetValueString(field, row[field.Name()])
+		}
+		return record, nil
+	}
+	return u.readRecords(header, next, start, end, rdsEngine, elastiCacheEngine)
+}
+
+// readRecords はヘッダーとnextが返す行から期間内の使用量を時間ごとに集計する。nextは最後にio.EOFを返す
+func (u *curUsage) readRecords(header []string, next func() ([]string, error), start, end time.Time, rdsEngine, elastiCacheEngine string) error {
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[curColumnName(name)] = i
+	}
+	for _, name := range []string{"line_item_usage_start_date", "line_item_product_code", "line_item_usage_type", "line_item_usage_amount"} {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("column %s not found", name)
+		}
+	}
+
+	for {
+		record, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read CUR: %w", err)
+		}
+		row := curRow{columns: columns, record: record}
+
+		if lineItemType := row.get("line_item_line_item_type"); lineItemType != "" && !curLineItemTypes[lineItemType] {
+			continue
+		}
+		hour, err := parseCURTime(row.get("line_item_usage_start_date"))
+		if err != nil {
+			return err
+		}
+		if (!start.IsZero() && hour.Before(start)) || (!end.IsZero() && !hour.Before(end)) {
+			continue
+		}
+		var amount float64
+		if _, err := fmt.Sscan(row.get("line_item_usage_amount"), &amount); err != nil || amount <= 0 {
+			continue
+		}
+
+		if u.add(row, hour, amount, rdsEngine, elastiCacheEngine) {
+			if u.first.IsZero() || hour.Before(u.first) {
+				u.first = hour
+			}
+			if hour.After(u.last) {
+				u.last = hour
+			}
+		}
+	}
+	return nil
+}
+
+// add は明細の使用量を加算する。集計対象の明細の場合はtrueを返す
+func (u *curUsage) add(row curRow, hour time.Time, amount float64, rdsEngine, elastiCacheEngine string) bool {
+	usageType := row.get("line_item_usage_type")
+	region := row.get("product_region_code")
+	if region == "" {
+		region = row.product("region")
+	}
+
+	// 使用タイプのリージョンの接頭辞（APN1- など）を除く
+	name, resource, _ := strings.Cut(usageType, ":")
+	if i := strings.Index(name, "-"); i >= 0 && strings.ToUpper(name[:i]) == name[:i] {
+		name = name[i+1:]
+	}
+
+	switch row.get("line_item_product_code") {
+	case "AmazonRDS":
+		if !strings.HasPrefix(resource, "db.") || (!strings.HasPrefix(name, "InstanceUsage") && !strings.HasPrefix(name, "Multi-AZUsage")) {
+			return false
+		}
+		engine := curRDSProductDescription(row)
+		if engine == "" {
+			engine = rdsEngine
+		}
+		u.addHours(curUsageKey{"rds", resource, engine, strings.HasPrefix(name, "Multi-AZUsage"), region}, hour, amount)
+	case "AmazonElastiCache":
+		if !strings.HasPrefix(name, "NodeUsage") || !strings.HasPrefix(resource, "cache.") {
+			return false
+		}
+		engine := strings.ToLower(row.product("cache_engine"))
+		if engine == "" {
+			engine = elastiCacheEngine
+		}
+		u.addHours(curUsageKey{"elasticache", resource, engine, false, region}, hour, amount)
+	case "AmazonEC2":
+		// 共有テナンシーのオンデマンドインスタンスの使用量のみ
+		operation := row.get("line_item_operation")
+		if name != "BoxUsage" || resource == "" {
+			return false
+		}
+		if _, ok := curEC2Platforms[operation]; !ok {
+			return false
+		}
+		u.addHours(curUsageKey{"ec2", resource, operation, false, region}, hour, amount)
+	case "AmazonECS", "AmazonEKS":
+		// Linuxのオンデマンドのタスクの使用量のみ（エフェメラルストレージ、Spot、Windowsは除く）
+		isVCPU := name == "Fargate-vCPU-Hours" || name == "Fargate-ARM-vCPU-Hours"
+		isMemory := name == "Fargate-GB-Hours" || name == "Fargate-ARM-GB-Hours"
+		if !isVCPU && !isMemory {
+			return false
+		}
+		architecture := "x86_64"
+		if strings.HasPrefix(name, "Fargate-ARM-") {
+			architecture = "arm"
+		}
+		key := curUsageKey{serviceType: "fargate", instanceType: architecture, region: region}
+		fargate, ok := u.fargate[key]
+		if !ok {
+			fargate = &curFargateUsage{vcpu: make(map[time.Time]float64), memory: make(map[time.Time]float64)}
+			u.fargate[key] = fargate
+		}
+		if isVCPU {
+			fargate.vcpu[hour] += amount
+		} else {
+			fargate.memory[hour] += amount
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (u *curUsage) addHours(key curUsageKey, hour time.Time, amount float64) {
+	if _, ok := u.hours[key]; !ok {
+		u.hours[key] = make(map[time.Time]float64)
+	}
+	u.hours[key][hour] += amount
+}
+
+// instances は集計単位ごとの稼働インスタンス数を返す
+// 平均（average）または期間内の最小（minimum）を切り捨てた台数とし、0台の行は除く
+func (u *curUsage) instances(start time.Time, hours int, statistic string) []InstanceInfo {
+	var instances []InstanceInfo
+	for key, hourly := range u.hours {
+		count := int(math.Floor(hourlyStatistic(hourly, start, hours, statistic) + 1e-9))
+		if count == 0 {
+			continue
+		}
+		instances = append(instances, InstanceInfo{
+			ServiceType:  key.serviceType,
+			InstanceType: key.instanceType,
+			Count:        count,
+			Description:  key.description,
+			MultiAz:      key.multiAz,
+			Region:       key.region,
+		})
+	}
+
+	// 出力を安定させるため並べ替える
+	sort.Slice(instances, func(i, j int) bool {
+		a, b := instances[i], instances[j]
+		if a.ServiceType != b.ServiceType {
+			return a.ServiceType < b.ServiceType
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.InstanceType != b.InstanceType {
+			return a.InstanceType < b.InstanceType
+		}
+		if a.Description != b.Description {
+			return a.Description < b.Description
+		}
+		return !a.MultiAz && b.MultiAz
+	})
+	return instances
+}
+
+// fargateCommands はアーキテクチャ・リージョンごとの平均のvCPUとメモリでcompute-savings-plans fargateのコマンドを返す
+// Fargateは時間ごとに変動するため、常に平均を使う
+func (u *curUsage) fargateCommands(start time.Time, hours int) []string {
+	var commands []string
+	for key, fargate := range u.fargate {
+		vcpu := hourlyStatistic(fargate.vcpu, start, hours, "average")
+		memory := hourlyStatistic(fargate.memory, start, hours, "average")
+		if vcpu == 0 || memory == 0 {
+			continue
+		}
+		commands = append(commands, fmt.Sprintf("awsri compute-savings-plans fargate --region=%s --vcpu-millicores-per-hour=%.0f --memory-mb-per-hour=%.0f --task-count=1 --architecture=%s",
+			key.region, vcpu*1000, memory*1024, key.instanceType))
+	}
+	sort.Strings(commands)
+	return commands
+}
+
+// hourlyStatistic は期間内の時間ごとの使用量の平均または最小を返す（使用量のない時間は0とする）
+func hourlyStatistic(hourly map[time.Time]float64, start time.Time, hours int, statistic string) float64 {
+	if hours <= 0 {
+		return 0
+	}
+	if statistic == "minimum" {
+		minimum := math.Inf(1)
+		for i := 0; i < hours; i++ {
+			if amount := hourly[start.Add(time.Duration(i)*time.Hour)]; amount < minimum {
+				minimum = amount
+			}
+		}
+		return minimum
+	}
+
+	total := 0.0
+	for hour, amount := range hourly {
+		if !hour.Before(start) && hour.Before(start.Add(time.Duration(hours)*time.Hour)) {
+			total += amount
+		}
+	}
+	return total / float64(hours)
+}
+
+// curRow はCURの1行
+type curRow struct {
+	columns    map[string]int
+	record     []string
+	attributes map[string]string // CUR 2.0のproduct列のマップ
+}
+
+// get は列の値を返す（列がない場合は空文字）
+func (r curRow) get(name string) string {
+	i, ok := r.columns[name]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+// product は製品の属性を返す
+// CUR 2.0ではproduct列にJSONのマップで、従来のCURではproduct_<属性>列に格納されている
+func (r *curRow) product(attribute string) string {
+	if value := r.get("product_" + attribute); value != "" {
+		return value
+	}
+	if r.attributes == nil {
+		r.attributes = make(map[string]string)
+		if value := r.get("product"); value != "" {
+			_ = json.Unmarshal([]byte(value), &r.attributes)
+		}
+	}
+	return r.attributes[attribute]
+}
+
+// curRDSProductDescription はRDSの明細のエンジン・エディション・ライセンスモデルからRIのproductDescriptionを返す
+func curRDSProductDescription(row curRow) string {
+	return rdsPricingProductDescription(row.product("database_engine"), row.product("database_edition"), row.product("license_model"))
+}
+
+// parquetValueString はParquetの列の値をCSVと同じ形式の文字列にする
+// タイムスタンプはRFC3339、product列のようなマップはJSONにする
+func parquetValueString(field parquet.Field, value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		if logicalType := field.Type().LogicalType(); logicalType != nil && logicalType.Timestamp != nil {
+			unit := logicalType.Timestamp.Unit
+			switch {
+			case unit.Millis != nil:
+				return time.UnixMilli(v).UTC().Format(time.RFC3339)
+			case unit.Micros != nil:
+				return time.UnixMicro(v).UTC().Format(time.RFC3339)
+			default:
+				return time.Unix(0, v).UTC().Format(time.RFC3339)
+			}
+		}
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case map[string]any:
+		attributes := make(map[string]string, len(v))
+		for key, value := range v {
+			attributes[key] = parquetValueString(field, value)
+		}
+		b, err := json.Marshal(attributes)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// curColumnName は列名をCUR 2.0の形式にそろえる（例: lineItem/UsageType -> line_item_usage_type）
+func curColumnName(name string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(name) {
+		switch {
+		case r == '/':
+			b.WriteRune('_')
+		case unicode.IsUpper(r):
+			if i > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// parseCURTime は明細の開始日時を時間単位で返す
+func parseCURTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05.000Z", "2006-01-02 15:04:05", "2006-01-02T15:04Z"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC().Truncate(time.Hour), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid usage start date: %s", value)
+}
@@ -0,0 +1,139 @@
+package awsri
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestCURColumnName(t *testing.T) {
+	tests := map[string]string{
+		"lineItem/UsageType":      "line_item_usage_type",
+		"lineItem/UsageStartDate": "line_item_usage_start_date",
+		"product/databaseEngine":  "product_database_engine",
+		"line_item_usage_amount":  "line_item_usage_amount",
+	}
+	for input, expected := range tests {
+		if got := curColumnName(input); got != expected {
+			t.Errorf("curColumnName(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestCURUsageInstances(t *testing.T) {
+	// CUR 2.0のCSV（product列はJSONのマップ）
+	input := strings.Join([]string{
+		"line_item_line_item_type,line_item_usage_start_date,line_item_product_code,line_item_usage_type,line_item_operation,line_item_usage_amount,product_region_code,product",
+		`Usage,2026-01-01T00:00:00Z,AmazonRDS,APN1-InstanceUsage:db.r6g.large,CreateDBInstance:0014,2,ap-northeast-1,"{""database_engine"":""PostgreSQL""}"`,
+		`DiscountedUsage,2026-01-01T01:00:00Z,AmazonRDS,APN1-InstanceUsage:db.r6g.large,CreateDBInstance:0014,1,ap-northeast-1,"{""database_engine"":""PostgreSQL""}"`,
+		`Usage,2026-01-01T00:00:00Z,AmazonRDS,APN1-Multi-AZUsage:db.m6i.large,CreateDBInstance:0004,1,ap-northeast-1,"{""database_engine"":""Oracle"",""database_edition"":""Enterprise"",""license_model"":""Bring your own license""}"`,
+		`Usage,2026-01-01T01:00:00Z,AmazonRDS,APN1-Multi-AZUsage:db.m6i.large,CreateDBInstance:0004,1,ap-northeast-1,"{""database_engine"":""Oracle"",""database_edition"":""Enterprise"",""license_model"":""Bring your own license""}"`,
+		`Usage,2026-01-01T00:00:00Z,AmazonElastiCache,APN1-NodeUsage:cache.r7g.large,CreateCacheCluster:0002,3,ap-northeast-1,"{}"`,
+		`Usage,2026-01-01T01:00:00Z,AmazonElastiCache,APN1-NodeUsage:cache.r7g.large,CreateCacheCluster:0002,3,ap-northeast-1,"{}"`,
+		`Usage,2026-01-01T00:00:00Z,AmazonEC2,APN1-BoxUsage:m7g.large,RunInstances,1,ap-northeast-1,"{}"`,
+		`Usage,2026-01-01T01:00:00Z,AmazonEC2,APN1-BoxUsage:m7g.large,RunInstances,1,ap-northeast-1,"{}"`,
+		`Tax,2026-01-01T00:00:00Z,AmazonRDS,APN1-InstanceUsage:db.r6g.large,CreateDBInstance:0014,5,ap-northeast-1,"{}"`,
+		`Usage,2026-01-01T00:00:00Z,AmazonRDS,APN1-RDS:GP3-Storage,CreateDBInstance:0014,100,ap-northeast-1,"{}"`,
+	}, "\n")
+
+	usage := newCURUsage()
+	if err := usage.read(strings.NewReader(input), time.Time{}, time.Time{}, "mysql", "redis"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !usage.first.Equal(start) || !usage.last.Equal(start.Add(time.Hour)) {
+		t.Fatalf("unexpected window: %v - %v", usage.first, usage.last)
+	}
+
+	// 平均ではdb.r6g.largeは1.5台のため1台
+	expected := []InstanceInfo{
+		{ServiceType: "ec2", InstanceType: "m7g.large", Count: 1, Description: "RunInstances", Region: "ap-northeast-1"},
+		{ServiceType: "elasticache", InstanceType: "cache.r7g.large", Count: 3, Description: "redis", Region: "ap-northeast-1"},
+		{ServiceType: "rds", InstanceType: "db.m6i.large", Count: 1, Description: "oracle-ee(byol)", MultiAz: true, Region: "ap-northeast-1"},
+		{ServiceType: "rds", InstanceType: "db.r6g.large", Count: 1, Description: "postgresql", Region: "ap-northeast-1"},
+	}
+	if got := usage.instances(start, 2, "average"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("average:\nexpected %+v\ngot      %+v", expected, got)
+	}
+
+	// 3時間目は使用量がないため最小は0台になる
+	if got := usage.instances(start, 3, "minimum"); len(got) != 0 {
+		t.Errorf("minimum over 3 hours: expected no instances, got %+v", got)
+	}
+}
+
+func TestCURFargateCommands(t *testing.T) {
+	input := strings.Join([]string{
+		"lineItem/UsageStartDate,lineItem/ProductCode,lineItem/UsageType,lineItem/UsageAmount,product/region",
+		"2026-01-01T00:00:00Z,AmazonECS,APN1-Fargate-ARM-vCPU-Hours:perCPU,2,ap-northeast-1",
+		"2026-01-01T00:00:00Z,AmazonECS,APN1-Fargate-ARM-GB-Hours,4,ap-northeast-1",
+		"2026-01-01T01:00:00Z,AmazonECS,APN1-Fargate-ARM-vCPU-Hours:perCPU,1,ap-northeast-1",
+		"2026-01-01T01:00:00Z,AmazonECS,APN1-Fargate-ARM-GB-Hours,2,ap-northeast-1",
+		// エフェメラルストレージとSpotの使用量は含めない
+		"2026-01-01T00:00:00Z,AmazonECS,APN1-Fargate-EphemeralStorage-GB-Hours,20,ap-northeast-1",
+		"2026-01-01T01:00:00Z,AmazonECS,APN1-SpotUsage-Fargate-vCPU-Hours:perCPU,4,ap-northeast-1",
+		"2026-01-01T01:00:00Z,AmazonECS,APN1-SpotUsage-Fargate-GB-Hours,8,ap-northeast-1",
+	}, "\n")
+
+	usage := newCURUsage()
+	if err := usage.read(strings.NewReader(input), time.Time{}, time.Time{}, "mysql", "redis"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"awsri compute-savings-plans fargate --region=ap-northeast-1 --vcpu-millicores-per-hour=1500 --memory-mb-per-hour=3072 --task-count=1 --architecture=arm"}
+	if got := usage.fargateCommands(usage.first, 2); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+// curParquetRow はCUR 2.0のParquetの列の一部
+type curParquetRow struct {
+	LineItemType   string            `parquet:"line_item_line_item_type"`
+	UsageStartDate time.Time         `parquet:"line_item_usage_start_date,timestamp(millisecond)"`
+	ProductCode    string            `parquet:"line_item_product_code"`
+	UsageType      string            `parquet:"line_item_usage_type"`
+	UsageAmount    float64           `parquet:"line_item_usage_amount"`
+	RegionCode     *string           `parquet:"product_region_code,optional"`
+	Product        map[string]string `parquet:"product"`
+}
+
+func TestCURUsageReadParquet(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	region := "ap-northeast-1"
+	postgres := map[string]string{"database_engine": "PostgreSQL"}
+	rows := []curParquetRow{
+		{"Usage", start, "AmazonRDS", "APN1-InstanceUsage:db.r6g.large", 2, &region, postgres},
+		{"DiscountedUsage", start.Add(time.Hour), "AmazonRDS", "APN1-InstanceUsage:db.r6g.large", 1, &region, postgres},
+		{"Tax", start, "AmazonRDS", "APN1-InstanceUsage:db.r6g.large", 5, &region, postgres},
+		// リージョンの列がない場合はproduct列のregionを使用する
+		{"Usage", start, "AmazonElastiCache", "APN1-NodeUsage:cache.r7g.large", 3, nil, map[string]string{"region": region}},
+		{"Usage", start.Add(time.Hour), "AmazonElastiCache", "APN1-NodeUsage:cache.r7g.large", 3, nil, map[string]string{"region": region}},
+	}
+	var buf bytes.Buffer
+	writer := parquet.NewGenericWriter[curParquetRow](&buf)
+	if _, err := writer.Write(rows); err != nil {
+		t.Fatalf("failed to write Parquet: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to write Parquet: %v", err)
+	}
+
+	usage := newCURUsage()
+	if err := usage.readParquet(bytes.NewReader(buf.Bytes()), int64(buf.Len()), time.Time{}, time.Time{}, "mysql", "redis"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !usage.first.Equal(start) || !usage.last.Equal(start.Add(time.Hour)) {
+		t.Fatalf("unexpected window: %v - %v", usage.first, usage.last)
+	}
+
+	expected := []InstanceInfo{
+		{ServiceType: "elasticache", InstanceType: "cache.r7g.large", Count: 3, Description: "redis", Region: "ap-northeast-1"},
+		{ServiceType: "rds", InstanceType: "db.r6g.large", Count: 1, Description: "postgresql", Region: "ap-northeast-1"},
+	}
+	if got := usage.instances(start, 2, "average"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v\ngot      %+v", expected, got)
+	}
+}
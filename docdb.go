@@ -0,0 +1,31 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+type DocDBOption struct {
+	InstanceClass string `required:"" help:"Instance class (e.g. db.r6g.large)"`
+	Region        string `default:"ap-northeast-1" help:"AWS region"`
+}
+
+type DocDBCommand struct {
+	opts DocDBOption
+}
+
+func NewDocDBCommand(opts DocDBOption) *DocDBCommand {
+	return &DocDBCommand{opts: opts}
+}
+
+func (c *DocDBCommand) Run(ctx context.Context) error {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(c.opts.Region))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config, %v", err)
+	}
+
+	// DocumentDBのリザーブドインスタンスはRDSのオファリングAPIで販売される
+	return renderRDSCompatibleTable(ctx, cfg, docDBEngine, addInstanceTypePrefix("docdb", c.opts.InstanceClass))
+}
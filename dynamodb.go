@@ -0,0 +1,222 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+)
+
+// dynamoDBReservationBlock はリザーブドキャパシティの購入単位（ユニット数）
+const dynamoDBReservationBlock = 100
+
+// dynamoDBReservedCapacityNote はリザーブドキャパシティの対象外となるテーブルについての注記
+const dynamoDBReservedCapacityNote = "Note: Tables in on-demand capacity mode and tables of the Standard-IA table class cannot use reserved capacity."
+
+// dynamoDBCapacityGroups はキャパシティの種類とPricing APIのgroup属性の対応
+var dynamoDBCapacityGroups = map[string]string{
+	"read":  "DDB-ReadUnits",
+	"write": "DDB-WriteUnits",
+}
+
+type DynamoDBOption struct {
+	Tables []string `name:"table" required:"" help:"Provisioned capacity per table in format: table-name:rcu:wcu (repeatable)"`
+	Region string   `default:"ap-northeast-1" help:"AWS region"`
+}
+
+type DynamoDBCommand struct {
+	opts DynamoDBOption
+}
+
+// DynamoDBCapacityPricing はプロビジョンドキャパシティの料金
+type DynamoDBCapacityPricing struct {
+	OnDemandHourly  float64         // 1ユニット1時間あたりのオンデマンド料金
+	ReservedUpfront map[int]float64 // 期間（年）ごとの100ユニットあたりの前払い料金
+	ReservedHourly  map[int]float64 // 期間（年）ごとの100ユニット1時間あたりの料金
+}
+
+// dynamoDBTable はテーブルごとのプロビジョンドキャパシティ
+type dynamoDBTable struct {
+	name string
+	rcu  int
+	wcu  int
+}
+
+func NewDynamoDBCommand(opts DynamoDBOption) *DynamoDBCommand {
+	return &DynamoDBCommand{opts: opts}
+}
+
+func (c *DynamoDBCommand) Run(ctx context.Context) error {
+	tables, err := parseDynamoDBTables(c.opts.Tables)
+	if err != nil {
+		return err
+	}
+
+	// テーブルのキャパシティを合計し、購入単位に切り捨てる
+	rcu, wcu := 0, 0
+	for _, table := range tables {
+		rcu += table.rcu
+		wcu += table.wcu
+	}
+	reservedRCU := dynamoDBReservedUnits(rcu)
+	reservedWCU := dynamoDBReservedUnits(wcu)
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(c.opts.Region))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config, %v", err)
+	}
+
+	// 読み込み・書き込みキャパシティの料金をAPI経由で取得
+	readPricing, err := getDynamoDBCapacityPricing(ctx, cfg, "read")
+	if err != nil {
+		return fmt.Errorf("failed to get read capacity price: %v", err)
+	}
+	writePricing, err := getDynamoDBCapacityPricing(ctx, cfg, "write")
+	if err != nil {
+		return fmt.Errorf("failed to get write capacity price: %v", err)
+	}
+
+	fmt.Printf("Provisioned capacity: %d RCU, %d WCU (reserved in %d-unit blocks: %d RCU, %d WCU)\n\n",
+		rcu, wcu, dynamoDBReservationBlock, reservedRCU, reservedWCU)
+
+	onDemandHourly := float64(rcu)*readPricing.OnDemandHourly + float64(wcu)*writePricing.OnDemandHourly
+	onDemandPrice := onDemandHourly * 24 * 30
+
+	if err := RenderReservedPriceTable(onDemandPrice, []string{"Reserved Capacity"}, func(duration int, _ string) (float64, float64, bool, error) {
+		readUpfront, readOk := readPricing.ReservedUpfront[duration]
+		writeUpfront, writeOk := writePricing.ReservedUpfront[duration]
+		if !readOk || !writeOk {
+			return 0, 0, false, nil
+		}
+		readBlocks := float64(reservedRCU / dynamoDBReservationBlock)
+		writeBlocks := float64(reservedWCU / dynamoDBReservationBlock)
+		fixedPrice := readBlocks*readUpfront + writeBlocks*writeUpfront
+
+		// 購入単位に満たない残りのキャパシティはオンデマンド料金のまま
+		hourly := readBlocks*readPricing.ReservedHourly[duration] + writeBlocks*writePricing.ReservedHourly[duration] +
+			float64(rcu-reservedRCU)*readPricing.OnDemandHourly + float64(wcu-reservedWCU)*writePricing.OnDemandHourly
+		return fixedPrice, hourly * 24 * 30, true, nil
+	}); err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println(dynamoDBReservedCapacityNote)
+	return nil
+}
+
+// parseDynamoDBTables はテーブルごとのキャパシティ（table-name:rcu:wcu）を解析する
+func parseDynamoDBTables(defs []string) ([]dynamoDBTable, error) {
+	var tables []dynamoDBTable
+	for _, def := range defs {
+		parts := strings.Split(def, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid table format: %s, expected format: table-name:rcu:wcu", def)
+		}
+		rcu, err := strconv.Atoi(parts[1])
+		if err != nil || rcu < 0 {
+			return nil, fmt.Errorf("invalid RCU in table: %s", parts[1])
+		}
+		wcu, err := strconv.Atoi(parts[2])
+		if err != nil || wcu < 0 {
+			return nil, fmt.Errorf("invalid WCU in table: %s", parts[2])
+		}
+		tables = append(tables, dynamoDBTable{name: parts[0], rcu: rcu, wcu: wcu})
+	}
+	return tables, nil
+}
+
+// dynamoDBReservedUnits はキャパシティを購入単位（100ユニット）に切り捨てる
+func dynamoDBReservedUnits(units int) int {
+	return units / dynamoDBReservationBlock * dynamoDBReservationBlock
+}
+
+// getDynamoDBCapacityPricing は読み込み（read）または書き込み（write）キャパシティの料金を取得する
+func getDynamoDBCapacityPricing(ctx context.Context, cfg aws.Config, capacityType string) (DynamoDBCapacityPricing, error) {
+	group, ok := dynamoDBCapacityGroups[capacityType]
+	if !ok {
+		return DynamoDBCapacityPricing{}, fmt.Errorf("unsupported capacity type: %s (must be read or write)", capacityType)
+	}
+
+	svc, region := newPricingClient(cfg)
+
+	// プロビジョンドキャパシティの料金を取得
+	filters := []types.Filter{
+		{
+			Field: aws.String("productFamily"),
+			Value: aws.String("Provisioned IOPS"),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			Field: aws.String("group"),
+			Value: aws.String(group),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+			Type:  types.FilterTypeTermMatch,
+		},
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonDynamoDB"),
+		Filters:     filters,
+	}
+
+	result, err := svc.GetProducts(ctx, input)
+	if err != nil {
+		return DynamoDBCapacityPricing{}, err
+	}
+	if len(result.PriceList) == 0 {
+		return DynamoDBCapacityPricing{}, fmt.Errorf("no pricing information found")
+	}
+
+	return parseDynamoDBCapacityPricing(result.PriceList[0])
+}
+
+// parseDynamoDBCapacityPricing は価格表からオンデマンドとリザーブドキャパシティの料金を取り出す
+func parseDynamoDBCapacityPricing(priceListEntry string) (DynamoDBCapacityPricing, error) {
+	product, err := parsePriceListProduct(priceListEntry)
+	if err != nil {
+		return DynamoDBCapacityPricing{}, err
+	}
+
+	capacityPricing := DynamoDBCapacityPricing{
+		ReservedUpfront: make(map[int]float64),
+		ReservedHourly:  make(map[int]float64),
+	}
+
+	// オンデマンド料金は無料利用枠（0ドル）の段階を除いた料金を使用する
+	for _, term := range product.Terms.OnDemand {
+		for _, dimension := range term.PriceDimensions {
+			if price := dimension.usd(); price > capacityPricing.OnDemandHourly {
+				capacityPricing.OnDemandHourly = price
+			}
+		}
+	}
+	if capacityPricing.OnDemandHourly == 0 {
+		return capacityPricing, fmt.Errorf("on-demand price not found in pricing data")
+	}
+
+	// リザーブドキャパシティの料金は100ユニット単位（前払い料金と時間単位の料金）
+	for _, term := range product.Terms.Reserved {
+		years, err := strconv.Atoi(strings.TrimSuffix(term.TermAttributes["LeaseContractLength"], "yr"))
+		if err != nil {
+			continue
+		}
+		for _, dimension := range term.PriceDimensions {
+			if dimension.Unit == "Quantity" {
+				capacityPricing.ReservedUpfront[years] = dimension.usd()
+			} else {
+				capacityPricing.ReservedHourly[years] = dimension.usd()
+			}
+		}
+	}
+
+	return capacityPricing, nil
+}
@@ -0,0 +1,91 @@
+package awsri
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDynamoDBCapacityPricing(t *testing.T) {
+	priceListEntry := `{
+		"product": {"productFamily": "Provisioned IOPS", "attributes": {"group": "DDB-WriteUnits", "regionCode": "us-east-1"}},
+		"terms": {
+			"OnDemand": {
+				"SKU.JRTCKXETXF": {
+					"priceDimensions": {
+						"SKU.JRTCKXETXF.1": {"unit": "WriteCapacityUnit-Hrs", "beginRange": "0", "endRange": "18600", "pricePerUnit": {"USD": "0.0000000000"}},
+						"SKU.JRTCKXETXF.2": {"unit": "WriteCapacityUnit-Hrs", "beginRange": "18600", "endRange": "Inf", "pricePerUnit": {"USD": "0.0006500000"}}
+					},
+					"termAttributes": {}
+				}
+			},
+			"Reserved": {
+				"SKU.ONE": {
+					"priceDimensions": {
+						"SKU.ONE.1": {"unit": "Quantity", "pricePerUnit": {"USD": "150"}},
+						"SKU.ONE.2": {"unit": "Hrs", "pricePerUnit": {"USD": "0.0128"}}
+					},
+					"termAttributes": {"LeaseContractLength": "1yr", "PurchaseOption": "Heavy Utilization"}
+				},
+				"SKU.THREE": {
+					"priceDimensions": {
+						"SKU.THREE.1": {"unit": "Quantity", "pricePerUnit": {"USD": "180"}},
+						"SKU.THREE.2": {"unit": "Hrs", "pricePerUnit": {"USD": "0.0081"}}
+					},
+					"termAttributes": {"LeaseContractLength": "3yr", "PurchaseOption": "Heavy Utilization"}
+				}
+			}
+		}
+	}`
+
+	pricing, err := parseDynamoDBCapacityPricing(priceListEntry)
+	if err != nil {
+		t.Fatalf("Failed to parse pricing: %v", err)
+	}
+	if pricing.OnDemandHourly != 0.00065 {
+		t.Errorf("Expected on-demand price 0.00065 (excluding free tier), got %v", pricing.OnDemandHourly)
+	}
+	if pricing.ReservedUpfront[1] != 150 || pricing.ReservedHourly[1] != 0.0128 {
+		t.Errorf("Unexpected 1y pricing: upfront=%v hourly=%v", pricing.ReservedUpfront[1], pricing.ReservedHourly[1])
+	}
+	if pricing.ReservedUpfront[3] != 180 || pricing.ReservedHourly[3] != 0.0081 {
+		t.Errorf("Unexpected 3y pricing: upfront=%v hourly=%v", pricing.ReservedUpfront[3], pricing.ReservedHourly[3])
+	}
+}
+
+func TestParseDynamoDBTables(t *testing.T) {
+	tables, err := parseDynamoDBTables([]string{"orders:1250:420", "users:80:10"})
+	if err != nil {
+		t.Fatalf("Failed to parse tables: %v", err)
+	}
+
+	rcu, wcu := 0, 0
+	for _, table := range tables {
+		rcu += table.rcu
+		wcu += table.wcu
+	}
+	if got := dynamoDBReservedUnits(rcu); got != 1300 {
+		t.Errorf("Expected 1330 RCU to be reserved as 1300, got %d", got)
+	}
+	if got := dynamoDBReservedUnits(wcu); got != 400 {
+		t.Errorf("Expected 430 WCU to be reserved as 400, got %d", got)
+	}
+
+	if _, err := parseDynamoDBTables([]string{"orders:1250"}); err == nil {
+		t.Error("Expected error for missing WCU")
+	}
+}
+
+func TestFilterDynamoDBOnDemandTables(t *testing.T) {
+	instances := []InstanceInfo{
+		{ServiceType: "dynamodb", InstanceType: "read", Count: 500, ResourceID: "orders", Status: "ACTIVE"},
+		{ServiceType: "dynamodb", InstanceType: "read", Count: 0, ResourceID: "events", Status: "on-demand"},
+	}
+
+	kept, skipped := filterSteadyInstances(instances, 0, time.Now())
+	if len(kept) != 1 || kept[0].ResourceID != "orders" {
+		t.Errorf("Expected only the provisioned table to be kept, got %+v", kept)
+	}
+	if len(skipped) != 1 || skipped[0].Reason != statusReasons["on-demand"] {
+		t.Errorf("Expected the on-demand table to be skipped with its reason, got %+v", skipped)
+	}
+}
@@ -3,6 +3,7 @@ package awsri
 import (
 	"context"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
@@ -21,6 +22,9 @@ type EC2Option struct {
 	Duration      int    `name:"duration" default:"1" help:"Duration in years (1 or 3)"`
 	PaymentOption string `name:"payment-option" default:"no-upfront" help:"Payment option (no-upfront, partial-upfront, all-upfront)"`
 	NoHeader      bool   `name:"no-header" help:"Do not output CSV header"`
+
+	EC2PlatformOption `embed:""`
+	ScheduleOption    `embed:""`
 }
 
 type EC2Command struct {
@@ -42,6 +46,17 @@ func (c *EC2Command) Run(ctx context.Context) error {
 		return fmt.Errorf("duration must be 1 or 3 years, got: %d", c.opts.Duration)
 	}
 
+	// Validate operating system, tenancy and license options
+	if _, err := c.opts.resolve(); err != nil {
+		return err
+	}
+
+	// Validate the running schedule (always running without one)
+	runningHours, err := c.opts.scheduledHours()
+	if err != nil {
+		return err
+	}
+
 	// Pricing API and Savings Plans API are only available in us-east-1
 	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
 	if err != nil {
@@ -68,15 +83,18 @@ func (c *EC2Command) Run(ctx context.Context) error {
 	hoursPerMonth := 720.0
 	spPurchaseAmount := hourlyCommitment * hoursPerMonth * 12.0 * float64(c.opts.Duration)
 
-	// Current cost (on-demand)
-	currentCostPerMonth := float64(c.opts.Count) * onDemandPrice * hoursPerMonth
+	// Current cost (on-demand), only for the scheduled running hours
+	currentCostPerMonth := float64(c.opts.Count) * onDemandPrice * runningHours
 
-	// Cost after purchase (Savings Plan)
+	// Cost after purchase (Savings Plan); the commitment is paid for every hour
 	spCostPerMonth := float64(c.opts.Count) * spPrice * hoursPerMonth
 
 	// Calculate savings amount and savings rate
 	savingsAmount := currentCostPerMonth - spCostPerMonth
 	savingsRate := (savingsAmount / currentCostPerMonth) * 100.0
+	if savingsAmount < 0 {
+		fmt.Fprintf(os.Stderr, "Note: at %.1f running hours/month the Savings Plan costs %.2f USD/month more than staying on-demand\n", runningHours, -savingsAmount)
+	}
 
 	// Output CSV
 	renderCSV(hourlyCommitment, spPurchaseAmount, currentCostPerMonth, spCostPerMonth, savingsAmount, savingsRate, c.opts.NoHeader)
@@ -86,6 +104,11 @@ func (c *EC2Command) Run(ctx context.Context) error {
 
 // getEC2OnDemandPrice retrieves EC2 on-demand pricing using the Pricing API
 func (c *EC2Command) getEC2OnDemandPrice(cfg aws.Config) (float64, error) {
+	platform, err := c.opts.resolve()
+	if err != nil {
+		return 0, err
+	}
+
 	svc := pricing.NewFromConfig(cfg)
 	location := mapRegionToLocation(c.opts.Region)
 
@@ -102,17 +125,28 @@ func (c *EC2Command) getEC2OnDemandPrice(cfg aws.Config) (float64, error) {
 		},
 		{
 			Field: aws.String("operatingSystem"),
-			Value: aws.String("Linux"),
+			Value: aws.String(platform.operatingSystem),
 			Type:  types.FilterTypeTermMatch,
 		},
 		{
 			Field: aws.String("tenancy"),
-			Value: aws.String("Shared"),
+			Value: aws.String(platform.tenancy),
 			Type:  types.FilterTypeTermMatch,
 		},
 		{
 			Field: aws.String("preInstalledSw"),
-			Value: aws.String("NA"),
+			Value: aws.String(platform.preInstalledSw),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			Field: aws.String("licenseModel"),
+			Value: aws.String(platform.licenseModel),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			// Exclude the prices of unused capacity reservations
+			Field: aws.String("capacitystatus"),
+			Value: aws.String("Used"),
 			Type:  types.FilterTypeTermMatch,
 		},
 	}
@@ -136,10 +170,8 @@ func (c *EC2Command) getEC2OnDemandPrice(cfg aws.Config) (float64, error) {
 	return extractOnDemandPriceFromResult(result.PriceList[0])
 }
 
-// getComputeSavingsPlanPrice retrieves EC2 Savings Plan pricing using the Savings Plans API
+// getComputeSavingsPlanPrice retrieves EC2 Compute Savings Plan pricing using the Savings Plans API
 func (c *EC2Command) getComputeSavingsPlanPrice(ctx context.Context, cfg aws.Config) (float64, error) {
-	svc := savingsplans.NewFromConfig(cfg)
-
 	// Get payment option from arguments
 	paymentOptionStr := c.opts.PaymentOption
 	if paymentOptionStr == "" {
@@ -152,14 +184,44 @@ func (c *EC2Command) getComputeSavingsPlanPrice(ctx context.Context, cfg aws.Con
 		return 0, err
 	}
 
-	paymentOption := savingsplansTypes.SavingsPlanPaymentOption(awsPaymentOption)
+	rate, found, err := c.getSavingsPlanRate(ctx, cfg, savingsplansTypes.SavingsPlanTypeCompute,
+		savingsplansTypes.SavingsPlanPaymentOption(awsPaymentOption), c.opts.Duration)
+	if err != nil {
+		return 0, err
+	}
+
+	// If not found with the specified payment option, try other options
+	if !found && paymentOptionStr == "no-upfront" {
+		rate, found, err = c.getSavingsPlanRate(ctx, cfg, savingsplansTypes.SavingsPlanTypeCompute,
+			savingsplansTypes.SavingsPlanPaymentOptionAllUpfront, c.opts.Duration)
+		if err != nil {
+			return 0, fmt.Errorf("failed to describe savings plans offering rates (all-upfront): %v", err)
+		}
+	}
+
+	if !found {
+		return 0, fmt.Errorf("Savings Plan price not found for instance type %s with duration %d years", c.opts.InstanceType, c.opts.Duration)
+	}
+
+	return rate, nil
+}
+
+// getSavingsPlanRate retrieves the hourly Savings Plan rate of the instance type for the plan type,
+// payment option and duration. It reports false when no matching rate is offered.
+func (c *EC2Command) getSavingsPlanRate(ctx context.Context, cfg aws.Config, planType savingsplansTypes.SavingsPlanType, paymentOption savingsplansTypes.SavingsPlanPaymentOption, duration int) (float64, bool, error) {
+	platform, err := c.opts.resolve()
+	if err != nil {
+		return 0, false, err
+	}
+
+	svc := savingsplans.NewFromConfig(cfg)
 
 	// Get Savings Plans Offering Rates
-	durationSeconds := int64(c.opts.Duration * 365 * 24 * 60 * 60) // Convert years to seconds
+	durationSeconds := int64(duration * 365 * 24 * 60 * 60) // Convert years to seconds
 
 	input := &savingsplans.DescribeSavingsPlansOfferingRatesInput{
 		SavingsPlanTypes: []savingsplansTypes.SavingsPlanType{
-			savingsplansTypes.SavingsPlanTypeCompute,
+			planType,
 		},
 		Products: []savingsplansTypes.SavingsPlanProductType{
 			savingsplansTypes.SavingsPlanProductTypeEc2,
@@ -183,35 +245,28 @@ func (c *EC2Command) getComputeSavingsPlanPrice(ctx context.Context, cfg aws.Con
 					c.opts.InstanceType,
 				},
 			},
+			{
+				Name: savingsplansTypes.SavingsPlanRateFilterAttributeProductDescription,
+				Values: []string{
+					platform.productDescription,
+				},
+			},
+			{
+				Name: savingsplansTypes.SavingsPlanRateFilterAttributeTenancy,
+				Values: []string{
+					platform.spTenancy,
+				},
+			},
 		},
 		MaxResults: 100,
 	}
 
 	result, err := svc.DescribeSavingsPlansOfferingRates(ctx, input)
 	if err != nil {
-		return 0, fmt.Errorf("failed to describe savings plans offering rates: %v", err)
-	}
-
-	if len(result.SearchResults) == 0 {
-		// If not found with the specified payment option, try other options
-		if paymentOptionStr == "no-upfront" {
-			input.SavingsPlanPaymentOptions = []savingsplansTypes.SavingsPlanPaymentOption{
-				savingsplansTypes.SavingsPlanPaymentOptionAllUpfront,
-			}
-			result, err = svc.DescribeSavingsPlansOfferingRates(context.TODO(), input)
-			if err != nil {
-				return 0, fmt.Errorf("failed to describe savings plans offering rates (all-upfront): %v", err)
-			}
-		}
-		if len(result.SearchResults) == 0 {
-			return 0, fmt.Errorf("no savings plans offering rates found for payment option: %s", paymentOptionStr)
-		}
+		return 0, false, fmt.Errorf("failed to describe savings plans offering rates: %v", err)
 	}
 
 	// Find offers that match duration and instance type
-	var matchedRate float64
-	found := false
-
 	for _, offering := range result.SearchResults {
 		// Check if duration matches
 		if offering.SavingsPlanOffering != nil && offering.SavingsPlanOffering.DurationSeconds != durationSeconds {
@@ -230,28 +285,9 @@ func (c *EC2Command) getComputeSavingsPlanPrice(ctx context.Context, cfg aws.Con
 			continue
 		}
 
-		// Check for Linux (exclude Windows)
-		if offering.UsageType != nil {
-			usageType := strings.ToLower(*offering.UsageType)
-			if strings.Contains(usageType, "windows") {
-				continue
-			}
-		}
-
-		// Also check from Properties
-		isWindows := false
-		for _, prop := range offering.Properties {
-			if prop.Name != nil && prop.Value != nil {
-				if *prop.Name == "usagetype" {
-					usageType := strings.ToLower(*prop.Value)
-					if strings.Contains(usageType, "windows") {
-						isWindows = true
-						break
-					}
-				}
-			}
-		}
-		if isWindows {
+		// Check if product description and tenancy match
+		if !savingsPlanPropertyMatches(offering.Properties, "productDescription", platform.productDescription) ||
+			!savingsPlanPropertyMatches(offering.Properties, "tenancy", platform.spTenancy) {
 			continue
 		}
 
@@ -259,18 +295,22 @@ func (c *EC2Command) getComputeSavingsPlanPrice(ctx context.Context, cfg aws.Con
 		if offering.Rate != nil {
 			rate, err := strconv.ParseFloat(*offering.Rate, 64)
 			if err == nil {
-				matchedRate = rate
-				found = true
-				break
+				return rate, true, nil
 			}
 		}
 	}
 
-	if !found {
-		return 0, fmt.Errorf("Savings Plan price not found for instance type %s with duration %d years", c.opts.InstanceType, c.opts.Duration)
-	}
+	return 0, false, nil
+}
 
-	return matchedRate, nil
+// savingsPlanPropertyMatches reports whether the property has the value, or is not present in Properties
+func savingsPlanPropertyMatches(properties []savingsplansTypes.SavingsPlanOfferingRateProperty, name, value string) bool {
+	for _, prop := range properties {
+		if prop.Name != nil && *prop.Name == name && prop.Value != nil {
+			return strings.EqualFold(*prop.Value, value)
+		}
+	}
+	return true
 }
 
 // getInstanceTypeFromProperties retrieves instance type from Properties
@@ -0,0 +1,258 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	savingsplansTypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+)
+
+// ec2CompareHeadings は比較表のヘッダー
+var ec2CompareHeadings = []string{
+	"Duration",
+	"Commitment",
+	"Payment Option",
+	"Flexibility",
+	"Upfront (USD)",
+	"Monthly (USD)",
+	"Yearly (USD)",
+	"Savings/Year",
+}
+
+type EC2CompareOption struct {
+	InstanceType string `required:"" help:"EC2 instance type (e.g. m6i.large)"`
+	Count        int    `default:"1" help:"Number of instances"`
+	Region       string `default:"ap-northeast-1" help:"AWS region"`
+
+	EC2PlatformOption `embed:""`
+}
+
+type EC2CompareCommand struct {
+	opts EC2CompareOption
+}
+
+// ec2SavingsPlanTypes は比較するSavings Plansの種類
+var ec2SavingsPlanTypes = []savingsplansTypes.SavingsPlanType{
+	savingsplansTypes.SavingsPlanTypeCompute,
+	savingsplansTypes.SavingsPlanTypeEc2Instance,
+}
+
+// ec2SavingsPlanRateKey はSavings Plansのレートを引くキー
+type ec2SavingsPlanRateKey struct {
+	duration      int
+	planType      savingsplansTypes.SavingsPlanType
+	paymentOption string
+}
+
+// ec2Commitment は比較表の1行分のコミットメントの料金
+type ec2Commitment struct {
+	name             string
+	paymentOption    string
+	flexibility      string
+	fixedPrice       float64
+	monthlyRecurring float64
+	available        bool
+}
+
+func NewEC2CompareCommand(opts EC2CompareOption) *EC2CompareCommand {
+	return &EC2CompareCommand{opts: opts}
+}
+
+func (c *EC2CompareCommand) Run(ctx context.Context) error {
+	if c.opts.Count <= 0 {
+		return fmt.Errorf("count must be positive, got: %d", c.opts.Count)
+	}
+
+	// OS・テナンシー・ライセンスのオプションを検証
+	platform, err := c.opts.resolve()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(c.opts.Region))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config, %v", err)
+	}
+
+	// Pricing APIとSavings Plans APIはus-east-1でのみ利用可能
+	usEast1Cfg := cfg.Copy()
+	usEast1Cfg.Region = "us-east-1"
+	ec2Command := NewEC2Command(EC2Option{Region: c.opts.Region, InstanceType: c.opts.InstanceType, Count: c.opts.Count, EC2PlatformOption: c.opts.EC2PlatformOption})
+
+	// オンデマンド料金をAPI経由で取得
+	onDemandHourly, err := ec2Command.getEC2OnDemandPrice(usEast1Cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get on-demand price: %v", err)
+	}
+	onDemandPrice := onDemandHourly * float64(c.opts.Count) * 24 * 30
+
+	// リザーブドインスタンスはリージョナルのオファリングと比較する
+	offerings, err := describeEC2Offerings(ctx, ec2.NewFromConfig(cfg), c.opts.InstanceType, platform)
+	if err != nil {
+		return err
+	}
+
+	// Savings Plansのレートは期間・種類・支払いオプションごとに取得する
+	savingsPlanRates := map[ec2SavingsPlanRateKey]float64{}
+	for _, duration := range Durations {
+		for _, planType := range ec2SavingsPlanTypes {
+			for _, offeringType := range ReservedOfferingTypes() {
+				rate, found, err := ec2Command.getSavingsPlanRate(ctx, usEast1Cfg, planType, savingsplansTypes.SavingsPlanPaymentOption(offeringType), duration)
+				if err != nil {
+					return err
+				}
+				if found {
+					savingsPlanRates[ec2SavingsPlanRateKey{duration: duration, planType: planType, paymentOption: offeringType}] = rate
+				}
+			}
+		}
+	}
+
+	table := NewTable(ec2CompareHeadings)
+	for _, row := range ec2CompareRows(onDemandPrice, c.opts.Count, c.opts.InstanceType, c.opts.Region, savingsPlanRates, offerings) {
+		table.Append(row)
+	}
+	table.Render()
+	return nil
+}
+
+// ec2CompareRows は期間ごとにオンデマンドとSavings Plans・リザーブドインスタンスの比較表の行を作る
+// savingsPlanRatesにないSavings Plansとofferingsにないリザーブドインスタンスは購入できないものとして表示する
+func ec2CompareRows(onDemandPrice float64, count int, instanceType string, region string, savingsPlanRates map[ec2SavingsPlanRateKey]float64, offerings []ec2Types.ReservedInstancesOffering) [][]string {
+	var rows [][]string
+	family := ec2InstanceFamily(instanceType)
+	for _, duration := range Durations {
+		durationMonths := DurationToMonths(duration)
+
+		rows = append(rows, []string{
+			fmt.Sprintf("%dy", duration),
+			"On-Demand",
+			"-",
+			"-",
+			"0",
+			fmt.Sprintf("%.1f", onDemandPrice),
+			fmt.Sprintf("%.1f", onDemandPrice*12),
+			"-",
+		})
+
+		var commitments []ec2Commitment
+		for _, planType := range ec2SavingsPlanTypes {
+			for _, offeringType := range ReservedOfferingTypes() {
+				rate, found := savingsPlanRates[ec2SavingsPlanRateKey{duration: duration, planType: planType, paymentOption: offeringType}]
+				commitment := ec2Commitment{
+					name:          ec2SavingsPlanName(planType),
+					paymentOption: offeringType,
+					flexibility:   ec2SavingsPlanFlexibility(planType, family, region),
+					available:     found,
+				}
+				if found {
+					commitment.fixedPrice, commitment.monthlyRecurring = savingsPlanCharges(rate*float64(count), offeringType, duration)
+				}
+				commitments = append(commitments, commitment)
+			}
+		}
+		for _, offeringClass := range ec2OfferingClasses {
+			for _, offeringType := range ReservedOfferingTypes() {
+				offering := findEC2Offering(offerings, duration, offeringClass, ec2Types.ScopeRegional, "", offeringType)
+				commitment := ec2Commitment{
+					name:          ec2ReservedInstanceName(offeringClass),
+					paymentOption: offeringType,
+					flexibility:   ec2ReservedInstanceFlexibility(offeringClass, family, region),
+					available:     offering != nil,
+				}
+				if offering != nil {
+					fixedPrice, monthlyRecurring := ec2OfferingCharges(*offering)
+					commitment.fixedPrice = fixedPrice * float64(count)
+					commitment.monthlyRecurring = monthlyRecurring * float64(count)
+				}
+				commitments = append(commitments, commitment)
+			}
+		}
+
+		for _, commitment := range commitments {
+			if !commitment.available {
+				rows = append(rows, []string{
+					fmt.Sprintf("%dy", duration),
+					commitment.name,
+					commitment.paymentOption,
+					commitment.flexibility,
+					"N/A", "N/A", "N/A", "N/A",
+				})
+				continue
+			}
+
+			effectiveYearly := CalculateEffectiveMonthly(commitment.fixedPrice, commitment.monthlyRecurring, durationMonths)
+			yearlySavings, savingsPercent := CalculateSavings(onDemandPrice, effectiveYearly)
+
+			rows = append(rows, []string{
+				fmt.Sprintf("%dy", duration),
+				commitment.name,
+				commitment.paymentOption,
+				commitment.flexibility,
+				fmt.Sprintf("%.1f", commitment.fixedPrice),
+				fmt.Sprintf("%.1f", commitment.monthlyRecurring),
+				fmt.Sprintf("%.1f", effectiveYearly),
+				fmt.Sprintf("%.1f (%.1f%%)", yearlySavings, savingsPercent),
+			})
+		}
+
+		if duration != Durations[len(Durations)-1] {
+			rows = append(rows, []string{"", "", "", "", "", "", "", ""})
+		}
+	}
+	return rows
+}
+
+// savingsPlanCharges は時間あたりのコミットメントから前払い料金と月額料金を返す
+// Partial Upfrontはコミットメントの半額を前払いする
+func savingsPlanCharges(hourlyCommitment float64, paymentOption string, duration int) (float64, float64) {
+	monthly := hourlyCommitment * 24 * 30
+	total := monthly * float64(DurationToMonths(duration))
+	switch paymentOption {
+	case "All Upfront":
+		return total, 0
+	case "Partial Upfront":
+		return total / 2, monthly / 2
+	default:
+		return 0, monthly
+	}
+}
+
+// ec2InstanceFamily はインスタンスタイプのファミリー（例: m6i.large -> m6i）を返す
+func ec2InstanceFamily(instanceType string) string {
+	return strings.SplitN(instanceType, ".", 2)[0]
+}
+
+func ec2SavingsPlanName(planType savingsplansTypes.SavingsPlanType) string {
+	if planType == savingsplansTypes.SavingsPlanTypeEc2Instance {
+		return "EC2 Instance SP"
+	}
+	return "Compute SP"
+}
+
+func ec2ReservedInstanceName(offeringClass ec2Types.OfferingClassType) string {
+	if offeringClass == ec2Types.OfferingClassTypeConvertible {
+		return "Convertible RI"
+	}
+	return "Standard RI"
+}
+
+// ec2SavingsPlanFlexibility はSavings Planが適用される範囲を返す
+func ec2SavingsPlanFlexibility(planType savingsplansTypes.SavingsPlanType, family string, region string) string {
+	if planType == savingsplansTypes.SavingsPlanTypeEc2Instance {
+		return fmt.Sprintf("Any size, OS, tenancy of %s in %s", family, region)
+	}
+	return "Any family, size, OS, tenancy, region; Fargate and Lambda"
+}
+
+// ec2ReservedInstanceFlexibility はリージョナルのリザーブドインスタンスが適用される範囲を返す
+func ec2ReservedInstanceFlexibility(offeringClass ec2Types.OfferingClassType, family string, region string) string {
+	if offeringClass == ec2Types.OfferingClassTypeConvertible {
+		return fmt.Sprintf("Any size of %s in %s; exchangeable for other families, OS, tenancy", family, region)
+	}
+	return fmt.Sprintf("Any size of %s in %s (Linux, shared tenancy only); sellable on Marketplace", family, region)
+}
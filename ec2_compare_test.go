@@ -0,0 +1,66 @@
+package awsri
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	savingsplansTypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+)
+
+func TestSavingsPlanCharges(t *testing.T) {
+	tests := []struct {
+		paymentOption   string
+		expectedUpfront float64
+		expectedMonthly float64
+	}{
+		{"No Upfront", 0, 720},
+		{"Partial Upfront", 4320, 360},
+		{"All Upfront", 8640, 0},
+	}
+
+	for _, tt := range tests {
+		upfront, monthly := savingsPlanCharges(1, tt.paymentOption, 1)
+		if upfront != tt.expectedUpfront || monthly != tt.expectedMonthly {
+			t.Errorf("%s: expected upfront=%v monthly=%v, got upfront=%v monthly=%v",
+				tt.paymentOption, tt.expectedUpfront, tt.expectedMonthly, upfront, monthly)
+		}
+	}
+}
+
+func TestEC2CompareRows(t *testing.T) {
+	rates := map[ec2SavingsPlanRateKey]float64{
+		{duration: 1, planType: savingsplansTypes.SavingsPlanTypeCompute, paymentOption: "No Upfront"}: 0.05,
+	}
+	offerings := []ec2Types.ReservedInstancesOffering{
+		{Duration: aws.Int64(365 * 24 * 60 * 60), OfferingClass: ec2Types.OfferingClassTypeStandard, Scope: ec2Types.ScopeRegional,
+			OfferingType: ec2Types.OfferingTypeValuesAllUpfront, FixedPrice: aws.Float32(500)},
+	}
+
+	rows := ec2CompareRows(200, 2, "m6i.large", "ap-northeast-1", rates, offerings)
+	// 期間ごとにオンデマンド1行、Savings Plans 2種類×3行、リザーブドインスタンス2クラス×3行と区切り線
+	if len(rows) != 27 {
+		t.Fatalf("Expected 27 rows, got %d", len(rows))
+	}
+
+	tests := []struct {
+		index    int
+		expected []string
+	}{
+		{0, []string{"1y", "On-Demand", "-", "-", "0", "200.0", "2400.0", "-"}},
+		{1, []string{"1y", "Compute SP", "No Upfront", "Any family, size, OS, tenancy, region; Fargate and Lambda", "0.0", "72.0", "864.0", "1536.0 (64.0%)"}},
+		{2, []string{"1y", "Compute SP", "Partial Upfront", "Any family, size, OS, tenancy, region; Fargate and Lambda", "N/A", "N/A", "N/A", "N/A"}},
+		{4, []string{"1y", "EC2 Instance SP", "No Upfront", "Any size, OS, tenancy of m6i in ap-northeast-1", "N/A", "N/A", "N/A", "N/A"}},
+		{9, []string{"1y", "Standard RI", "All Upfront", "Any size of m6i in ap-northeast-1 (Linux, shared tenancy only); sellable on Marketplace", "1000.0", "0.0", "1000.0", "1400.0 (58.3%)"}},
+		{12, []string{"1y", "Convertible RI", "All Upfront", "Any size of m6i in ap-northeast-1; exchangeable for other families, OS, tenancy", "N/A", "N/A", "N/A", "N/A"}},
+		{13, []string{"", "", "", "", "", "", "", ""}},
+		{14, []string{"3y", "On-Demand", "-", "-", "0", "200.0", "2400.0", "-"}},
+		{23, []string{"3y", "Standard RI", "All Upfront", "Any size of m6i in ap-northeast-1 (Linux, shared tenancy only); sellable on Marketplace", "N/A", "N/A", "N/A", "N/A"}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(rows[tt.index], tt.expected) {
+			t.Errorf("Row %d: expected %v, got %v", tt.index, tt.expected, rows[tt.index])
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package awsri
+
+import (
+	"fmt"
+
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// EC2PlatformOption はOS・テナンシー・ライセンスでEC2の料金を選ぶオプション
+type EC2PlatformOption struct {
+	OS             string `name:"os" default:"linux" help:"Operating system (linux, windows, rhel, rhel-ha, suse, ubuntu-pro)"`
+	Tenancy        string `name:"tenancy" default:"shared" help:"Tenancy (shared, dedicated)"`
+	LicenseModel   string `name:"license-model" default:"license-included" help:"License model (license-included, byol)"`
+	PreInstalledSw string `name:"preinstalled-sw" default:"none" help:"Pre-installed software (none, sql-web, sql-std, sql-ent)"`
+}
+
+// ec2Platform はPricing API・Savings Plans API・EC2 APIの形式に変換したプラットフォーム
+type ec2Platform struct {
+	// Pricing APIの属性
+	operatingSystem string
+	tenancy         string
+	licenseModel    string
+	preInstalledSw  string
+
+	// productDescription はSavings Plansのレートとリザーブドインスタンスのオファリングで共通（例: "Windows with SQL Server Web"）
+	productDescription string
+	// spTenancy はSavings Plansのレートのテナンシー
+	spTenancy string
+	// riTenancy はリザーブドインスタンスのオファリングのテナンシー
+	riTenancy ec2Types.Tenancy
+}
+
+// ec2OperatingSystems は--osの値からPricing APIのOSとproductDescriptionへの対応
+var ec2OperatingSystems = map[string]struct {
+	operatingSystem    string
+	productDescription string
+}{
+	"linux":      {"Linux", "Linux/UNIX"},
+	"windows":    {"Windows", "Windows"},
+	"rhel":       {"RHEL", "Red Hat Enterprise Linux"},
+	"rhel-ha":    {"Red Hat Enterprise Linux with HA", "Red Hat Enterprise Linux with HA"},
+	"suse":       {"SUSE", "SUSE Linux"},
+	"ubuntu-pro": {"Ubuntu Pro", "Ubuntu Pro"},
+}
+
+// ec2PreInstalledSoftware は--preinstalled-swの値からPricing APIの値とproductDescriptionの接尾辞への対応
+var ec2PreInstalledSoftware = map[string]struct {
+	preInstalledSw string
+	suffix         string
+}{
+	"none":    {"NA", ""},
+	"sql-web": {"SQL Web", " with SQL Server Web"},
+	"sql-std": {"SQL Std", " with SQL Server Standard"},
+	"sql-ent": {"SQL Ent", " with SQL Server Enterprise"},
+}
+
+// resolve はオプションを検証し、各APIの形式に変換する
+func (o EC2PlatformOption) resolve() (ec2Platform, error) {
+	// CLIのパーサーではなくコードで構造体を作った場合は既定値を使う
+	osName, tenancy, licenseModel, preInstalledSw := o.OS, o.Tenancy, o.LicenseModel, o.PreInstalledSw
+	if osName == "" {
+		osName = "linux"
+	}
+	if tenancy == "" {
+		tenancy = "shared"
+	}
+	if licenseModel == "" {
+		licenseModel = "license-included"
+	}
+	if preInstalledSw == "" {
+		preInstalledSw = "none"
+	}
+
+	operatingSystem, ok := ec2OperatingSystems[osName]
+	if !ok {
+		return ec2Platform{}, fmt.Errorf("invalid os: %s (must be one of: linux, windows, rhel, rhel-ha, suse, ubuntu-pro)", osName)
+	}
+	software, ok := ec2PreInstalledSoftware[preInstalledSw]
+	if !ok {
+		return ec2Platform{}, fmt.Errorf("invalid preinstalled-sw: %s (must be one of: none, sql-web, sql-std, sql-ent)", preInstalledSw)
+	}
+	if preInstalledSw != "none" && osName != "linux" && osName != "windows" {
+		return ec2Platform{}, fmt.Errorf("preinstalled-sw %s is only available with linux or windows", preInstalledSw)
+	}
+
+	platform := ec2Platform{
+		operatingSystem:    operatingSystem.operatingSystem,
+		preInstalledSw:     software.preInstalledSw,
+		productDescription: operatingSystem.productDescription + software.suffix,
+		licenseModel:       "No License required",
+	}
+	// Linux with SQL Serverは"/UNIX"の接尾辞なしで掲載されている
+	if osName == "linux" && preInstalledSw != "none" {
+		platform.productDescription = "Linux" + software.suffix
+	}
+
+	switch licenseModel {
+	case "license-included":
+	case "byol":
+		if osName != "windows" || preInstalledSw != "none" {
+			return ec2Platform{}, fmt.Errorf("license-model byol is only available with windows and no pre-installed software")
+		}
+		platform.licenseModel = "Bring your own license"
+		platform.productDescription = "Windows BYOL"
+	default:
+		return ec2Platform{}, fmt.Errorf("invalid license-model: %s (must be one of: license-included, byol)", licenseModel)
+	}
+
+	switch tenancy {
+	case "shared":
+		platform.tenancy, platform.spTenancy, platform.riTenancy = "Shared", "shared", ec2Types.TenancyDefault
+	case "dedicated":
+		platform.tenancy, platform.spTenancy, platform.riTenancy = "Dedicated", "dedicated", ec2Types.TenancyDedicated
+	default:
+		return ec2Platform{}, fmt.Errorf("invalid tenancy: %s (must be one of: shared, dedicated)", tenancy)
+	}
+
+	return platform, nil
+}
+
+// ec2PlatformFromDescription はマニフェストのEC2の行のプラットフォームのオプションを返す
+// descriptionはgenerateが書き出すプラットフォームの詳細（例: "Windows with SQL Server Web"）か、
+// curが書き出す使用オペレーション（例: "RunInstances:0002"）
+func ec2PlatformFromDescription(description string) (EC2PlatformOption, bool) {
+	if platform, ok := curEC2Platforms[description]; ok {
+		return platform, true
+	}
+	for osName := range ec2OperatingSystems {
+		for preInstalledSw := range ec2PreInstalledSoftware {
+			for _, licenseModel := range []string{"license-included", "byol"} {
+				option := EC2PlatformOption{OS: osName, LicenseModel: licenseModel, PreInstalledSw: preInstalledSw}
+				platform, err := option.resolve()
+				if err == nil && platform.productDescription == description {
+					return option, true
+				}
+			}
+		}
+	}
+	return EC2PlatformOption{}, false
+}
@@ -0,0 +1,79 @@
+package awsri
+
+import (
+	"testing"
+
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestEC2PlatformOptionResolve(t *testing.T) {
+	tests := []struct {
+		opts                       EC2PlatformOption
+		expectedOperatingSystem    string
+		expectedPreInstalledSw     string
+		expectedLicenseModel       string
+		expectedProductDescription string
+	}{
+		{EC2PlatformOption{}, "Linux", "NA", "No License required", "Linux/UNIX"},
+		{EC2PlatformOption{OS: "windows", PreInstalledSw: "sql-web"}, "Windows", "SQL Web", "No License required", "Windows with SQL Server Web"},
+		{EC2PlatformOption{OS: "linux", PreInstalledSw: "sql-ent"}, "Linux", "SQL Ent", "No License required", "Linux with SQL Server Enterprise"},
+		{EC2PlatformOption{OS: "windows", LicenseModel: "byol"}, "Windows", "NA", "Bring your own license", "Windows BYOL"},
+		{EC2PlatformOption{OS: "rhel"}, "RHEL", "NA", "No License required", "Red Hat Enterprise Linux"},
+	}
+
+	for _, tt := range tests {
+		platform, err := tt.opts.resolve()
+		if err != nil {
+			t.Errorf("%+v: unexpected error: %v", tt.opts, err)
+			continue
+		}
+		if platform.operatingSystem != tt.expectedOperatingSystem || platform.preInstalledSw != tt.expectedPreInstalledSw ||
+			platform.licenseModel != tt.expectedLicenseModel || platform.productDescription != tt.expectedProductDescription {
+			t.Errorf("%+v: unexpected platform %+v", tt.opts, platform)
+		}
+	}
+
+	platform, err := EC2PlatformOption{Tenancy: "dedicated"}.resolve()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if platform.tenancy != "Dedicated" || platform.spTenancy != "dedicated" || platform.riTenancy != ec2Types.TenancyDedicated {
+		t.Errorf("unexpected tenancy %+v", platform)
+	}
+
+	// 組み合わせられないオプションはエラーになる
+	for _, opts := range []EC2PlatformOption{
+		{OS: "macos"},
+		{Tenancy: "host"},
+		{OS: "linux", LicenseModel: "byol"},
+		{OS: "suse", PreInstalledSw: "sql-std"},
+	} {
+		if _, err := opts.resolve(); err == nil {
+			t.Errorf("%+v: expected an error", opts)
+		}
+	}
+}
+
+func TestEC2PlatformFromDescription(t *testing.T) {
+	// generateが書き出すプラットフォームの詳細とcurが書き出すオペレーションのどちらも変換する
+	for description, expected := range map[string]string{
+		"Linux/UNIX":                  "Linux/UNIX",
+		"Windows with SQL Server Web": "Windows with SQL Server Web",
+		"Windows BYOL":                "Windows BYOL",
+		"RunInstances:0002":           "Windows",
+	} {
+		option, ok := ec2PlatformFromDescription(description)
+		if !ok {
+			t.Errorf("%s: expected a platform", description)
+			continue
+		}
+		platform, err := option.resolve()
+		if err != nil || platform.productDescription != expected {
+			t.Errorf("%s: expected %s, got %+v (%v)", description, expected, platform, err)
+		}
+	}
+
+	if _, ok := ec2PlatformFromDescription("Unknown OS"); ok {
+		t.Error("Expected no platform for an unknown description")
+	}
+}
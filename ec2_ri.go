@@ -0,0 +1,173 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// ec2OfferingClasses は表示するリザーブドインスタンスのクラス
+var ec2OfferingClasses = []ec2Types.OfferingClassType{
+	ec2Types.OfferingClassTypeStandard,
+	ec2Types.OfferingClassTypeConvertible,
+}
+
+// ec2OfferingScopes は表示するリザーブドインスタンスのスコープ
+var ec2OfferingScopes = []ec2Types.Scope{
+	ec2Types.ScopeRegional,
+	ec2Types.ScopeAvailabilityZone,
+}
+
+type EC2RIOption struct {
+	InstanceType     string `required:"" help:"EC2 instance type (e.g. m5.large)"`
+	AvailabilityZone string `name:"availability-zone" help:"Availability zone for zonal reservations (default: any zone in the region)"`
+	Region           string `default:"ap-northeast-1" help:"AWS region"`
+
+	EC2PlatformOption `embed:""`
+	ScheduleOption    `embed:""`
+}
+
+type EC2RICommand struct {
+	opts EC2RIOption
+}
+
+func NewEC2RICommand(opts EC2RIOption) *EC2RICommand {
+	return &EC2RICommand{opts: opts}
+}
+
+func (c *EC2RICommand) Run(ctx context.Context) error {
+	// OS・テナンシー・ライセンスのオプションを検証
+	platform, err := c.opts.resolve()
+	if err != nil {
+		return err
+	}
+	// 稼働スケジュールを検証（指定がなければ常時稼働）
+	hours, err := c.opts.scheduledHours()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(c.opts.Region))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config, %v", err)
+	}
+
+	// オンデマンド料金をAPI経由で取得（Pricing APIはus-east-1でのみ利用可能）
+	pricingCfg := cfg.Copy()
+	pricingCfg.Region = "us-east-1"
+	ec2Command := NewEC2Command(EC2Option{Region: c.opts.Region, InstanceType: c.opts.InstanceType, EC2PlatformOption: c.opts.EC2PlatformOption})
+	onDemandHourly, err := ec2Command.getEC2OnDemandPrice(pricingCfg)
+	if err != nil {
+		return fmt.Errorf("failed to get on-demand price: %v", err)
+	}
+	// オンデマンド料金は稼働時間分のみ、リザーブドインスタンスは全時間分を支払う
+	onDemandPrice := scheduledOnDemand(onDemandHourly*24*30, hours)
+	if note := c.opts.scheduleNote(hours); note != "" {
+		fmt.Println(note)
+	}
+
+	// 期間・クラス・スコープ・支払いオプションの組み合わせはまとめて取得してから絞り込む
+	offerings, err := describeEC2Offerings(ctx, ec2.NewFromConfig(cfg), c.opts.InstanceType, platform)
+	if err != nil {
+		return err
+	}
+
+	// 表示名からクラス・スコープ・支払いオプションを引けるようにする
+	type ec2OfferingKey struct {
+		offeringClass ec2Types.OfferingClassType
+		scope         ec2Types.Scope
+		offeringType  string
+	}
+	var labels []string
+	keys := map[string]ec2OfferingKey{}
+	for _, offeringClass := range ec2OfferingClasses {
+		for _, scope := range ec2OfferingScopes {
+			for _, offeringType := range ReservedOfferingTypes() {
+				label := ec2OfferingLabel(offeringClass, scope, offeringType)
+				labels = append(labels, label)
+				keys[label] = ec2OfferingKey{offeringClass: offeringClass, scope: scope, offeringType: offeringType}
+			}
+		}
+	}
+
+	return RenderReservedPriceTable(onDemandPrice, labels, func(duration int, label string) (float64, float64, bool, error) {
+		key := keys[label]
+		offering := findEC2Offering(offerings, duration, key.offeringClass, key.scope, c.opts.AvailabilityZone, key.offeringType)
+		if offering == nil {
+			return 0, 0, false, nil
+		}
+		fixedPrice, monthlyRecurring := ec2OfferingCharges(*offering)
+		return fixedPrice, monthlyRecurring, true, nil
+	})
+}
+
+// describeEC2Offerings は指定したインスタンスタイプ・プラットフォーム（OS・テナンシー）のオファリングを取得する
+func describeEC2Offerings(ctx context.Context, client ec2.DescribeReservedInstancesOfferingsAPIClient, instanceType string, platform ec2Platform) ([]ec2Types.ReservedInstancesOffering, error) {
+	var offerings []ec2Types.ReservedInstancesOffering
+	paginator := ec2.NewDescribeReservedInstancesOfferingsPaginator(client, &ec2.DescribeReservedInstancesOfferingsInput{
+		InstanceType:       ec2Types.InstanceType(instanceType),
+		ProductDescription: ec2Types.RIProductDescription(platform.productDescription),
+		InstanceTenancy:    platform.riTenancy,
+		IncludeMarketplace: aws.Bool(false),
+		MaxResults:         aws.Int32(100),
+	})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		offerings = append(offerings, result.ReservedInstancesOfferings...)
+	}
+	return offerings, nil
+}
+
+// findEC2Offering は期間（年）・クラス・スコープ・オファリングタイプに一致するオファリングを返す
+// ゾーナルのオファリングはavailabilityZoneが指定されていればそのゾーンのものに限る
+func findEC2Offering(offerings []ec2Types.ReservedInstancesOffering, duration int, offeringClass ec2Types.OfferingClassType, scope ec2Types.Scope, availabilityZone string, offeringType string) *ec2Types.ReservedInstancesOffering {
+	// EC2のオファリングの期間は秒単位
+	durationSeconds := int64(duration * 365 * 24 * 60 * 60)
+
+	for i, offering := range offerings {
+		if aws.ToInt64(offering.Duration) != durationSeconds ||
+			offering.OfferingClass != offeringClass ||
+			offering.Scope != scope ||
+			string(offering.OfferingType) != offeringType {
+			continue
+		}
+		if scope == ec2Types.ScopeAvailabilityZone && availabilityZone != "" && aws.ToString(offering.AvailabilityZone) != availabilityZone {
+			continue
+		}
+		return &offerings[i]
+	}
+	return nil
+}
+
+// ec2OfferingCharges はオファリングの前払い料金と月額料金を返す
+func ec2OfferingCharges(offering ec2Types.ReservedInstancesOffering) (float64, float64) {
+	// 時間単位の料金はRecurringChargesに含まれる（含まれない場合はUsagePriceを使用）
+	hourly := 0.0
+	for _, charge := range offering.RecurringCharges {
+		hourly += aws.ToFloat64(charge.Amount)
+	}
+	if len(offering.RecurringCharges) == 0 {
+		hourly = float64(aws.ToFloat32(offering.UsagePrice))
+	}
+	return float64(aws.ToFloat32(offering.FixedPrice)), hourly * 24 * 30
+}
+
+// ec2OfferingLabel は表に表示するオファリング名（例: Standard No Upfront (Regional)）を返す
+func ec2OfferingLabel(offeringClass ec2Types.OfferingClassType, scope ec2Types.Scope, offeringType string) string {
+	className := "Standard"
+	if offeringClass == ec2Types.OfferingClassTypeConvertible {
+		className = "Convertible"
+	}
+	scopeName := "Regional"
+	if scope == ec2Types.ScopeAvailabilityZone {
+		scopeName = "Zonal"
+	}
+	return fmt.Sprintf("%s %s (%s)", className, offeringType, scopeName)
+}
@@ -0,0 +1,52 @@
+package awsri
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestFindEC2Offering(t *testing.T) {
+	oneYear := aws.Int64(365 * 24 * 60 * 60)
+	offerings := []ec2Types.ReservedInstancesOffering{
+		{Duration: oneYear, OfferingClass: ec2Types.OfferingClassTypeStandard, Scope: ec2Types.ScopeRegional,
+			OfferingType: ec2Types.OfferingTypeValuesAllUpfront, FixedPrice: aws.Float32(500)},
+		{Duration: oneYear, OfferingClass: ec2Types.OfferingClassTypeConvertible, Scope: ec2Types.ScopeRegional,
+			OfferingType: ec2Types.OfferingTypeValuesAllUpfront, FixedPrice: aws.Float32(700)},
+		{Duration: oneYear, OfferingClass: ec2Types.OfferingClassTypeStandard, Scope: ec2Types.ScopeAvailabilityZone,
+			AvailabilityZone: aws.String("ap-northeast-1a"), OfferingType: ec2Types.OfferingTypeValuesNoUpfront,
+			RecurringCharges: []ec2Types.RecurringCharge{{Amount: aws.Float64(0.25), Frequency: ec2Types.RecurringChargeFrequencyHourly}}},
+		{Duration: oneYear, OfferingClass: ec2Types.OfferingClassTypeStandard, Scope: ec2Types.ScopeAvailabilityZone,
+			AvailabilityZone: aws.String("ap-northeast-1c"), OfferingType: ec2Types.OfferingTypeValuesNoUpfront,
+			RecurringCharges: []ec2Types.RecurringCharge{{Amount: aws.Float64(0.5), Frequency: ec2Types.RecurringChargeFrequencyHourly}}},
+	}
+
+	offering := findEC2Offering(offerings, 1, ec2Types.OfferingClassTypeConvertible, ec2Types.ScopeRegional, "", "All Upfront")
+	if offering == nil || aws.ToFloat32(offering.FixedPrice) != 700 {
+		t.Errorf("Expected the convertible All Upfront offering, got %+v", offering)
+	}
+
+	// ゾーンを指定した場合はそのゾーンのオファリングを使用する
+	offering = findEC2Offering(offerings, 1, ec2Types.OfferingClassTypeStandard, ec2Types.ScopeAvailabilityZone, "ap-northeast-1c", "No Upfront")
+	if offering == nil {
+		t.Fatal("Expected the zonal No Upfront offering in ap-northeast-1c")
+	}
+	if fixedPrice, monthly := ec2OfferingCharges(*offering); fixedPrice != 0 || monthly != 360 {
+		t.Errorf("Unexpected charges: upfront=%v monthly=%v", fixedPrice, monthly)
+	}
+
+	if offering := findEC2Offering(offerings, 1, ec2Types.OfferingClassTypeStandard, ec2Types.ScopeRegional, "", "No Upfront"); offering != nil {
+		t.Errorf("Expected no regional No Upfront offering, got %+v", offering)
+	}
+	if offering := findEC2Offering(offerings, 3, ec2Types.OfferingClassTypeStandard, ec2Types.ScopeRegional, "", "All Upfront"); offering != nil {
+		t.Errorf("Expected no 3y offering, got %+v", offering)
+	}
+}
+
+func TestEC2OfferingLabel(t *testing.T) {
+	expected := "Convertible Partial Upfront (Zonal)"
+	if got := ec2OfferingLabel(ec2Types.OfferingClassTypeConvertible, ec2Types.ScopeAvailabilityZone, "Partial Upfront"); got != expected {
+		t.Errorf("Expected: %s, Got: %s", expected, got)
+	}
+}
@@ -14,8 +14,15 @@ import (
 )
 
 type ElasticacheOption struct {
-	CacheNodeType      string `required:"" help:"Cache node type"`
-	ProductDescription string `required:"" help:"Product description"`
+	CacheNodeType      string   `help:"Cache node type (required unless --fleet is given)"`
+	ProductDescription string   `required:"" help:"Product description"`
+	Region             string   `default:"ap-northeast-1" help:"AWS region"`
+	Fleet              []string `name:"fleet" help:"Running nodes in format: node-type:count (repeatable); shows normalized units and a purchase in the smallest size"`
+	Reserved           []string `name:"reserved" help:"Existing reserved nodes in format: node-type:count (repeatable), applied to --fleet in normalized units"`
+	Duration           int      `name:"duration" default:"1" help:"Duration in years (1 or 3) of the purchase proposed for --fleet"`
+	OfferingType       string   `name:"offering-type" default:"Partial Upfront" help:"Offering type (No Upfront, Partial Upfront, All Upfront) of the purchase proposed for --fleet"`
+
+	ScheduleOption `embed:""`
 }
 
 type ElasticacheCommand struct {
@@ -27,19 +34,43 @@ func NewElastiCacheCommand(opts ElasticacheOption) *ElasticacheCommand {
 }
 
 func (c *ElasticacheCommand) Run(ctx context.Context) error {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion("ap-northeast-1"))
+	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(c.opts.Region))
 	if err != nil {
 		return fmt.Errorf("unable to load SDK config, %v", err)
 	}
 
+	// ノードの構成が指定された場合は正規化ユニットで集計する
+	if len(c.opts.Fleet) > 0 {
+		return c.runFleet(ctx, cfg)
+	}
+	if c.opts.CacheNodeType == "" {
+		return fmt.Errorf("--cache-node-type or --fleet is required")
+	}
+
+	return c.renderPriceTable(cfg, c.opts.CacheNodeType)
+}
+
+// renderPriceTable はノードタイプのオンデマンドとリザーブドノードの料金表を表示する
+func (c *ElasticacheCommand) renderPriceTable(cfg aws.Config, cacheNodeType string) error {
+	// 稼働スケジュールを検証（指定がなければ常時稼働）
+	hours, err := c.opts.scheduledHours()
+	if err != nil {
+		return err
+	}
+
 	tableRenderer := NewTableRenderer()
 	svc := elasticache.NewFromConfig(cfg)
 
 	// オンデマンド料金をAPI経由で取得
-	onDemandPrice, err := c.getElastiCacheOnDemandPrice(cfg, c.opts.CacheNodeType, c.opts.ProductDescription)
+	onDemandPrice, err := c.getElastiCacheOnDemandPrice(cfg, cacheNodeType, c.opts.ProductDescription)
 	if err != nil {
 		return fmt.Errorf("failed to get on-demand price: %v", err)
 	}
+	// オンデマンド料金は稼働時間分のみ、リザーブドノードは全時間分を支払う
+	onDemandPrice = scheduledOnDemand(onDemandPrice, hours)
+	if note := c.opts.scheduleNote(hours); note != "" {
+		fmt.Println(note)
+	}
 
 	for _, duration := range Durations {
 		durationMonths := DurationToMonths(duration)
@@ -53,7 +84,7 @@ func (c *ElasticacheCommand) Run(ctx context.Context) error {
 			params := &elasticache.DescribeReservedCacheNodesOfferingsInput{
 				Duration:           aws.String(strconv.Itoa(duration)),
 				OfferingType:       aws.String(offeringType),
-				CacheNodeType:      aws.String(c.opts.CacheNodeType),
+				CacheNodeType:      aws.String(cacheNodeType),
 				ProductDescription: aws.String(c.opts.ProductDescription),
 			}
 			o, err := svc.DescribeReservedCacheNodesOfferings(context.TODO(), params)
@@ -96,10 +127,7 @@ func (c *ElasticacheCommand) Run(ctx context.Context) error {
 }
 
 func (c *ElasticacheCommand) getElastiCacheOnDemandPrice(cfg aws.Config, cacheNodeType string, productDescription string) (float64, error) {
-	// Pricing APIはus-east-1でのみ利用可能
-	pricingCfg := cfg.Copy()
-	pricingCfg.Region = "us-east-1"
-	svc := pricing.NewFromConfig(pricingCfg)
+	svc, region := newPricingClient(cfg)
 
 	// ElastiCacheのオンデマンド料金を取得
 	filters := []types.Filter{
@@ -115,7 +143,7 @@ func (c *ElasticacheCommand) getElastiCacheOnDemandPrice(cfg aws.Config, cacheNo
 		},
 		{
 			Field: aws.String("regionCode"),
-			Value: aws.String("ap-northeast-1"),
+			Value: aws.String(region),
 			Type:  types.FilterTypeTermMatch,
 		},
 	}
@@ -132,3 +160,17 @@ func (c *ElasticacheCommand) getElastiCacheOnDemandPrice(cfg aws.Config, cacheNo
 
 	return extractPriceFromResult(result)
 }
+
+// cacheOfferingExists はノードタイプ・productDescription・期間・オファリングタイプのオファリングがあるかを返す
+func cacheOfferingExists(ctx context.Context, svc *elasticache.Client, cacheNodeType string, productDescription string, duration int, offeringType string) (bool, error) {
+	o, err := svc.DescribeReservedCacheNodesOfferings(ctx, &elasticache.DescribeReservedCacheNodesOfferingsInput{
+		Duration:           aws.String(strconv.Itoa(duration)),
+		OfferingType:       aws.String(offeringType),
+		CacheNodeType:      aws.String(cacheNodeType),
+		ProductDescription: aws.String(productDescription),
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(o.ReservedCacheNodesOfferings) > 0, nil
+}
@@ -0,0 +1,158 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+)
+
+// cacheFleetHeadings は正規化ユニットの集計表の見出し
+var cacheFleetHeadings = []string{"Family", "Fleet Units", "Reserved Units", "Uncovered Units", "Purchase"}
+
+// cacheFleetPurchase はファミリーごとの購入の提案
+type cacheFleetPurchase struct {
+	family         string
+	fleetUnits     float64
+	reservedUnits  float64
+	uncoveredUnits float64
+	instanceType   string // 購入するノードタイプ（最小のサイズ）
+	count          int
+}
+
+// runFleet はサイズの異なるノードを正規化ユニットに換算し、既存のリザーブドノードを適用した残りを最小のサイズで購入する提案を表示する
+// リザーブドノードはファミリー内のサイズ変更後も正規化ユニットで適用されるため、サイズ変更後の適用状況も同じ表で確認できる
+func (c *ElasticacheCommand) runFleet(ctx context.Context, cfg aws.Config) error {
+	if !isCacheSizeFlexible(c.opts.ProductDescription) {
+		return fmt.Errorf("--fleet is only available for size-flexible engines (redis, valkey), got: %s", c.opts.ProductDescription)
+	}
+
+	fleet, err := parseCacheNodes(c.opts.Fleet, c.opts.ProductDescription, c.opts.Region)
+	if err != nil {
+		return err
+	}
+	reservedNodes, err := parseCacheNodes(c.opts.Reserved, c.opts.ProductDescription, c.opts.Region)
+	if err != nil {
+		return err
+	}
+
+	pools, rest := normalizeFleet(fleet, c.opts.Region)
+	if len(rest) > 0 {
+		return fmt.Errorf("invalid node type for normalization: %s", rest[0].InstanceType)
+	}
+
+	// 既存のリザーブドノードをファミリーごとの正規化ユニットに換算
+	reservedUnits := make(map[string]float64)
+	var reservations []Reservation
+	for _, node := range reservedNodes {
+		family, units, ok := cacheNormalizationUnits(node.InstanceType)
+		if !ok {
+			return fmt.Errorf("invalid node type for normalization: %s", node.InstanceType)
+		}
+		reservedUnits[family] += units * float64(node.Count)
+		reservations = append(reservations, Reservation{
+			ServiceType:  node.ServiceType,
+			InstanceType: node.InstanceType,
+			Count:        node.Count,
+			Description:  node.Description,
+			Region:       node.Region,
+		})
+	}
+
+	svc := elasticache.NewFromConfig(cfg)
+	var purchases []cacheFleetPurchase
+	for _, pool := range pools {
+		purchase := cacheFleetPurchase{
+			family:        pool.family,
+			fleetUnits:    pool.units,
+			reservedUnits: reservedUnits[pool.family],
+		}
+		if purchase.reservedUnits < purchase.fleetUnits {
+			purchase.uncoveredUnits = purchase.fleetUnits - purchase.reservedUnits
+		}
+		if purchase.uncoveredUnits > 0 {
+			size, err := smallestOfferingSize(pool, func(instanceType string) (bool, error) {
+				return cacheOfferingExists(ctx, svc, instanceType, c.opts.ProductDescription, c.opts.Duration, c.opts.OfferingType)
+			})
+			if err != nil {
+				return err
+			}
+			purchase.instanceType = pool.instanceType(size)
+			purchase.count = pool.count(size, purchase.uncoveredUnits)
+		}
+		purchases = append(purchases, purchase)
+	}
+
+	renderCacheFleetTable(purchases)
+
+	// リザーブドノードを適用した後に残るノード
+	uncovered := subtractReservations(fleet, reservations)
+	if len(uncovered) == 0 {
+		fmt.Println("\nAll nodes are covered by the reserved nodes.")
+	} else {
+		fmt.Println("\nNodes not covered by the reserved nodes:")
+		for _, node := range uncovered {
+			fmt.Printf("  %s x %d\n", node.InstanceType, node.Count)
+		}
+	}
+
+	// 購入するノードタイプの料金表
+	for _, purchase := range purchases {
+		if purchase.count == 0 {
+			continue
+		}
+		fmt.Printf("\n%s (%d nodes):\n", purchase.instanceType, purchase.count)
+		if err := c.renderPriceTable(cfg, purchase.instanceType); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseCacheNodes はnode-type:count形式のノードを解析する
+func parseCacheNodes(defs []string, description string, region string) ([]InstanceInfo, error) {
+	var nodes []InstanceInfo
+	for _, def := range defs {
+		parts := strings.Split(def, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid node format: %s, expected format: node-type:count", def)
+		}
+		count, err := strconv.Atoi(parts[1])
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("invalid count in node: %s", parts[1])
+		}
+		nodes = append(nodes, InstanceInfo{
+			ServiceType:  "elasticache",
+			InstanceType: addInstanceTypePrefix("elasticache", parts[0]),
+			Count:        count,
+			Description:  description,
+			Region:       region,
+		})
+	}
+	return nodes, nil
+}
+
+// renderCacheFleetTable はファミリーごとの正規化ユニットと購入の提案を表示する
+func renderCacheFleetTable(purchases []cacheFleetPurchase) {
+	table := NewTable(cacheFleetHeadings)
+
+	for _, purchase := range purchases {
+		buy := "-"
+		if purchase.count > 0 {
+			buy = fmt.Sprintf("%d x %s", purchase.count, purchase.instanceType)
+		}
+		table.Append([]string{
+			purchase.family,
+			strconv.FormatFloat(purchase.fleetUnits, 'f', -1, 64),
+			strconv.FormatFloat(purchase.reservedUnits, 'f', -1, 64),
+			strconv.FormatFloat(purchase.uncoveredUnits, 'f', -1, 64),
+			buy,
+		})
+	}
+
+	table.Render()
+}
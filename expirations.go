@@ -0,0 +1,663 @@
+package awsri
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	opensearchTypes "github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans"
+	savingsplansTypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+)
+
+// expirationHeadings は有効期限の一覧表の見出し
+var expirationHeadings = []string{"Service", "ID", "Instance", "Count", "Region", "Offering", "End", "Days Left", "Renewal Upfront (USD)", "Renewal Monthly (USD)"}
+
+type ExpirationsOption struct {
+	Region   string   `name:"region" default:"ap-northeast-1" help:"AWS region"`
+	Regions  []string `name:"regions" sep:"," help:"Comma-separated regions to scan (e.g. ap-northeast-1,us-east-1), or 'all' for every commercial region (default: --region only)"`
+	Services []string `name:"services" sep:"," default:"rds,elasticache,ec2,opensearch,savings-plans" help:"Comma-separated reservations to list (rds, elasticache, ec2, opensearch, savings-plans)"`
+	Days     int      `name:"days" default:"30" help:"Flag reservations and Savings Plans ending within this many days and look up their renewal prices"`
+	ICS      string   `name:"ics" help:"Write the expiry dates to an iCalendar (.ics) file"`
+	Format   string   `name:"format" default:"table" help:"Output format (table, csv, json)"`
+}
+
+type ExpirationsCommand struct {
+	opts ExpirationsOption
+
+	// テストでスタブに差し替えられるようにAPIクライアントの生成処理と更新料金の取得処理を保持する
+	newClients            func(cfg aws.Config) expirationClients
+	newSavingsPlansClient func(cfg aws.Config) savingsPlansDescribeAPI
+	renewal               func(ctx context.Context, expiration Expiration) (float64, float64, error)
+}
+
+// expirationClients はリージョンごとのリザベーションの取得に使うAPIクライアント
+type expirationClients struct {
+	rds         rds.DescribeReservedDBInstancesAPIClient
+	elasticache elasticache.DescribeReservedCacheNodesAPIClient
+	ec2         ec2ReservedInstancesAPI
+	opensearch  openSearchReservedInstancesAPI
+}
+
+// ec2ReservedInstancesAPI はEC2のリザーブドインスタンスの取得に使うAPIのサブセット
+type ec2ReservedInstancesAPI interface {
+	DescribeReservedInstances(ctx context.Context, params *ec2.DescribeReservedInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeReservedInstancesOutput, error)
+}
+
+// openSearchReservedInstancesAPI はOpenSearchのリザーブドインスタンスの取得に使うAPIのサブセット
+type openSearchReservedInstancesAPI interface {
+	DescribeReservedInstances(ctx context.Context, params *opensearch.DescribeReservedInstancesInput, optFns ...func(*opensearch.Options)) (*opensearch.DescribeReservedInstancesOutput, error)
+}
+
+// savingsPlansDescribeAPI はSavings Plansの取得に使うAPIのサブセット
+type savingsPlansDescribeAPI interface {
+	DescribeSavingsPlans(ctx context.Context, params *savingsplans.DescribeSavingsPlansInput, optFns ...func(*savingsplans.Options)) (*savingsplans.DescribeSavingsPlansOutput, error)
+}
+
+// Expiration は有効なリザベーションまたはSavings Plansとその終了日
+type Expiration struct {
+	Service        string    `json:"service"` // rds, elasticache, ec2, opensearch, savings-plans
+	ID             string    `json:"id"`
+	InstanceType   string    `json:"instance_type"` // Savings Plansの場合はプランの種類（例: Compute）
+	Description    string    `json:"description,omitempty"`
+	MultiAz        bool      `json:"multi_az,omitempty"`
+	Count          int       `json:"count,omitempty"`
+	Region         string    `json:"region,omitempty"`
+	OfferingType   string    `json:"offering_type"`
+	Duration       int       `json:"duration"` // 年
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	DaysLeft       int       `json:"days_left"`
+	Expiring       bool      `json:"expiring"`
+	RenewalUpfront *float64  `json:"renewal_upfront,omitempty"` // 今日のオファリングで更新した場合の前払い料金（台数分）
+	RenewalMonthly *float64  `json:"renewal_monthly,omitempty"` // 同上の月額料金（台数分）
+
+	// EC2のオファリングの検索に使う
+	offeringClass    ec2Types.OfferingClassType
+	scope            ec2Types.Scope
+	availabilityZone string
+	tenancy          ec2Types.Tenancy
+	// Savings Plansの前払い料金と時間あたりの継続支払い（USD）
+	upfrontPayment   float64
+	recurringPayment float64
+}
+
+func NewExpirationsCommand(opts ExpirationsOption) *ExpirationsCommand {
+	c := &ExpirationsCommand{
+		opts: opts,
+		newClients: func(cfg aws.Config) expirationClients {
+			return expirationClients{
+				rds:         rds.NewFromConfig(cfg),
+				elasticache: elasticache.NewFromConfig(cfg),
+				ec2:         ec2.NewFromConfig(cfg),
+				opensearch:  opensearch.NewFromConfig(cfg),
+			}
+		},
+		newSavingsPlansClient: func(cfg aws.Config) savingsPlansDescribeAPI {
+			return savingsplans.NewFromConfig(cfg)
+		},
+	}
+	c.renewal = c.renewalPrice
+	return c
+}
+
+func (c *ExpirationsCommand) Run(ctx context.Context) error {
+	if c.opts.Format != "table" && c.opts.Format != "csv" && c.opts.Format != "json" {
+		return fmt.Errorf("unsupported output format: %s", c.opts.Format)
+	}
+	for _, service := range c.opts.Services {
+		switch strings.TrimSpace(service) {
+		case "rds", "elasticache", "ec2", "opensearch", "savings-plans":
+		default:
+			return fmt.Errorf("unsupported service: %s (must be one of: rds, elasticache, ec2, opensearch, savings-plans)", service)
+		}
+	}
+
+	regions := NewGenerateCommand(GenerateOption{Region: c.opts.Region, Regions: c.opts.Regions}).targetRegions()
+	expirations, err := c.list(ctx, regions, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if c.opts.ICS != "" {
+		if err := os.WriteFile(c.opts.ICS, []byte(renderExpirationsICS(expirations, c.opts.Days, time.Now())), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", c.opts.ICS, err)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d expiry dates to %s\n", len(expirations), c.opts.ICS)
+	}
+
+	switch c.opts.Format {
+	case "csv":
+		return renderExpirationCSV(expirations)
+	case "json":
+		jsonData, err := json.MarshalIndent(expirations, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+	default:
+		renderExpirationTable(expirations, c.opts.Days)
+	}
+	return nil
+}
+
+// list は対象リージョンの有効なリザベーションとSavings Plansを終了日の順に返す
+// --days以内に終了するものは今日のオファリングでの更新料金も取得する
+func (c *ExpirationsCommand) list(ctx context.Context, regions []string, now time.Time) ([]Expiration, error) {
+	services := make(map[string]bool)
+	for _, service := range c.opts.Services {
+		services[strings.TrimSpace(service)] = true
+	}
+
+	var expirations []Expiration
+	for _, region := range regions {
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+		if err != nil {
+			return nil, fmt.Errorf("unable to load SDK config: %w", err)
+		}
+		clients := c.newClients(cfg)
+
+		if services["rds"] {
+			found, err := rdsExpirations(ctx, clients.rds, region)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get RDS reservations in %s: %w", region, err)
+			}
+			expirations = append(expirations, found...)
+		}
+		if services["elasticache"] {
+			found, err := elastiCacheExpirations(ctx, clients.elasticache, region)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get ElastiCache reservations in %s: %w", region, err)
+			}
+			expirations = append(expirations, found...)
+		}
+		if services["ec2"] {
+			found, err := ec2Expirations(ctx, clients.ec2, region)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get EC2 reservations in %s: %w", region, err)
+			}
+			expirations = append(expirations, found...)
+		}
+		if services["opensearch"] {
+			found, err := openSearchExpirations(ctx, clients.opensearch, region)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get OpenSearch reservations in %s: %w", region, err)
+			}
+			expirations = append(expirations, found...)
+		}
+	}
+
+	if services["savings-plans"] {
+		// Savings Plans APIはus-east-1でのみ利用可能
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to load SDK config: %w", err)
+		}
+		found, err := savingsPlansExpirations(ctx, c.newSavingsPlansClient(cfg))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Savings Plans: %w", err)
+		}
+		expirations = append(expirations, found...)
+	}
+
+	sort.SliceStable(expirations, func(i, j int) bool {
+		return expirations[i].End.Before(expirations[j].End)
+	})
+
+	for i := range expirations {
+		expiration := &expirations[i]
+		expiration.DaysLeft = int(expiration.End.Sub(now).Hours() / 24)
+		expiration.Expiring = expiration.DaysLeft <= c.opts.Days
+		if !expiration.Expiring {
+			continue
+		}
+		upfront, monthly, err := c.renewal(ctx, *expiration)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to get renewal price for %s %s: %v\n", expiration.Service, expiration.ID, err)
+			continue
+		}
+		expiration.RenewalUpfront = aws.Float64(upfront)
+		expiration.RenewalMonthly = aws.Float64(monthly)
+	}
+
+	return expirations, nil
+}
+
+// rdsExpirations は有効なリザーブドDBインスタンスを返す
+func rdsExpirations(ctx context.Context, client rds.DescribeReservedDBInstancesAPIClient, region string) ([]Expiration, error) {
+	var expirations []Expiration
+	paginator := rds.NewDescribeReservedDBInstancesPaginator(client, &rds.DescribeReservedDBInstancesInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, ri := range result.ReservedDBInstances {
+			if aws.ToString(ri.State) != "active" {
+				continue
+			}
+			start := aws.ToTime(ri.StartTime)
+			duration := time.Duration(aws.ToInt32(ri.Duration)) * time.Second
+			expirations = append(expirations, Expiration{
+				Service:      "rds",
+				ID:           aws.ToString(ri.ReservedDBInstanceId),
+				InstanceType: aws.ToString(ri.DBInstanceClass),
+				Description:  aws.ToString(ri.ProductDescription),
+				MultiAz:      aws.ToBool(ri.MultiAZ),
+				Count:        int(aws.ToInt32(ri.DBInstanceCount)),
+				Region:       region,
+				OfferingType: aws.ToString(ri.OfferingType),
+				Duration:     durationYears(duration),
+				Start:        start,
+				End:          start.Add(duration),
+			})
+		}
+	}
+	return expirations, nil
+}
+
+// elastiCacheExpirations は有効なリザーブドキャッシュノードを返す
+func elastiCacheExpirations(ctx context.Context, client elasticache.DescribeReservedCacheNodesAPIClient, region string) ([]Expiration, error) {
+	var expirations []Expiration
+	paginator := elasticache.NewDescribeReservedCacheNodesPaginator(client, &elasticache.DescribeReservedCacheNodesInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range result.ReservedCacheNodes {
+			if aws.ToString(node.State) != "active" {
+				continue
+			}
+			start := aws.ToTime(node.StartTime)
+			duration := time.Duration(aws.ToInt32(node.Duration)) * time.Second
+			expirations = append(expirations, Expiration{
+				Service:      "elasticache",
+				ID:           aws.ToString(node.ReservedCacheNodeId),
+				InstanceType: aws.ToString(node.CacheNodeType),
+				Description:  aws.ToString(node.ProductDescription),
+				Count:        int(aws.ToInt32(node.CacheNodeCount)),
+				Region:       region,
+				OfferingType: aws.ToString(node.OfferingType),
+				Duration:     durationYears(duration),
+				Start:        start,
+				End:          start.Add(duration),
+			})
+		}
+	}
+	return expirations, nil
+}
+
+// ec2Expirations は有効なEC2のリザーブドインスタンスを返す
+func ec2Expirations(ctx context.Context, client ec2ReservedInstancesAPI, region string) ([]Expiration, error) {
+	// DescribeReservedInstancesはページングされない
+	result, err := client.DescribeReservedInstances(ctx, &ec2.DescribeReservedInstancesInput{
+		Filters: []ec2Types.Filter{{Name: aws.String("state"), Values: []string{string(ec2Types.ReservedInstanceStateActive)}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var expirations []Expiration
+	for _, ri := range result.ReservedInstances {
+		if ri.State != ec2Types.ReservedInstanceStateActive {
+			continue
+		}
+		expirations = append(expirations, Expiration{
+			Service:          "ec2",
+			ID:               aws.ToString(ri.ReservedInstancesId),
+			InstanceType:     string(ri.InstanceType),
+			Description:      string(ri.ProductDescription),
+			Count:            int(aws.ToInt32(ri.InstanceCount)),
+			Region:           region,
+			OfferingType:     string(ri.OfferingType),
+			Duration:         durationYears(time.Duration(aws.ToInt64(ri.Duration)) * time.Second),
+			Start:            aws.ToTime(ri.Start),
+			End:              aws.ToTime(ri.End),
+			offeringClass:    ri.OfferingClass,
+			scope:            ri.Scope,
+			availabilityZone: aws.ToString(ri.AvailabilityZone),
+			tenancy:          ri.InstanceTenancy,
+		})
+	}
+	return expirations, nil
+}
+
+// openSearchExpirations は有効なOpenSearchのリザーブドインスタンスを返す
+func openSearchExpirations(ctx context.Context, client openSearchReservedInstancesAPI, region string) ([]Expiration, error) {
+	var expirations []Expiration
+	input := &opensearch.DescribeReservedInstancesInput{}
+	for {
+		result, err := client.DescribeReservedInstances(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, ri := range result.ReservedInstances {
+			if !strings.EqualFold(aws.ToString(ri.State), "active") {
+				continue
+			}
+			start := aws.ToTime(ri.StartTime)
+			duration := time.Duration(ri.Duration) * time.Second
+			expirations = append(expirations, Expiration{
+				Service:      "opensearch",
+				ID:           aws.ToString(ri.ReservedInstanceId),
+				InstanceType: string(ri.InstanceType),
+				Count:        int(ri.InstanceCount),
+				Region:       region,
+				OfferingType: openSearchOfferingType(ri.PaymentOption),
+				Duration:     durationYears(duration),
+				Start:        start,
+				End:          start.Add(duration),
+			})
+		}
+		if result.NextToken == nil {
+			return expirations, nil
+		}
+		input.NextToken = result.NextToken
+	}
+}
+
+// savingsPlansExpirations は有効なSavings Plansを返す
+func savingsPlansExpirations(ctx context.Context, client savingsPlansDescribeAPI) ([]Expiration, error) {
+	var expirations []Expiration
+	input := &savingsplans.DescribeSavingsPlansInput{
+		States: []savingsplansTypes.SavingsPlanState{savingsplansTypes.SavingsPlanStateActive},
+	}
+	for {
+		result, err := client.DescribeSavingsPlans(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, plan := range result.SavingsPlans {
+			start, err := time.Parse(time.RFC3339, aws.ToString(plan.Start))
+			if err != nil {
+				return nil, fmt.Errorf("invalid start of Savings Plan %s: %w", aws.ToString(plan.SavingsPlanId), err)
+			}
+			end, err := time.Parse(time.RFC3339, aws.ToString(plan.End))
+			if err != nil {
+				return nil, fmt.Errorf("invalid end of Savings Plan %s: %w", aws.ToString(plan.SavingsPlanId), err)
+			}
+			upfrontPayment, _ := strconv.ParseFloat(aws.ToString(plan.UpfrontPaymentAmount), 64)
+			recurringPayment, _ := strconv.ParseFloat(aws.ToString(plan.RecurringPaymentAmount), 64)
+			expirations = append(expirations, Expiration{
+				Service:          "savings-plans",
+				ID:               aws.ToString(plan.SavingsPlanId),
+				InstanceType:     savingsPlanTypeName(plan),
+				Description:      fmt.Sprintf("%s USD/hour", aws.ToString(plan.Commitment)),
+				Region:           aws.ToString(plan.Region),
+				OfferingType:     string(plan.PaymentOption),
+				Duration:         durationYears(time.Duration(plan.TermDurationInSeconds) * time.Second),
+				Start:            start,
+				End:              end,
+				upfrontPayment:   upfrontPayment,
+				recurringPayment: recurringPayment,
+			})
+		}
+		if result.NextToken == nil || aws.ToString(result.NextToken) == "" {
+			return expirations, nil
+		}
+		input.NextToken = result.NextToken
+	}
+}
+
+// renewalPrice は今日のオファリングで同じ条件のまま更新した場合の前払い料金と月額料金（台数分）を返す
+func (c *ExpirationsCommand) renewalPrice(ctx context.Context, expiration Expiration) (float64, float64, error) {
+	count := float64(expiration.Count)
+
+	if expiration.Service == "savings-plans" {
+		// Savings Plansのオファリングの価格はコミットメントそのものため、同じ前払い料金と継続支払いで更新する
+		return expiration.upfrontPayment, expiration.recurringPayment * 24 * 30, nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(expiration.Region))
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to load SDK config: %w", err)
+	}
+	instance := InstanceInfo{
+		ServiceType:  expiration.Service,
+		InstanceType: expiration.InstanceType,
+		Description:  expiration.Description,
+		MultiAz:      expiration.MultiAz,
+		Count:        expiration.Count,
+		Region:       expiration.Region,
+	}
+	total := NewTotalCommand(TotalOption{Duration: expiration.Duration, OfferingType: expiration.OfferingType})
+
+	var upfront, monthly float64
+	switch expiration.Service {
+	case "rds":
+		upfront, monthly, _, err = total.calculateRDSPrice(ctx, cfg, instance)
+	case "elasticache":
+		upfront, monthly, _, err = total.calculateElastiCachePrice(ctx, cfg, instance)
+	case "opensearch":
+		upfront, monthly, _, err = total.calculateOpenSearchPrice(ctx, cfg, instance)
+	case "ec2":
+		upfront, monthly, err = ec2RenewalPrice(ctx, ec2.NewFromConfig(cfg), expiration)
+	default:
+		err = fmt.Errorf("unsupported service type: %s", expiration.Service)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return upfront * count, monthly * count, nil
+}
+
+// ec2RenewalPrice はEC2のリザーブドインスタンスと同じクラス・スコープ・オファリングタイプのオファリングの料金を返す
+func ec2RenewalPrice(ctx context.Context, client ec2.DescribeReservedInstancesOfferingsAPIClient, expiration Expiration) (float64, float64, error) {
+	// リザーブドインスタンスのproductDescriptionにはVPCの表記が付く場合がある（例: Linux/UNIX (Amazon VPC)）
+	description := strings.TrimSuffix(expiration.Description, " (Amazon VPC)")
+	option, ok := ec2PlatformFromDescription(description)
+	if !ok {
+		return 0, 0, fmt.Errorf("unknown platform: %s", expiration.Description)
+	}
+	option.Tenancy = "shared"
+	if expiration.tenancy == ec2Types.TenancyDedicated {
+		option.Tenancy = "dedicated"
+	}
+	platform, err := option.resolve()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	offerings, err := describeEC2Offerings(ctx, client, expiration.InstanceType, platform)
+	if err != nil {
+		return 0, 0, err
+	}
+	offering := findEC2Offering(offerings, expiration.Duration, expiration.offeringClass, expiration.scope, expiration.availabilityZone, expiration.OfferingType)
+	if offering == nil {
+		return 0, 0, fmt.Errorf("no reserved instances offerings found for EC2 %s (%s)", expiration.InstanceType,
+			ec2OfferingLabel(expiration.offeringClass, expiration.scope, expiration.OfferingType))
+	}
+	upfront, monthly := ec2OfferingCharges(*offering)
+	return upfront, monthly, nil
+}
+
+// openSearchOfferingType はOpenSearchの支払いオプションをオファリングタイプ（例: Partial Upfront）に変換する
+func openSearchOfferingType(paymentOption opensearchTypes.ReservedInstancePaymentOption) string {
+	for offeringType, option := range openSearchPaymentOptions {
+		if option == paymentOption {
+			return offeringType
+		}
+	}
+	return string(paymentOption)
+}
+
+// savingsPlanTypeName は表に表示するSavings Plansの種類（例: Compute, EC2Instance m5）を返す
+func savingsPlanTypeName(plan savingsplansTypes.SavingsPlan) string {
+	name := string(plan.SavingsPlanType)
+	if family := aws.ToString(plan.Ec2InstanceFamily); family != "" {
+		name += " " + family
+	}
+	return name
+}
+
+// durationYears はリザベーションの期間を年単位に丸める
+func durationYears(duration time.Duration) int {
+	years := int(duration.Hours()/24/365 + 0.5)
+	if years < 1 {
+		return 1
+	}
+	return years
+}
+
+// expirationRow は有効期限の1行を表のセルに変換する
+func expirationRow(expiration Expiration) []string {
+	instance := expiration.InstanceType
+	if expiration.Description != "" {
+		instance += " " + expiration.Description
+	}
+	if expiration.MultiAz {
+		instance += " (Multi-AZ)"
+	}
+	count := "-"
+	if expiration.Count > 0 {
+		count = strconv.Itoa(expiration.Count)
+	}
+	upfront, monthly := "-", "-"
+	if expiration.RenewalUpfront != nil {
+		upfront = fmt.Sprintf("%.2f", *expiration.RenewalUpfront)
+		monthly = fmt.Sprintf("%.2f", *expiration.RenewalMonthly)
+	}
+	daysLeft := strconv.Itoa(expiration.DaysLeft)
+	if expiration.Expiring {
+		daysLeft += " !"
+	}
+	return []string{
+		expirationServiceName(expiration.Service),
+		expiration.ID,
+		instance,
+		count,
+		expiration.Region,
+		fmt.Sprintf("%dy %s", expiration.Duration, expiration.OfferingType),
+		expiration.End.Format("2006-01-02"),
+		daysLeft,
+		upfront,
+		monthly,
+	}
+}
+
+// expirationServiceName は表に表示するサービス名を返す
+func expirationServiceName(service string) string {
+	if service == "savings-plans" {
+		return "Savings Plans"
+	}
+	return serviceDisplayName(service)
+}
+
+// renderExpirationTable はテーブル形式で有効期限の一覧を表示する
+func renderExpirationTable(expirations []Expiration, days int) {
+	table := NewTable(expirationHeadings)
+	expiring := 0
+	for _, expiration := range expirations {
+		table.Append(expirationRow(expiration))
+		if expiration.Expiring {
+			expiring++
+		}
+	}
+	table.Render()
+
+	fmt.Println()
+	fmt.Printf("%d of %d reservations and Savings Plans end within %d days (marked with !)\n", expiring, len(expirations), days)
+}
+
+// renderExpirationCSV はCSV形式で有効期限の一覧を表示する
+func renderExpirationCSV(expirations []Expiration) error {
+	writer := csv.NewWriter(os.Stdout)
+	if err := writer.Write(expirationHeadings); err != nil {
+		return err
+	}
+	for _, expiration := range expirations {
+		if err := writer.Write(expirationRow(expiration)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// renderExpirationsICS は終了日を終日の予定としたiCalendarを返す
+// 予定には終了日の--days日前に通知するアラームを付ける
+func renderExpirationsICS(expirations []Expiration, days int, now time.Time) string {
+	var lines []string
+	lines = append(lines,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//awsri//expirations//EN",
+		"CALSCALE:GREGORIAN",
+	)
+	for _, expiration := range expirations {
+		end := expiration.End.UTC()
+		row := expirationRow(expiration)
+		summary := fmt.Sprintf("%s %s expires", row[0], row[2])
+		if expiration.Count > 0 {
+			summary = fmt.Sprintf("%s %s x%d expires", row[0], row[2], expiration.Count)
+		}
+		description := fmt.Sprintf("%s (%s, %s) started on %s and ends on %s.",
+			expiration.ID, row[5], expiration.Region, expiration.Start.UTC().Format("2006-01-02"), end.Format(time.RFC3339))
+		if expiration.RenewalUpfront != nil {
+			description += fmt.Sprintf(" Renewal at today's prices: %s USD upfront and %s USD per month.", row[8], row[9])
+		}
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-%s@awsri", expiration.Service, expiration.ID),
+			"DTSTAMP:"+now.UTC().Format("20060102T150405Z"),
+			"DTSTART;VALUE=DATE:"+end.Format("20060102"),
+			"DTEND;VALUE=DATE:"+end.AddDate(0, 0, 1).Format("20060102"),
+			"SUMMARY:"+escapeICSText(summary),
+			"DESCRIPTION:"+escapeICSText(description),
+		)
+		if days > 0 {
+			lines = append(lines,
+				"BEGIN:VALARM",
+				"ACTION:DISPLAY",
+				"DESCRIPTION:"+escapeICSText(summary),
+				fmt.Sprintf("TRIGGER:-P%dD", days),
+				"END:VALARM",
+			)
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var builder strings.Builder
+	for _, line := range lines {
+		builder.WriteString(foldICSLine(line))
+		builder.WriteString("\r\n")
+	}
+	return builder.String()
+}
+
+// escapeICSText はiCalendarのテキストの特殊文字をエスケープする
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
+
+// foldICSLine はiCalendarの1行を75オクテットごとに折り返す
+func foldICSLine(line string) string {
+	const limit = 75
+	var builder strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			builder.WriteString("\r\n ")
+			width = 1
+		}
+		builder.WriteRune(r)
+		width += size
+	}
+	return builder.String()
+}
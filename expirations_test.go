@@ -0,0 +1,207 @@
+package awsri
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans"
+	savingsplansTypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+)
+
+var expirationsNow = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+// fakeReservations は各サービスのリザベーションを返すスタブ
+type fakeReservations struct{}
+
+func (f *fakeReservations) DescribeReservedDBInstances(ctx context.Context, params *rds.DescribeReservedDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeReservedDBInstancesOutput, error) {
+	// 1年前の10日後に開始した1年のRIは10日後に終了する
+	start := expirationsNow.AddDate(-1, 0, 10)
+	return &rds.DescribeReservedDBInstancesOutput{
+		ReservedDBInstances: []rdsTypes.ReservedDBInstance{
+			{
+				ReservedDBInstanceId: aws.String("ri-postgres"),
+				DBInstanceClass:      aws.String("db.r6g.large"),
+				ProductDescription:   aws.String("postgresql"),
+				DBInstanceCount:      aws.Int32(2),
+				MultiAZ:              aws.Bool(true),
+				OfferingType:         aws.String("Partial Upfront"),
+				Duration:             aws.Int32(31536000),
+				StartTime:            aws.Time(start),
+				State:                aws.String("active"),
+			},
+			{
+				ReservedDBInstanceId: aws.String("ri-retired"),
+				DBInstanceClass:      aws.String("db.r5.large"),
+				Duration:             aws.Int32(31536000),
+				StartTime:            aws.Time(start.AddDate(-1, 0, 0)),
+				State:                aws.String("retired"),
+			},
+		},
+	}, nil
+}
+
+func (f *fakeReservations) DescribeReservedCacheNodes(ctx context.Context, params *elasticache.DescribeReservedCacheNodesInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeReservedCacheNodesOutput, error) {
+	return &elasticache.DescribeReservedCacheNodesOutput{}, nil
+}
+
+func (f *fakeReservations) DescribeReservedInstances(ctx context.Context, params *ec2.DescribeReservedInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeReservedInstancesOutput, error) {
+	start := expirationsNow.AddDate(-1, 0, 0)
+	return &ec2.DescribeReservedInstancesOutput{
+		ReservedInstances: []ec2Types.ReservedInstances{
+			{
+				ReservedInstancesId: aws.String("ri-ec2"),
+				InstanceType:        ec2Types.InstanceType("m7g.large"),
+				ProductDescription:  ec2Types.RIProductDescription("Linux/UNIX"),
+				InstanceCount:       aws.Int32(4),
+				OfferingType:        ec2Types.OfferingTypeValues("No Upfront"),
+				OfferingClass:       ec2Types.OfferingClassTypeStandard,
+				Scope:               ec2Types.ScopeRegional,
+				Duration:            aws.Int64(94608000),
+				Start:               aws.Time(start),
+				End:                 aws.Time(start.AddDate(3, 0, 0)),
+				State:               ec2Types.ReservedInstanceStateActive,
+			},
+		},
+	}, nil
+}
+
+type fakeOpenSearchReservations struct{}
+
+func (f *fakeOpenSearchReservations) DescribeReservedInstances(ctx context.Context, params *opensearch.DescribeReservedInstancesInput, optFns ...func(*opensearch.Options)) (*opensearch.DescribeReservedInstancesOutput, error) {
+	return &opensearch.DescribeReservedInstancesOutput{}, nil
+}
+
+type fakeSavingsPlans struct{}
+
+func (f *fakeSavingsPlans) DescribeSavingsPlans(ctx context.Context, params *savingsplans.DescribeSavingsPlansInput, optFns ...func(*savingsplans.Options)) (*savingsplans.DescribeSavingsPlansOutput, error) {
+	return &savingsplans.DescribeSavingsPlansOutput{
+		SavingsPlans: []savingsplansTypes.SavingsPlan{
+			{
+				SavingsPlanId:          aws.String("sp-1"),
+				SavingsPlanType:        savingsplansTypes.SavingsPlanTypeCompute,
+				Commitment:             aws.String("1.5"),
+				PaymentOption:          savingsplansTypes.SavingsPlanPaymentOptionNoUpfront,
+				RecurringPaymentAmount: aws.String("1.5"),
+				TermDurationInSeconds:  31536000,
+				Start:                  aws.String("2025-11-07T00:00:00Z"),
+				End:                    aws.String("2026-11-07T00:00:00Z"),
+				State:                  savingsplansTypes.SavingsPlanStateActive,
+			},
+		},
+	}, nil
+}
+
+func newFakeExpirationsCommand(opts ExpirationsOption) *ExpirationsCommand {
+	cmd := NewExpirationsCommand(opts)
+	cmd.newClients = func(cfg aws.Config) expirationClients {
+		return expirationClients{rds: &fakeReservations{}, elasticache: &fakeReservations{}, ec2: &fakeReservations{}, opensearch: &fakeOpenSearchReservations{}}
+	}
+	cmd.newSavingsPlansClient = func(cfg aws.Config) savingsPlansDescribeAPI {
+		return &fakeSavingsPlans{}
+	}
+	// RIの更新料金はオファリングの代わりに固定値を使う
+	cmd.renewal = func(ctx context.Context, expiration Expiration) (float64, float64, error) {
+		if expiration.Service == "savings-plans" {
+			return cmd.renewalPrice(ctx, expiration)
+		}
+		return 100 * float64(expiration.Count), 10 * float64(expiration.Count), nil
+	}
+	return cmd
+}
+
+func TestExpirationsList(t *testing.T) {
+	cmd := newFakeExpirationsCommand(ExpirationsOption{Services: []string{"rds", "elasticache", "ec2", "opensearch", "savings-plans"}, Days: 30})
+	expirations, err := cmd.list(context.Background(), []string{"ap-northeast-1"}, expirationsNow)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 終了日の順に並び、退役したRIは含まない
+	expected := []struct {
+		id       string
+		daysLeft int
+		duration int
+		expiring bool
+		upfront  float64
+		monthly  float64
+	}{
+		{"ri-postgres", 10, 1, true, 200, 20},
+		{"sp-1", 20, 1, true, 0, 1.5 * 24 * 30},
+		{"ri-ec2", 731, 3, false, 0, 0}, // 2028年はうるう年
+	}
+	if len(expirations) != len(expected) {
+		t.Fatalf("Expected %d expirations, got %d: %+v", len(expected), len(expirations), expirations)
+	}
+	for i, want := range expected {
+		got := expirations[i]
+		if got.ID != want.id || got.DaysLeft != want.daysLeft || got.Duration != want.duration || got.Expiring != want.expiring {
+			t.Errorf("expiration %d: expected %+v, got %+v", i, want, got)
+			continue
+		}
+		if !want.expiring {
+			if got.RenewalUpfront != nil {
+				t.Errorf("%s: expected no renewal price", got.ID)
+			}
+			continue
+		}
+		if got.RenewalUpfront == nil || *got.RenewalUpfront != want.upfront || *got.RenewalMonthly != want.monthly {
+			t.Errorf("%s: expected renewal %.2f/%.2f, got %v/%v", got.ID, want.upfront, want.monthly, got.RenewalUpfront, got.RenewalMonthly)
+		}
+	}
+	if expirations[0].Region != "ap-northeast-1" || !expirations[0].MultiAz || expirations[0].Count != 2 {
+		t.Errorf("unexpected RDS reservation: %+v", expirations[0])
+	}
+}
+
+func TestRenderExpirationsICS(t *testing.T) {
+	upfront, monthly := 200.0, 20.0
+	ics := renderExpirationsICS([]Expiration{
+		{
+			Service:        "rds",
+			ID:             "ri-postgres",
+			InstanceType:   "db.r6g.large",
+			Description:    "postgresql",
+			Count:          2,
+			Region:         "ap-northeast-1",
+			OfferingType:   "Partial Upfront",
+			Duration:       1,
+			Start:          time.Date(2025, 10, 28, 9, 0, 0, 0, time.UTC),
+			End:            time.Date(2026, 10, 28, 9, 0, 0, 0, time.UTC),
+			RenewalUpfront: &upfront,
+			RenewalMonthly: &monthly,
+		},
+	}, 30, expirationsNow)
+
+	for _, line := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:rds-ri-postgres@awsri\r\n",
+		"DTSTAMP:20261018T000000Z\r\n",
+		"DTSTART;VALUE=DATE:20261028\r\n",
+		"DTEND;VALUE=DATE:20261029\r\n",
+		"SUMMARY:RDS db.r6g.large postgresql x2 expires\r\n",
+		"TRIGGER:-P30D\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, line) {
+			t.Errorf("Expected %q in:\n%s", line, ics)
+		}
+	}
+	// カンマはエスケープされ、75オクテットを超える行は折り返される
+	if !strings.Contains(ics, `(1y Partial Upfront\, ap-northeast-1)`) {
+		t.Errorf("Expected escaped commas in:\n%s", ics)
+	}
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+}
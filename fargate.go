@@ -16,20 +16,44 @@ import (
 )
 
 type FargateOption struct {
-	Region                string  `name:"region" default:"ap-northeast-1" help:"AWS region"`
-	MemoryMBPerHour       float64 `required:"" help:"Memory MB per hour (will be converted to GB)"`
-	VCPUMillicoresPerHour float64 `required:"" help:"vCPU millicores per hour (will be converted to vCPU)"`
-	TaskCount             int     `required:"" help:"Number of tasks"`
-	Duration              int     `name:"duration" default:"1" help:"Duration in years (1 or 3)"`
-	Architecture          string  `name:"architecture" default:"x86_64" help:"Architecture (x86_64 or arm)"`
-	PaymentOption         string  `name:"payment-option" default:"no-upfront" help:"Payment option (no-upfront, partial-upfront, all-upfront)"`
-	NoHeader              bool    `name:"no-header" help:"Do not output CSV header"`
+	Region                string   `name:"region" default:"ap-northeast-1" help:"AWS region"`
+	MemoryMBPerHour       float64  `help:"Memory MB per hour (will be converted to GB)"`
+	VCPUMillicoresPerHour float64  `help:"vCPU millicores per hour (will be converted to vCPU)"`
+	TaskCount             int      `help:"Number of tasks"`
+	Workloads             []string `name:"workload" help:"Workload in format: name:vcpu-millicores:memory-mb:task-count[:architecture[:hours]], hours as N/day or N/month (repeatable, replaces the per-task flags)"`
+	Duration              int      `name:"duration" default:"1" help:"Duration in years (1 or 3)"`
+	Architecture          string   `name:"architecture" default:"x86_64" help:"Architecture (x86_64 or arm)"`
+	PaymentOption         string   `name:"payment-option" default:"no-upfront" help:"Payment option (no-upfront, partial-upfront, all-upfront)"`
+	NoHeader              bool     `name:"no-header" help:"Do not output CSV header"`
 }
 
 type FargateCommand struct {
 	opts FargateOption
 }
 
+// fargateWorkload is a group of identical Fargate tasks and their monthly running hours
+type fargateWorkload struct {
+	name          string
+	vcpu          float64 // vCPU per task
+	memoryGB      float64 // memory GB per task
+	taskCount     int
+	architecture  string
+	hoursPerMonth float64
+}
+
+// fargateWorkloadCost is the monthly cost of a workload on demand and with a Savings Plan
+type fargateWorkloadCost struct {
+	workload    fargateWorkload
+	currentCost float64
+	spCost      float64
+}
+
+// onDemandIsCheaper reports whether covering the workload with a Savings Plan costs more than running it on demand,
+// in which case the workload is left out of the commitment
+func (c fargateWorkloadCost) onDemandIsCheaper() bool {
+	return c.spCost > c.currentCost
+}
+
 type FargatePricing struct {
 	VCPUOnDemandPrice   float64 // per hour
 	MemoryOnDemandPrice float64 // per GB per hour
@@ -42,61 +66,225 @@ func NewFargateCommand(opts FargateOption) *FargateCommand {
 }
 
 func (c *FargateCommand) Run(ctx context.Context) error {
+	// Validate duration (must be 1 or 3 years)
+	if c.opts.Duration != 1 && c.opts.Duration != 3 {
+		return fmt.Errorf("duration must be 1 or 3 years, got: %d", c.opts.Duration)
+	}
+
+	workloads, err := c.workloads()
+	if err != nil {
+		return err
+	}
+
 	// Pricing API and Savings Plans API are only available in us-east-1
 	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
 	if err != nil {
 		return fmt.Errorf("unable to load SDK config: %v", err)
 	}
 
-	// Get on-demand pricing
-	onDemandPricing, err := c.getFargateOnDemandPrice(ctx, cfg)
-	if err != nil {
-		return fmt.Errorf("failed to get on-demand price: %v", err)
+	// Get on-demand and Savings Plan pricing once per architecture
+	onDemandPricing := make(map[string]*FargatePricing)
+	spPricing := make(map[string]*FargatePricing)
+	for _, workload := range workloads {
+		if _, ok := onDemandPricing[workload.architecture]; ok {
+			continue
+		}
+		arch := *c
+		arch.opts.Architecture = workload.architecture
+
+		onDemandPricing[workload.architecture], err = arch.getFargateOnDemandPrice(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to get on-demand price: %v", err)
+		}
+		spPricing[workload.architecture], err = arch.getComputeSavingsPlanPrice(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to get Savings Plan price: %v", err)
+		}
 	}
 
-	// Get Savings Plan pricing
-	spPricing, err := c.getComputeSavingsPlanPrice(ctx, cfg)
-	if err != nil {
-		return fmt.Errorf("failed to get Savings Plan price: %v", err)
+	var costs []fargateWorkloadCost
+	for _, workload := range workloads {
+		costs = append(costs, calculateFargateWorkloadCost(workload, onDemandPricing[workload.architecture], spPricing[workload.architecture]))
 	}
 
-	// Validate duration (must be 1 or 3 years)
-	if c.opts.Duration != 1 && c.opts.Duration != 3 {
-		return fmt.Errorf("duration must be 1 or 3 years, got: %d", c.opts.Duration)
+	if len(c.opts.Workloads) > 0 {
+		renderFargateWorkloadCSV(costs, c.opts.Duration, c.opts.NoHeader)
+		return nil
+	}
+
+	// Output CSV
+	hourlyCommitment, spPurchaseAmount, currentCostPerMonth, spCostPerMonth, savingsAmount, savingsRate := fargateSummary(costs, c.opts.Duration)
+	renderCSV(hourlyCommitment, spPurchaseAmount, currentCostPerMonth, spCostPerMonth, savingsAmount, savingsRate, c.opts.NoHeader)
+
+	return nil
+}
+
+// workloads returns the workloads given with --workload, or a single workload running all month
+// from --vcpu-millicores-per-hour, --memory-mb-per-hour and --task-count
+func (c *FargateCommand) workloads() ([]fargateWorkload, error) {
+	if c.opts.Architecture != "x86_64" && c.opts.Architecture != "arm" {
+		return nil, fmt.Errorf("architecture must be x86_64 or arm, got: %s", c.opts.Architecture)
+	}
+	if len(c.opts.Workloads) > 0 {
+		return parseFargateWorkloads(c.opts.Workloads, c.opts.Architecture)
+	}
+	if c.opts.VCPUMillicoresPerHour <= 0 || c.opts.MemoryMBPerHour <= 0 || c.opts.TaskCount <= 0 {
+		return nil, fmt.Errorf("--vcpu-millicores-per-hour, --memory-mb-per-hour and --task-count are required unless --workload is given")
 	}
 
 	// Convert input parameter units
 	// vCPU: millicores to vCPU (divide by 1000, following Kubernetes convention)
 	// Memory: MB to GB (divide by 1024)
-	vcpuCount := c.opts.VCPUMillicoresPerHour / 1000.0
-	memoryGB := c.opts.MemoryMBPerHour / 1024.0
+	return []fargateWorkload{{
+		name:          "default",
+		vcpu:          c.opts.VCPUMillicoresPerHour / 1000.0,
+		memoryGB:      c.opts.MemoryMBPerHour / 1024.0,
+		taskCount:     c.opts.TaskCount,
+		architecture:  c.opts.Architecture,
+		hoursPerMonth: 720.0,
+	}}, nil
+}
 
-	// Calculate monthly cost (720 hours/month)
-	// TaskCount × vCPU count × 720 hours × vCPU price + TaskCount × GB count × 720 hours × GB price
-	hoursPerMonth := 720.0
-	currentCostPerMonth := float64(c.opts.TaskCount)*vcpuCount*hoursPerMonth*onDemandPricing.VCPUOnDemandPrice +
-		float64(c.opts.TaskCount)*memoryGB*hoursPerMonth*onDemandPricing.MemoryOnDemandPrice
+// parseFargateWorkloads parses workloads in format name:vcpu-millicores:memory-mb:task-count[:architecture[:hours]].
+// Hours are the average running hours of the tasks as N/day or N/month; a plain number is per month.
+func parseFargateWorkloads(defs []string, defaultArchitecture string) ([]fargateWorkload, error) {
+	var workloads []fargateWorkload
+	for _, def := range defs {
+		parts := strings.Split(def, ":")
+		if len(parts) < 4 || len(parts) > 6 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid workload format: %s, expected format: name:vcpu-millicores:memory-mb:task-count[:architecture[:hours]]", def)
+		}
+		millicores, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || millicores <= 0 {
+			return nil, fmt.Errorf("invalid vCPU millicores in workload %s: %s", parts[0], parts[1])
+		}
+		memoryMB, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || memoryMB <= 0 {
+			return nil, fmt.Errorf("invalid memory MB in workload %s: %s", parts[0], parts[2])
+		}
+		taskCount, err := strconv.Atoi(parts[3])
+		if err != nil || taskCount <= 0 {
+			return nil, fmt.Errorf("invalid task count in workload %s: %s", parts[0], parts[3])
+		}
+
+		architecture := defaultArchitecture
+		if len(parts) >= 5 && parts[4] != "" {
+			architecture = parts[4]
+		}
+		if architecture != "x86_64" && architecture != "arm" {
+			return nil, fmt.Errorf("invalid architecture in workload %s: %s (must be x86_64 or arm)", parts[0], architecture)
+		}
+
+		hoursPerMonth := 720.0
+		if len(parts) == 6 {
+			hoursPerMonth, err = parseFargateHours(parts[5])
+			if err != nil {
+				return nil, fmt.Errorf("invalid hours in workload %s: %v", parts[0], err)
+			}
+		}
+
+		workloads = append(workloads, fargateWorkload{
+			name:          parts[0],
+			vcpu:          millicores / 1000.0,
+			memoryGB:      memoryMB / 1024.0,
+			taskCount:     taskCount,
+			architecture:  architecture,
+			hoursPerMonth: hoursPerMonth,
+		})
+	}
+	return workloads, nil
+}
+
+// parseFargateHours converts running hours given as N/day or N/month to hours per 720-hour month
+func parseFargateHours(value string) (float64, error) {
+	amount, unit, _ := strings.Cut(value, "/")
+	hours, err := strconv.ParseFloat(amount, 64)
+	if err != nil || hours <= 0 {
+		return 0, fmt.Errorf("%s is not a positive number of hours", value)
+	}
+	switch unit {
+	case "day":
+		hours *= 30
+	case "", "month":
+	default:
+		return 0, fmt.Errorf("unknown unit in %s (must be day or month)", value)
+	}
+	if hours > 720 {
+		return 0, fmt.Errorf("%s is more than 720 hours per month", value)
+	}
+	return hours, nil
+}
 
-	spCostPerMonth := float64(c.opts.TaskCount)*vcpuCount*hoursPerMonth*spPricing.VCPUSPPrice +
-		float64(c.opts.TaskCount)*memoryGB*hoursPerMonth*spPricing.MemorySPPrice
+// calculateFargateWorkloadCost calculates the monthly cost of a workload:
+// on demand, tasks × (vCPU × vCPU price + GB × GB price) × running hours.
+// A Savings Plan is paid for every hour whether the tasks run or not, so the commitment
+// covering the running tasks is charged for all 720 hours of the month.
+func calculateFargateWorkloadCost(workload fargateWorkload, onDemandPricing, spPricing *FargatePricing) fargateWorkloadCost {
+	tasks := float64(workload.taskCount)
+	onDemandHourly := tasks*workload.vcpu*onDemandPricing.VCPUOnDemandPrice + tasks*workload.memoryGB*onDemandPricing.MemoryOnDemandPrice
+	spHourly := tasks*workload.vcpu*spPricing.VCPUSPPrice + tasks*workload.memoryGB*spPricing.MemorySPPrice
+	return fargateWorkloadCost{
+		workload:    workload,
+		currentCost: onDemandHourly * workload.hoursPerMonth,
+		spCost:      spHourly * 720,
+	}
+}
 
-	// Calculate hourly cost (for Hourly commitment)
-	hourlySPCost := spCostPerMonth / hoursPerMonth
+// fargateSummary aggregates workload costs into the columns of the compute-savings-plans CSV.
+// Workloads for which on-demand is cheaper stay on demand and are not covered by the commitment.
+func fargateSummary(costs []fargateWorkloadCost, duration int) (hourlyCommitment, spPurchaseAmount, currentCostPerMonth, spCostPerMonth, savingsAmount, savingsRate float64) {
+	var commitmentCostPerMonth float64
+	for _, cost := range costs {
+		currentCostPerMonth += cost.currentCost
+		if cost.onDemandIsCheaper() {
+			spCostPerMonth += cost.currentCost
+			continue
+		}
+		commitmentCostPerMonth += cost.spCost
+		spCostPerMonth += cost.spCost
+	}
 
-	// Hourly commitment = hourly cost after applying Savings Plan
-	hourlyCommitment := hourlySPCost
+	// Hourly commitment = cost after applying Savings Plan per hour of a 720-hour month
+	hoursPerMonth := 720.0
+	hourlyCommitment = commitmentCostPerMonth / hoursPerMonth
 
 	// SP/RI purchase amount (USD) = Hourly commitment × 720 hours × 12 months × duration (years)
-	spPurchaseAmount := hourlyCommitment * hoursPerMonth * 12.0 * float64(c.opts.Duration)
+	spPurchaseAmount = hourlyCommitment * hoursPerMonth * 12.0 * float64(duration)
 
 	// Calculate savings amount and savings rate
-	savingsAmount := currentCostPerMonth - spCostPerMonth
-	savingsRate := (savingsAmount / currentCostPerMonth) * 100.0
+	savingsAmount = currentCostPerMonth - spCostPerMonth
+	savingsRate = (savingsAmount / currentCostPerMonth) * 100.0
+	return
+}
 
-	// Output CSV
-	renderCSV(hourlyCommitment, spPurchaseAmount, currentCostPerMonth, spCostPerMonth, savingsAmount, savingsRate, c.opts.NoHeader)
+// renderFargateWorkloadCSV outputs a row per workload and a total row with the aggregated hourly commitment
+func renderFargateWorkloadCSV(costs []fargateWorkloadCost, duration int, noHeader bool) {
+	if !noHeader {
+		fmt.Println("Workload,Hourly commitment,SP/RI Purchase Amount (USD),Current Cost (USD/month),Cost After Purchase (USD/month),Savings Amount,Savings Rate")
+	}
+	for _, cost := range costs {
+		fmt.Println(fargateWorkloadRow(cost.workload.name, []fargateWorkloadCost{cost}, duration))
+	}
+	fmt.Println(fargateWorkloadRow("Total", costs, duration))
+}
 
-	return nil
+// fargateWorkloadRow formats a CSV row in the same format as renderCSV with the workload name first.
+// A single workload left on demand is marked in the savings rate column.
+func fargateWorkloadRow(name string, costs []fargateWorkloadCost, duration int) string {
+	hourlyCommitment, spPurchaseAmount, currentCost, spCost, savingsAmount, savingsRate := fargateSummary(costs, duration)
+	rate := fmt.Sprintf("%.0f", savingsRate)
+	if len(costs) == 1 && costs[0].onDemandIsCheaper() {
+		rate += " on-demand is cheaper"
+	}
+	return fmt.Sprintf("%s,%g,%.0f,%.0f,%.0f,%.0f,%s",
+		name,
+		hourlyCommitment,
+		spPurchaseAmount,
+		currentCost,
+		spCost,
+		savingsAmount,
+		rate,
+	)
 }
 
 // getFargateOnDemandPrice retrieves Fargate on-demand pricing using the Pricing API
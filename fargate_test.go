@@ -0,0 +1,85 @@
+package awsri
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseFargateWorkloads(t *testing.T) {
+	workloads, err := parseFargateWorkloads([]string{"api:1024:2048:4", "batch:4096:8192:2:arm:6/day", "cron:256:512:1::40/month"}, "x86_64")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []fargateWorkload{
+		{name: "api", vcpu: 1.024, memoryGB: 2, taskCount: 4, architecture: "x86_64", hoursPerMonth: 720},
+		{name: "batch", vcpu: 4.096, memoryGB: 8, taskCount: 2, architecture: "arm", hoursPerMonth: 180},
+		{name: "cron", vcpu: 0.256, memoryGB: 0.5, taskCount: 1, architecture: "x86_64", hoursPerMonth: 40},
+	}
+	if len(workloads) != len(expected) {
+		t.Fatalf("Expected %d workloads, got %d", len(expected), len(workloads))
+	}
+	for i := range expected {
+		if workloads[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], workloads[i])
+		}
+	}
+
+	for _, def := range []string{
+		"api:1024:2048",
+		"api:1024:2048:0",
+		"api:abc:2048:1",
+		"api:1024:2048:1:graviton",
+		"api:1024:2048:1:arm:25/day",
+		"api:1024:2048:1:arm:8/week",
+	} {
+		if _, err := parseFargateWorkloads([]string{def}, "x86_64"); err == nil {
+			t.Errorf("Expected error for %s", def)
+		}
+	}
+}
+
+func TestFargateWorkloadCosts(t *testing.T) {
+	onDemand := &FargatePricing{VCPUOnDemandPrice: 0.05, MemoryOnDemandPrice: 0.005}
+	sp := &FargatePricing{VCPUSPPrice: 0.04, MemorySPPrice: 0.004}
+
+	// 2 tasks of 1 vCPU/2 GB all month and 1 task of 4 vCPU/8 GB for 180 hours
+	costs := []fargateWorkloadCost{
+		calculateFargateWorkloadCost(fargateWorkload{name: "api", vcpu: 1, memoryGB: 2, taskCount: 2, hoursPerMonth: 720}, onDemand, sp),
+		calculateFargateWorkloadCost(fargateWorkload{name: "batch", vcpu: 4, memoryGB: 8, taskCount: 1, hoursPerMonth: 180}, onDemand, sp),
+	}
+	if math.Abs(costs[0].currentCost-86.4) > 1e-9 || math.Abs(costs[1].currentCost-43.2) > 1e-9 {
+		t.Errorf("unexpected on-demand costs: %.4f, %.4f", costs[0].currentCost, costs[1].currentCost)
+	}
+
+	// The commitment is paid for all 720 hours, also while the batch task is not running
+	if math.Abs(costs[0].spCost-69.12) > 1e-9 || math.Abs(costs[1].spCost-138.24) > 1e-9 {
+		t.Errorf("unexpected Savings Plan costs: %.4f, %.4f", costs[0].spCost, costs[1].spCost)
+	}
+
+	_, _, _, _, savingsAmount, savingsRate := fargateSummary(costs[:1], 1)
+	if math.Abs(savingsAmount-17.28) > 1e-9 || math.Abs(savingsRate-20) > 1e-9 {
+		t.Errorf("unexpected savings of the full-time workload: %.4f, %.4f", savingsAmount, savingsRate)
+	}
+
+	// The batch workload is cheaper on demand, so it stays on demand and is left out of the commitment
+	if costs[0].onDemandIsCheaper() || !costs[1].onDemandIsCheaper() {
+		t.Errorf("expected only batch to be cheaper on demand")
+	}
+	hourlyCommitment, spPurchaseAmount, currentCost, spCost, savingsAmount, savingsRate := fargateSummary(costs, 1)
+	if math.Abs(currentCost-129.6) > 1e-9 || math.Abs(spCost-112.32) > 1e-9 {
+		t.Errorf("unexpected monthly costs: %.4f, %.4f", currentCost, spCost)
+	}
+	if math.Abs(hourlyCommitment-0.096) > 1e-9 || math.Abs(spPurchaseAmount-0.096*720*12) > 1e-6 {
+		t.Errorf("unexpected commitment: %.4f, %.4f", hourlyCommitment, spPurchaseAmount)
+	}
+	if math.Abs(savingsAmount-17.28) > 1e-9 || math.Abs(savingsRate-17.28/129.6*100) > 1e-9 {
+		t.Errorf("unexpected savings: %.4f, %.4f", savingsAmount, savingsRate)
+	}
+
+	if row := fargateWorkloadRow("batch", costs[1:], 1); row != "batch,0,0,43,43,0,0 on-demand is cheaper" {
+		t.Errorf("unexpected batch row: %s", row)
+	}
+	if row := fargateWorkloadRow("Total", costs, 1); row != "Total,0.096,829,130,112,17,13" {
+		t.Errorf("unexpected total row: %s", row)
+	}
+}
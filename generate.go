@@ -3,41 +3,137 @@ package awsri
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/docdb"
+	docdbTypes "github.com/aws/aws-sdk-go-v2/service/docdb/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbTypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecsTypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
 	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
+	"github.com/aws/aws-sdk-go-v2/service/neptune"
+	neptuneTypes "github.com/aws/aws-sdk-go-v2/service/neptune/types"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
 )
 
 // GenerateCommand は引数生成コマンドを表す構造体
 type GenerateCommand struct {
 	opts GenerateOption
+
+	// テストでスタブに差し替えられるようにAPIクライアントの生成処理を保持する
+	newSTSClient           func(cfg aws.Config) stsAPI
+	newOrganizationsClient func(cfg aws.Config) organizationsAPI
+	discover               func(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error)
+	discoverReservations   func(ctx context.Context, cfg aws.Config) ([]Reservation, error)
+
+	// 稼働状態や作成からの期間により集計から除外したリソース
+	skipped []SkippedResource
 }
 
+// maxConcurrentScans はアカウント×リージョンのスキャンの最大並列数
+const maxConcurrentScans = 16
+
 // NewGenerateCommand は新しいGenerateCommandを作成する
 func NewGenerateCommand(opts GenerateOption) *GenerateCommand {
-	return &GenerateCommand{opts: opts}
+	c := &GenerateCommand{
+		opts: opts,
+		newSTSClient: func(cfg aws.Config) stsAPI {
+			return sts.NewFromConfig(cfg)
+		},
+		newOrganizationsClient: func(cfg aws.Config) organizationsAPI {
+			return organizations.NewFromConfig(cfg)
+		},
+	}
+	c.discover = c.getInstancesInfo
+	c.discoverReservations = c.getReservations
+	return c
 }
 
 // Run はGenerateCommandを実行する
 func (c *GenerateCommand) Run(ctx context.Context) error {
+	// 最小稼働期間を解析
+	var minAge time.Duration
+	if c.opts.MinAge != "" {
+		var err error
+		minAge, err = parseAge(c.opts.MinAge)
+		if err != nil {
+			return err
+		}
+	}
+
 	// AWS設定を読み込み
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(c.opts.Region))
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(c.opts.Region))
 	if err != nil {
 		return fmt.Errorf("unable to load SDK config: %w", err)
 	}
 
-	// インスタンス情報を取得
-	instances, err := c.getInstancesInfo(ctx, cfg)
+	// スキャン対象のアカウントを決定
+	accounts, err := c.targetAccounts(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target accounts: %w", err)
+	}
+
+	// スキャン対象のリージョンを決定
+	regions := c.targetRegions()
+
+	// 全アカウント・全リージョンのインスタンス情報を並列に取得
+	instances, reservations, err := c.scan(ctx, accounts, regions)
 	if err != nil {
 		return fmt.Errorf("failed to get instances info: %w", err)
 	}
 
+	// EC2とFargateはtotalコマンドで料金を計算できないため、JSON出力にのみ含まれる
+	if c.opts.Output != "json" {
+		for _, instance := range instances {
+			if instance.ServiceType == "ec2" || instance.ServiceType == "fargate" {
+				fmt.Fprintln(os.Stderr, "Note: EC2 instances and Fargate tasks are only included in --output=json")
+				break
+			}
+		}
+	}
+
+	// タグでフィルタリング・グループ化
+	instances, err = c.applyTagOptions(instances)
+	if err != nil {
+		return err
+	}
+
+	// 停止中のインスタンスや作成から日の浅いインスタンスを除外
+	instances, c.skipped = filterSteadyInstances(instances, minAge, time.Now())
+	if c.opts.Output != "json" {
+		for _, s := range c.skipped {
+			fmt.Fprintf(os.Stderr, "Skipped: %s %s (%s) in %s%s: %s\n",
+				s.ServiceType, s.ResourceID, s.InstanceType, s.Region, accountTarget{id: s.Account}.label(), s.Reason)
+		}
+	}
+
+	// インスタンス情報を集計
+	aggregated := aggregateInstances(instances)
+
+	// 既存のリザベーションでカバーされている分を差し引く
+	if c.opts.Uncovered {
+		aggregated = subtractReservations(aggregated, reservations)
+	}
+
 	// 出力形式に応じて結果を表示
-	output, err := c.formatOutput(instances, c.opts.Output)
+	output, err := c.formatOutput(aggregated, c.opts.Output)
 	if err != nil {
 		return fmt.Errorf("failed to format output: %w", err)
 	}
@@ -46,118 +142,871 @@ func (c *GenerateCommand) Run(ctx context.Context) error {
 	return nil
 }
 
-// getInstancesInfo はAWSアカウントからインスタンス情報を取得する
-func (c *GenerateCommand) getInstancesInfo(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+// targetRegions はスキャン対象のリージョン一覧を返す
+// --regionsが指定されていない場合は--regionのみを対象とする
+func (c *GenerateCommand) targetRegions() []string {
+	if len(c.opts.Regions) == 0 {
+		return []string{c.opts.Region}
+	}
+	if c.scanAllRegions() {
+		return allRegions()
+	}
+
+	var regions []string
+	for _, region := range c.opts.Regions {
+		region = strings.TrimSpace(region)
+		if region != "" {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// scanAllRegions は--regions=allが指定されているかどうかを返す
+func (c *GenerateCommand) scanAllRegions() bool {
+	return len(c.opts.Regions) == 1 && strings.TrimSpace(c.opts.Regions[0]) == "all"
+}
+
+// scan は各アカウント・各リージョンのインスタンス情報を並列に取得する
+// --uncoveredが指定されている場合は有効なリザベーションも取得する
+func (c *GenerateCommand) scan(ctx context.Context, accounts []accountTarget, regions []string) ([]InstanceInfo, []Reservation, error) {
+	type target struct {
+		account accountTarget
+		region  string
+	}
+	var targets []target
+	for _, account := range accounts {
+		for _, region := range regions {
+			targets = append(targets, target{account: account, region: region})
+		}
+	}
+
+	results := make([][]InstanceInfo, len(targets))
+	reservationResults := make([][]Reservation, len(targets))
+	errs := make([]error, len(targets))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentScans)
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t target) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			cfg := t.account.cfg.Copy()
+			cfg.Region = t.region
+
+			// インスタンス情報を取得
+			instances, err := c.discover(ctx, cfg)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			// 各行にアカウントとリージョンを付与
+			for j := range instances {
+				instances[j].Account = t.account.id
+				instances[j].Region = t.region
+			}
+
+			// リザベーション情報を取得
+			var reservations []Reservation
+			if c.opts.Uncovered {
+				reservations, err = c.discoverReservations(ctx, cfg)
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				for j := range reservations {
+					reservations[j].Account = t.account.id
+					reservations[j].Region = t.region
+				}
+			}
+
+			results[i] = instances
+			reservationResults[i] = reservations
+		}(i, t)
+	}
+	wg.Wait()
+
 	var instances []InstanceInfo
+	var reservations []Reservation
+	for i, t := range targets {
+		if errs[i] != nil {
+			// 全リージョンをスキャンする場合、有効化されていないリージョンなどはスキップする
+			if c.scanAllRegions() {
+				fmt.Fprintf(os.Stderr, "Warning: skipping region %s%s: %v\n", t.region, t.account.label(), errs[i])
+				continue
+			}
+			return nil, nil, fmt.Errorf("region %s%s: %w", t.region, t.account.label(), errs[i])
+		}
+		instances = append(instances, results[i]...)
+		reservations = append(reservations, reservationResults[i]...)
+	}
 
-	// RDSインスタンス情報を取得
-	rdsInstances, err := c.getRDSInstances(ctx, cfg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get RDS instances: %w", err)
+	return instances, reservations, nil
+}
+
+// needsTags はリソースのタグを取得する必要があるかどうかを返す
+func (c *GenerateCommand) needsTags() bool {
+	return len(c.opts.IncludeTags) > 0 || len(c.opts.ExcludeTags) > 0 || c.opts.GroupByTag != ""
+}
+
+// applyTagOptions は--include-tag/--exclude-tagでインスタンスを絞り込み、
+// --group-by-tagのタグのみを残して集計のキーにする
+func (c *GenerateCommand) applyTagOptions(instances []InstanceInfo) ([]InstanceInfo, error) {
+	if !c.needsTags() {
+		return instances, nil
 	}
-	instances = append(instances, rdsInstances...)
 
-	// ElastiCacheインスタンス情報を取得
-	elasticacheInstances, err := c.getElastiCacheInstances(ctx, cfg)
+	include, err := parseTagFilter(c.opts.IncludeTags)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get ElastiCache instances: %w", err)
+		return nil, err
+	}
+	exclude, err := parseTagFilter(c.opts.ExcludeTags)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []InstanceInfo
+	for _, instance := range instances {
+		if !include.matchesAll(instance.Tags) || exclude.matchesAny(instance.Tags) {
+			continue
+		}
+
+		// グループ化に使うタグ以外は出力しない（タグがない場合は空文字の値でグループ化する）
+		if c.opts.GroupByTag != "" {
+			instance.Tags = map[string]string{c.opts.GroupByTag: instance.Tags[c.opts.GroupByTag]}
+		} else {
+			instance.Tags = nil
+		}
+		filtered = append(filtered, instance)
+	}
+
+	return filtered, nil
+}
+
+// aggregateInstances は同じ構成のインスタンスをまとめ、アカウントごとの内訳を付与する
+func aggregateInstances(instances []InstanceInfo) []InstanceInfo {
+	var aggregated []InstanceInfo
+	index := make(map[string]int)
+
+	for _, instance := range instances {
+		key := fmt.Sprintf("%s|%s|%s|%t|%s|%s", instance.ServiceType, instance.InstanceType, instance.Description, instance.MultiAz, instance.Region, tagsKey(instance.Tags))
+
+		i, ok := index[key]
+		if !ok {
+			i = len(aggregated)
+			index[key] = i
+			aggregated = append(aggregated, InstanceInfo{
+				ServiceType:  instance.ServiceType,
+				InstanceType: instance.InstanceType,
+				Description:  instance.Description,
+				MultiAz:      instance.MultiAz,
+				Region:       instance.Region,
+				Tags:         instance.Tags,
+			})
+		}
+
+		aggregated[i].Count += instance.Count
+		if instance.Account != "" {
+			if aggregated[i].Accounts == nil {
+				aggregated[i].Accounts = make(map[string]int)
+			}
+			aggregated[i].Accounts[instance.Account] += instance.Count
+		}
+	}
+
+	// 出力を安定させるためにソート
+	sort.SliceStable(aggregated, func(i, j int) bool {
+		if aggregated[i].Region != aggregated[j].Region {
+			return aggregated[i].Region < aggregated[j].Region
+		}
+		if aggregated[i].ServiceType != aggregated[j].ServiceType {
+			return aggregated[i].ServiceType > aggregated[j].ServiceType
+		}
+		if aggregated[i].InstanceType != aggregated[j].InstanceType {
+			return aggregated[i].InstanceType < aggregated[j].InstanceType
+		}
+		if aggregated[i].Description != aggregated[j].Description {
+			return aggregated[i].Description < aggregated[j].Description
+		}
+		return tagsKey(aggregated[i].Tags) < tagsKey(aggregated[j].Tags)
+	})
+
+	return aggregated
+}
+
+// getInstancesInfo はAWSアカウントからインスタンス情報を取得する
+// 戻り値はリソース単位の行で、集計はaggregateInstancesで行う
+// 権限のないサービスは警告を表示してスキップし、他のサービスの探索を続ける
+func (c *GenerateCommand) getInstancesInfo(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+	discoverers := []struct {
+		name     string
+		discover func(context.Context, aws.Config) ([]InstanceInfo, error)
+	}{
+		{"RDS instances", c.getRDSInstances},
+		{"ElastiCache instances", c.getElastiCacheInstances},
+		{"OpenSearch instances", c.getOpenSearchInstances},
+		{"Redshift nodes", c.getRedshiftNodes},
+		{"MemoryDB nodes", c.getMemoryDBNodes},
+		{"DynamoDB tables", c.getDynamoDBTables},
+		{"DocumentDB instances", c.getDocDBInstances},
+		{"Neptune instances", c.getNeptuneInstances},
+		{"EC2 instances", c.getEC2Instances},
+		{"Fargate tasks", c.getFargateTasks},
+	}
+
+	var instances []InstanceInfo
+	for _, d := range discoverers {
+		found, err := d.discover(ctx, cfg)
+		if err != nil {
+			if isAccessDeniedError(err) {
+				fmt.Fprintf(os.Stderr, "Warning: skipping %s in %s: %v\n", d.name, cfg.Region, err)
+				continue
+			}
+			return nil, fmt.Errorf("failed to get %s: %w", d.name, err)
+		}
+		instances = append(instances, found...)
 	}
-	instances = append(instances, elasticacheInstances...)
 
 	return instances, nil
 }
 
+// accessDeniedErrorCodes は権限がないことを表すAPIのエラーコード
+var accessDeniedErrorCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"UnauthorizedOperation": true, // EC2
+	"UnauthorizedException": true,
+	"AuthorizationError":    true,
+}
+
+// isAccessDeniedError は権限がないために失敗したAPIのエラーかどうかを返す
+func isAccessDeniedError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && accessDeniedErrorCodes[apiErr.ErrorCode()]
+}
+
 // getRDSInstances はRDSインスタンス情報を取得する
 func (c *GenerateCommand) getRDSInstances(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
 	svc := rds.NewFromConfig(cfg)
-	result, err := svc.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{})
+
+	var instances []InstanceInfo
+	paginator := rds.NewDescribeDBInstancesPaginator(svc, &rds.DescribeDBInstancesInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instance := range result.DBInstances {
+			// エンジンタイプを取得（なければデフォルト値を使用）
+			engine := c.opts.RDSEngine
+			if instance.Engine != nil {
+				engine = *instance.Engine
+			}
+
+			// DocumentDBとNeptuneのインスタンスもRDSのAPIで返されるため、それぞれのAPIで取得する
+			if _, ok := rdsCompatibleEngines[engine]; ok {
+				continue
+			}
+
+			// タグを取得
+			var tags map[string]string
+			if c.needsTags() {
+				tags = make(map[string]string)
+				for _, tag := range instance.TagList {
+					tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+			}
+
+			instances = append(instances, InstanceInfo{
+				ServiceType:  "rds",
+				InstanceType: aws.ToString(instance.DBInstanceClass),
+				Count:        1,
+				Description:  rdsProductDescription(engine, aws.ToString(instance.LicenseModel)),
+				MultiAz:      aws.ToBool(instance.MultiAZ),
+				Tags:         tags,
+				ResourceID:   aws.ToString(instance.DBInstanceIdentifier),
+				Status:       aws.ToString(instance.DBInstanceStatus),
+				CreatedAt:    aws.ToTime(instance.InstanceCreateTime),
+			})
+		}
+	}
+
+	return instances, nil
+}
+
+// getElastiCacheInstances はElastiCacheインスタンス情報を取得する
+func (c *GenerateCommand) getElastiCacheInstances(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+	svc := elasticache.NewFromConfig(cfg)
+
+	var instances []InstanceInfo
+	paginator := elasticache.NewDescribeCacheClustersPaginator(svc, &elasticache.DescribeCacheClustersInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cluster := range result.CacheClusters {
+			// エンジンタイプを取得（なければデフォルト値を使用）
+			engine := c.opts.ElastiCacheEngine
+			if cluster.Engine != nil {
+				engine = *cluster.Engine
+			}
+
+			// Memcachedのクラスタは複数ノードを持つためノード数でカウントする
+			count := int(aws.ToInt32(cluster.NumCacheNodes))
+			if count == 0 {
+				count = 1
+			}
+
+			// タグを取得（ElastiCacheはクラスタ情報にタグが含まれないため個別に取得する）
+			var tags map[string]string
+			if c.needsTags() {
+				tagsResult, err := svc.ListTagsForResource(ctx, &elasticache.ListTagsForResourceInput{
+					ResourceName: cluster.ARN,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to list tags for %s: %w", aws.ToString(cluster.CacheClusterId), err)
+				}
+				tags = make(map[string]string)
+				for _, tag := range tagsResult.TagList {
+					tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+			}
+
+			instances = append(instances, InstanceInfo{
+				ServiceType:  "elasticache",
+				InstanceType: aws.ToString(cluster.CacheNodeType),
+				Count:        count,
+				Description:  engine,
+				MultiAz:      false, // ElastiCacheはMultiAzの概念が異なる
+				Tags:         tags,
+				ResourceID:   aws.ToString(cluster.CacheClusterId),
+				Status:       aws.ToString(cluster.CacheClusterStatus),
+				CreatedAt:    aws.ToTime(cluster.CacheClusterCreateTime),
+			})
+		}
+	}
+
+	return instances, nil
+}
+
+// getOpenSearchInstances はOpenSearchドメインのデータノードと専用マスターノードの情報を取得する
+// Descriptionはノードの役割（data または master）
+func (c *GenerateCommand) getOpenSearchInstances(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+	svc := opensearch.NewFromConfig(cfg)
+
+	domains, err := svc.ListDomainNames(ctx, &opensearch.ListDomainNamesInput{})
 	if err != nil {
 		return nil, err
 	}
+	var domainNames []string
+	for _, domain := range domains.DomainNames {
+		domainNames = append(domainNames, aws.ToString(domain.DomainName))
+	}
 
-	// インスタンスタイプごとにカウント
-	instanceCounts := make(map[string]int)
-	instanceEngines := make(map[string]string)
-	instanceMultiAZs := make(map[string]bool)
+	var instances []InstanceInfo
+	// DescribeDomainsは一度に5件まで
+	for start := 0; start < len(domainNames); start += 5 {
+		end := start + 5
+		if end > len(domainNames) {
+			end = len(domainNames)
+		}
 
-	for _, instance := range result.DBInstances {
-		instanceType := *instance.DBInstanceClass
-		instanceCounts[instanceType]++
-		
-		// エンジンタイプを取得
-		if instance.Engine != nil {
-			instanceEngines[instanceType] = *instance.Engine
+		result, err := svc.DescribeDomains(ctx, &opensearch.DescribeDomainsInput{
+			DomainNames: domainNames[start:end],
+		})
+		if err != nil {
+			return nil, err
 		}
-		
-		// マルチAZ設定を取得
-		instanceMultiAZs[instanceType] = *instance.MultiAZ
+
+		for _, domain := range result.DomainStatusList {
+			if domain.ClusterConfig == nil {
+				continue
+			}
+
+			// タグを取得（OpenSearchはドメイン情報にタグが含まれないため個別に取得する）
+			var tags map[string]string
+			if c.needsTags() {
+				tagsResult, err := svc.ListTags(ctx, &opensearch.ListTagsInput{
+					ARN: domain.ARN,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to list tags for %s: %w", aws.ToString(domain.DomainName), err)
+				}
+				tags = make(map[string]string)
+				for _, tag := range tagsResult.TagList {
+					tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+			}
+
+			clusterConfig := domain.ClusterConfig
+			status := openSearchDomainStatus(domain.Created, domain.Deleted, domain.Processing)
+
+			instances = append(instances, InstanceInfo{
+				ServiceType:  "opensearch",
+				InstanceType: string(clusterConfig.InstanceType),
+				Count:        int(aws.ToInt32(clusterConfig.InstanceCount)),
+				Description:  "data",
+				Tags:         tags,
+				ResourceID:   aws.ToString(domain.DomainName),
+				Status:       status,
+			})
+
+			if aws.ToBool(clusterConfig.DedicatedMasterEnabled) {
+				instances = append(instances, InstanceInfo{
+					ServiceType:  "opensearch",
+					InstanceType: string(clusterConfig.DedicatedMasterType),
+					Count:        int(aws.ToInt32(clusterConfig.DedicatedMasterCount)),
+					Description:  "master",
+					Tags:         tags,
+					ResourceID:   aws.ToString(domain.DomainName),
+					Status:       status,
+				})
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// openSearchDomainStatus はOpenSearchドメインの状態を表す文字列を返す
+func openSearchDomainStatus(created, deleted, processing *bool) string {
+	switch {
+	case aws.ToBool(deleted):
+		return "deleted"
+	case !aws.ToBool(created):
+		return "creating"
+	case aws.ToBool(processing):
+		return "processing"
+	default:
+		return "active"
 	}
+}
+
+// getRedshiftNodes はRedshiftのプロビジョニング済みクラスタのノード情報を取得する
+// Redshift Serverlessはリザーブドノードの対象外のため取得しない
+func (c *GenerateCommand) getRedshiftNodes(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+	svc := redshift.NewFromConfig(cfg)
 
-	// InstanceInfo構造体に変換
 	var instances []InstanceInfo
-	for instanceType, count := range instanceCounts {
-		// エンジンタイプを取得（なければデフォルト値を使用）
-		engine, ok := instanceEngines[instanceType]
-		if !ok {
-			engine = c.opts.RDSEngine
+	paginator := redshift.NewDescribeClustersPaginator(svc, &redshift.DescribeClustersInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
 		}
-		
-		// マルチAZ設定を取得
-		multiAZ, ok := instanceMultiAZs[instanceType]
-		if !ok {
-			multiAZ = false
+
+		for _, cluster := range result.Clusters {
+			// タグを取得
+			var tags map[string]string
+			if c.needsTags() {
+				tags = make(map[string]string)
+				for _, tag := range cluster.Tags {
+					tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+			}
+
+			instances = append(instances, InstanceInfo{
+				ServiceType:  "redshift",
+				InstanceType: aws.ToString(cluster.NodeType),
+				Count:        int(aws.ToInt32(cluster.NumberOfNodes)),
+				Tags:         tags,
+				ResourceID:   aws.ToString(cluster.ClusterIdentifier),
+				Status:       aws.ToString(cluster.ClusterStatus),
+				CreatedAt:    aws.ToTime(cluster.ClusterCreateTime),
+			})
 		}
+	}
 
-		instances = append(instances, InstanceInfo{
-			ServiceType:  "rds",
-			InstanceType: instanceType,
-			Count:        count,
-			Description:  engine,
-			MultiAz:      multiAZ,
-		})
+	return instances, nil
+}
+
+// getMemoryDBNodes はMemoryDBクラスタのノード情報を取得する
+// ノード数は全シャードのプライマリとレプリカの合計
+func (c *GenerateCommand) getMemoryDBNodes(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+	svc := memorydb.NewFromConfig(cfg)
+
+	var instances []InstanceInfo
+	paginator := memorydb.NewDescribeClustersPaginator(svc, &memorydb.DescribeClustersInput{
+		ShowShardDetails: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cluster := range result.Clusters {
+			count := 0
+			for _, shard := range cluster.Shards {
+				count += int(aws.ToInt32(shard.NumberOfNodes))
+			}
+
+			// タグを取得（MemoryDBはクラスタ情報にタグが含まれないため個別に取得する）
+			var tags map[string]string
+			if c.needsTags() {
+				tagsResult, err := svc.ListTags(ctx, &memorydb.ListTagsInput{
+					ResourceArn: cluster.ARN,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to list tags for %s: %w", aws.ToString(cluster.Name), err)
+				}
+				tags = make(map[string]string)
+				for _, tag := range tagsResult.TagList {
+					tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+			}
+
+			instances = append(instances, InstanceInfo{
+				ServiceType:  "memorydb",
+				InstanceType: aws.ToString(cluster.NodeType),
+				Count:        count,
+				Description:  aws.ToString(cluster.Engine),
+				Tags:         tags,
+				ResourceID:   aws.ToString(cluster.Name),
+				Status:       aws.ToString(cluster.Status),
+			})
+		}
 	}
 
 	return instances, nil
 }
 
-// getElastiCacheInstances はElastiCacheインスタンス情報を取得する
-func (c *GenerateCommand) getElastiCacheInstances(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
-	svc := elasticache.NewFromConfig(cfg)
-	result, err := svc.DescribeCacheClusters(ctx, &elasticache.DescribeCacheClustersInput{})
-	if err != nil {
-		return nil, err
+// getDocDBInstances はDocumentDBインスタンス情報を取得する
+func (c *GenerateCommand) getDocDBInstances(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+	svc := docdb.NewFromConfig(cfg)
+
+	var instances []InstanceInfo
+	// DocumentDBのAPIはRDSのインスタンスも返すため、エンジンで絞り込む
+	paginator := docdb.NewDescribeDBInstancesPaginator(svc, &docdb.DescribeDBInstancesInput{
+		Filters: []docdbTypes.Filter{
+			{Name: aws.String("engine"), Values: []string{docDBEngine.serviceType}},
+		},
+	})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instance := range result.DBInstances {
+			// タグを取得（インスタンス情報にタグが含まれないため個別に取得する）
+			var tags map[string]string
+			if c.needsTags() {
+				tagsResult, err := svc.ListTagsForResource(ctx, &docdb.ListTagsForResourceInput{
+					ResourceName: instance.DBInstanceArn,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to list tags for %s: %w", aws.ToString(instance.DBInstanceIdentifier), err)
+				}
+				tags = make(map[string]string)
+				for _, tag := range tagsResult.TagList {
+					tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+			}
+
+			instanceClass := aws.ToString(instance.DBInstanceClass)
+			instances = append(instances, InstanceInfo{
+				ServiceType:  docDBEngine.serviceType,
+				InstanceType: instanceClass,
+				Count:        1,
+				Description:  docDBEngine.productDescription,
+				Tags:         tags,
+				ResourceID:   aws.ToString(instance.DBInstanceIdentifier),
+				Status:       rdsCompatibleInstanceStatus(instanceClass, aws.ToString(instance.DBInstanceStatus)),
+				CreatedAt:    aws.ToTime(instance.InstanceCreateTime),
+			})
+		}
 	}
 
-	// インスタンスタイプごとにカウント
-	instanceCounts := make(map[string]int)
-	instanceEngines := make(map[string]string)
+	return instances, nil
+}
+
+// getNeptuneInstances はNeptuneインスタンス情報を取得する
+func (c *GenerateCommand) getNeptuneInstances(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+	svc := neptune.NewFromConfig(cfg)
 
-	for _, cluster := range result.CacheClusters {
-		instanceType := *cluster.CacheNodeType
-		instanceCounts[instanceType]++
-		
-		// エンジンタイプを取得
-		if cluster.Engine != nil {
-			instanceEngines[instanceType] = *cluster.Engine
+	var instances []InstanceInfo
+	// NeptuneのAPIはRDSのインスタンスも返すため、エンジンで絞り込む
+	paginator := neptune.NewDescribeDBInstancesPaginator(svc, &neptune.DescribeDBInstancesInput{
+		Filters: []neptuneTypes.Filter{
+			{Name: aws.String("engine"), Values: []string{neptuneEngine.serviceType}},
+		},
+	})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instance := range result.DBInstances {
+			// タグを取得（インスタンス情報にタグが含まれないため個別に取得する）
+			var tags map[string]string
+			if c.needsTags() {
+				tagsResult, err := svc.ListTagsForResource(ctx, &neptune.ListTagsForResourceInput{
+					ResourceName: instance.DBInstanceArn,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to list tags for %s: %w", aws.ToString(instance.DBInstanceIdentifier), err)
+				}
+				tags = make(map[string]string)
+				for _, tag := range tagsResult.TagList {
+					tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+			}
+
+			instanceClass := aws.ToString(instance.DBInstanceClass)
+			instances = append(instances, InstanceInfo{
+				ServiceType:  neptuneEngine.serviceType,
+				InstanceType: instanceClass,
+				Count:        1,
+				Description:  neptuneEngine.productDescription,
+				Tags:         tags,
+				ResourceID:   aws.ToString(instance.DBInstanceIdentifier),
+				Status:       rdsCompatibleInstanceStatus(instanceClass, aws.ToString(instance.DBInstanceStatus)),
+				CreatedAt:    aws.ToTime(instance.InstanceCreateTime),
+			})
 		}
 	}
 
-	// InstanceInfo構造体に変換
+	return instances, nil
+}
+
+// rdsCompatibleInstanceStatus はDocumentDBとNeptuneのインスタンスの状態を返す
+// サーバーレスインスタンスはリザーブドインスタンスの対象外のため、除外理由を表す状態にする
+func rdsCompatibleInstanceStatus(instanceClass, status string) string {
+	if instanceClass == "db.serverless" {
+		return "serverless"
+	}
+	return status
+}
+
+// getDynamoDBTables はDynamoDBテーブルのプロビジョンドキャパシティを取得する
+// InstanceTypeはキャパシティの種類（read または write）、Countはグローバルセカンダリインデックスを含むユニット数
+// オンデマンドキャパシティモードとStandard-IAテーブルクラスのテーブルはリザーブドキャパシティの対象外のため、除外理由を表す状態にする
+func (c *GenerateCommand) getDynamoDBTables(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+	svc := dynamodb.NewFromConfig(cfg)
+
 	var instances []InstanceInfo
-	for instanceType, count := range instanceCounts {
-		// エンジンタイプを取得（なければデフォルト値を使用）
-		engine, ok := instanceEngines[instanceType]
-		if !ok {
-			engine = c.opts.ElastiCacheEngine
+	paginator := dynamodb.NewListTablesPaginator(svc, &dynamodb.ListTablesInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tableName := range result.TableNames {
+			described, err := svc.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+				TableName: aws.String(tableName),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe table %s: %w", tableName, err)
+			}
+			table := described.Table
+
+			status := string(table.TableStatus)
+			if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode == dynamodbTypes.BillingModePayPerRequest {
+				status = "on-demand"
+			} else if table.TableClassSummary != nil && table.TableClassSummary.TableClass == dynamodbTypes.TableClassStandardInfrequentAccess {
+				status = "standard-ia"
+			}
+
+			// テーブルとグローバルセカンダリインデックスのキャパシティを合計
+			rcu, wcu := provisionedUnits(table.ProvisionedThroughput)
+			for _, index := range table.GlobalSecondaryIndexes {
+				indexRCU, indexWCU := provisionedUnits(index.ProvisionedThroughput)
+				rcu += indexRCU
+				wcu += indexWCU
+			}
+
+			// タグを取得（DynamoDBはテーブル情報にタグが含まれないため個別に取得する）
+			var tags map[string]string
+			if c.needsTags() {
+				tagsResult, err := svc.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{
+					ResourceArn: table.TableArn,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("failed to list tags for %s: %w", tableName, err)
+				}
+				tags = make(map[string]string)
+				for _, tag := range tagsResult.Tags {
+					tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+			}
+
+			for _, capacity := range []struct {
+				capacityType string
+				units        int
+			}{{"read", rcu}, {"write", wcu}} {
+				// オンデマンドキャパシティモードのテーブルは除外理由を表示するため0ユニットでも含める
+				if capacity.units == 0 && status != "on-demand" {
+					continue
+				}
+				instances = append(instances, InstanceInfo{
+					ServiceType:  "dynamodb",
+					InstanceType: capacity.capacityType,
+					Count:        capacity.units,
+					Tags:         tags,
+					ResourceID:   tableName,
+					Status:       status,
+					CreatedAt:    aws.ToTime(table.CreationDateTime),
+				})
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// provisionedUnits はプロビジョンドキャパシティの読み込み・書き込みユニット数を返す
+func provisionedUnits(throughput *dynamodbTypes.ProvisionedThroughputDescription) (int, int) {
+	if throughput == nil {
+		return 0, 0
+	}
+	return int(aws.ToInt64(throughput.ReadCapacityUnits)), int(aws.ToInt64(throughput.WriteCapacityUnits))
+}
+
+// getEC2Instances はEC2インスタンス情報を取得する
+// 停止中のインスタンスも除外理由を表示するために取得する
+func (c *GenerateCommand) getEC2Instances(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+	svc := ec2.NewFromConfig(cfg)
+
+	var instances []InstanceInfo
+	paginator := ec2.NewDescribeInstancesPaginator(svc, &ec2.DescribeInstancesInput{
+		Filters: []ec2Types.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []string{"pending", "running", "stopping", "stopped"},
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
 		}
 
-		instances = append(instances, InstanceInfo{
-			ServiceType:  "elasticache",
-			InstanceType: instanceType,
-			Count:        count,
-			Description:  engine,
-			MultiAz:      false, // ElastiCacheはMultiAzの概念が異なる
+		for _, reservation := range result.Reservations {
+			for _, instance := range reservation.Instances {
+				// スポットインスタンスはRI/Savings Plansの対象外
+				if instance.InstanceLifecycle == ec2Types.InstanceLifecycleTypeSpot {
+					continue
+				}
+
+				// タグを取得
+				var tags map[string]string
+				if c.needsTags() {
+					tags = make(map[string]string)
+					for _, tag := range instance.Tags {
+						tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+					}
+				}
+
+				instances = append(instances, InstanceInfo{
+					ServiceType:  "ec2",
+					InstanceType: string(instance.InstanceType),
+					Count:        1,
+					Description:  aws.ToString(instance.PlatformDetails),
+					Tags:         tags,
+					ResourceID:   aws.ToString(instance.InstanceId),
+					Status:       stateName(instance.State),
+					CreatedAt:    aws.ToTime(instance.LaunchTime),
+				})
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// getFargateTasks は稼働中のFargateタスク情報を取得する
+// タスクはデプロイのたびに入れ替わるため、作成日時による除外の対象外とする
+// InstanceTypeは "vCPU(ミリコア)/メモリ(MB)" (例: "1024/2048")、Descriptionはアーキテクチャ（x86_64 または arm）
+func (c *GenerateCommand) getFargateTasks(ctx context.Context, cfg aws.Config) ([]InstanceInfo, error) {
+	svc := ecs.NewFromConfig(cfg)
+
+	var clusterArns []string
+	clusterPaginator := ecs.NewListClustersPaginator(svc, &ecs.ListClustersInput{})
+	for clusterPaginator.HasMorePages() {
+		result, err := clusterPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		clusterArns = append(clusterArns, result.ClusterArns...)
+	}
+
+	var instances []InstanceInfo
+	for _, clusterArn := range clusterArns {
+		var taskArns []string
+		taskPaginator := ecs.NewListTasksPaginator(svc, &ecs.ListTasksInput{
+			Cluster:       aws.String(clusterArn),
+			LaunchType:    ecsTypes.LaunchTypeFargate,
+			DesiredStatus: ecsTypes.DesiredStatusRunning,
 		})
+		for taskPaginator.HasMorePages() {
+			result, err := taskPaginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			taskArns = append(taskArns, result.TaskArns...)
+		}
+
+		// DescribeTasksは一度に100件まで
+		for start := 0; start < len(taskArns); start += 100 {
+			end := start + 100
+			if end > len(taskArns) {
+				end = len(taskArns)
+			}
+
+			input := &ecs.DescribeTasksInput{
+				Cluster: aws.String(clusterArn),
+				Tasks:   taskArns[start:end],
+			}
+			if c.needsTags() {
+				input.Include = []ecsTypes.TaskField{ecsTypes.TaskFieldTags}
+			}
+			result, err := svc.DescribeTasks(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, task := range result.Tasks {
+				// Fargate SpotはSavings Plansの対象外
+				if aws.ToString(task.CapacityProviderName) == "FARGATE_SPOT" {
+					continue
+				}
+
+				architecture := "x86_64"
+				for _, attribute := range task.Attributes {
+					if aws.ToString(attribute.Name) == "ecs.cpu-architecture" && aws.ToString(attribute.Value) == "arm64" {
+						architecture = "arm"
+					}
+				}
+
+				// タグを取得
+				var tags map[string]string
+				if c.needsTags() {
+					tags = make(map[string]string)
+					for _, tag := range task.Tags {
+						tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+					}
+				}
+
+				instances = append(instances, InstanceInfo{
+					ServiceType:  "fargate",
+					InstanceType: fmt.Sprintf("%s/%s", aws.ToString(task.Cpu), aws.ToString(task.Memory)),
+					Count:        1,
+					Description:  architecture,
+					Tags:         tags,
+				})
+			}
+		}
 	}
 
 	return instances, nil
@@ -180,81 +1029,99 @@ func (c *GenerateCommand) formatOutput(instances []InstanceInfo, format string)
 // formatCommandOutput はコマンド形式で出力を生成する
 func (c *GenerateCommand) formatCommandOutput(instances []InstanceInfo) string {
 	args := c.formatArgsOutput(instances)
-	return fmt.Sprintf("%s", args)
+	return fmt.Sprintf("awsri total %s --duration=%d --offering-type=%q", args, c.opts.Duration, c.opts.OfferingType)
 }
 
 // formatArgsOutput は引数のみの形式で出力を生成する
 func (c *GenerateCommand) formatArgsOutput(instances []InstanceInfo) string {
 	var rdsArgs []string
 	var elasticacheArgs []string
+	var openSearchArgs []string
+	var redshiftArgs []string
+	var memoryDBArgs []string
+	var dynamoDBArgs []string
+	var docDBArgs []string
+	var neptuneArgs []string
 
 	for _, instance := range instances {
+		// プレフィックスを削除
+		instanceType := trimInstanceTypePrefix(instance.ServiceType, instance.InstanceType)
+
+		// リージョンが分かっている場合は末尾に付与する
+		region := ""
+		if instance.Region != "" {
+			region = ":" + instance.Region
+		}
+
 		switch instance.ServiceType {
 		case "rds":
-			// RDSインスタンスの引数形式: instance-type:count:product-description:multi-az
-			// db.プレフィックスを削除
-			instanceType := strings.TrimPrefix(instance.InstanceType, "db.")
-			rdsArgs = append(rdsArgs, fmt.Sprintf("--rds=%s:%d:%s:%t", 
-				instanceType, instance.Count, instance.Description, instance.MultiAz))
+			// RDSインスタンスの引数形式: instance-type:count:product-description:multi-az[:region]
+			rdsArgs = append(rdsArgs, fmt.Sprintf("--rds=%s:%d:%s:%t%s",
+				instanceType, instance.Count, instance.Description, instance.MultiAz, region))
 		case "elasticache":
-			// ElastiCacheインスタンスの引数形式: node-type:count:product-description
-			// cache.プレフィックスを削除
-			instanceType := strings.TrimPrefix(instance.InstanceType, "cache.")
-			elasticacheArgs = append(elasticacheArgs, fmt.Sprintf("--elasticache=%s:%d:%s", 
-				instanceType, instance.Count, instance.Description))
+			// ElastiCacheインスタンスの引数形式: node-type:count:product-description[:region]
+			elasticacheArgs = append(elasticacheArgs, fmt.Sprintf("--elasticache=%s:%d:%s%s",
+				instanceType, instance.Count, instance.Description, region))
+		case "opensearch":
+			// OpenSearchインスタンスの引数形式: instance-type:count[:region]
+			openSearchArgs = append(openSearchArgs, fmt.Sprintf("--opensearch=%s:%d%s",
+				instanceType, instance.Count, region))
+		case "redshift":
+			// Redshiftノードの引数形式: node-type:count[:region]
+			// RA3/DC2以外のノードタイプはリザーブドノードを購入できないため出力しない
+			if !isRedshiftReservable(instanceType) {
+				continue
+			}
+			redshiftArgs = append(redshiftArgs, fmt.Sprintf("--redshift=%s:%d%s",
+				instanceType, instance.Count, region))
+		case "memorydb":
+			// MemoryDBノードの引数形式: node-type:count[:region]
+			memoryDBArgs = append(memoryDBArgs, fmt.Sprintf("--memorydb=%s:%d%s",
+				instanceType, instance.Count, region))
+		case "dynamodb":
+			// DynamoDBキャパシティの引数形式: read|write:units[:region]
+			dynamoDBArgs = append(dynamoDBArgs, fmt.Sprintf("--dynamodb=%s:%d%s",
+				instanceType, instance.Count, region))
+		case "docdb":
+			// DocumentDBインスタンスの引数形式: instance-class:count[:region]
+			docDBArgs = append(docDBArgs, fmt.Sprintf("--docdb=%s:%d%s",
+				instanceType, instance.Count, region))
+		case "neptune":
+			// Neptuneインスタンスの引数形式: instance-class:count[:region]
+			neptuneArgs = append(neptuneArgs, fmt.Sprintf("--neptune=%s:%d%s",
+				instanceType, instance.Count, region))
 		}
 	}
 
-	return strings.Join(append(rdsArgs, elasticacheArgs...), " ")
+	args := append(rdsArgs, elasticacheArgs...)
+	args = append(args, openSearchArgs...)
+	args = append(args, redshiftArgs...)
+	args = append(args, memoryDBArgs...)
+	args = append(args, dynamoDBArgs...)
+	args = append(args, docDBArgs...)
+	args = append(args, neptuneArgs...)
+	return strings.Join(args, " ")
 }
 
-// formatJSONOutput はJSON形式で出力を生成する
+// formatJSONOutput はJSON形式（totalコマンドの--manifestで読み込めるマニフェスト）で出力を生成する
 func (c *GenerateCommand) formatJSONOutput(instances []InstanceInfo) (string, error) {
-	// JSON出力用の構造体
-	type OutputInstance struct {
-		ServiceType  string `json:"service_type"`
-		InstanceType string `json:"instance_type"`
-		Count        int    `json:"count"`
-		Description  string `json:"description"`
-		MultiAz      bool   `json:"multi_az,omitempty"`
-	}
-
-	type OutputData struct {
-		Instances    []OutputInstance `json:"instances"`
-		Duration     int              `json:"duration"`
-		OfferingType string           `json:"offering_type"`
-	}
-
 	// 出力データを作成
-	outputData := OutputData{
-		Duration:     c.opts.Duration,
-		OfferingType: c.opts.OfferingType,
-		Instances:    make([]OutputInstance, 0, len(instances)),
-	}
-
-	for _, instance := range instances {
-		// プレフィックスを削除
-		instanceType := instance.InstanceType
-		if instance.ServiceType == "rds" {
-			instanceType = strings.TrimPrefix(instanceType, "db.")
-		} else if instance.ServiceType == "elasticache" {
-			instanceType = strings.TrimPrefix(instanceType, "cache.")
-		}
-
-		outputData.Instances = append(outputData.Instances, OutputInstance{
-			ServiceType:  instance.ServiceType,
-			InstanceType: instanceType,
-			Count:        instance.Count,
-			Description:  instance.Description,
-			MultiAz:      instance.MultiAz,
-		})
-	}
+	manifest := NewManifest(instances, c.opts.Duration, c.opts.OfferingType)
+	manifest.Skipped = c.skipped
 
 	// JSONに変換
-	jsonData, err := json.MarshalIndent(outputData, "", "  ")
+	jsonData, err := json.MarshalIndent(manifest, "", "  ")
 	if err != nil {
 		return "", err
 	}
 
 	return string(jsonData), nil
-}
\ No newline at end of file
+}
+
+// stateName はEC2インスタンスの状態名を返す
+func stateName(state *ec2Types.InstanceState) string {
+	if state == nil {
+		return ""
+	}
+	return string(state.Name)
+}
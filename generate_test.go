@@ -1,7 +1,15 @@
 package awsri
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
 )
 
 func TestFormatOutput(t *testing.T) {
@@ -55,4 +63,247 @@ func TestFormatOutput(t *testing.T) {
 	if len(jsonOutput) == 0 {
 		t.Error("JSON output is empty")
 	}
-}
\ No newline at end of file
+}
+
+func TestFormatOutputWithRegion(t *testing.T) {
+	cmd := NewGenerateCommand(GenerateOption{
+		Duration:     1,
+		OfferingType: "Partial Upfront",
+	})
+
+	instances := []InstanceInfo{
+		{
+			ServiceType:  "rds",
+			InstanceType: "db.m5.large",
+			Count:        2,
+			Description:  "postgresql",
+			MultiAz:      true,
+			Region:       "us-east-1",
+		},
+		{
+			ServiceType:  "elasticache",
+			InstanceType: "cache.m5.large",
+			Count:        1,
+			Description:  "redis",
+			Region:       "ap-northeast-1",
+		},
+	}
+
+	argsOutput, err := cmd.formatOutput(instances, "args")
+	if err != nil {
+		t.Fatalf("Failed to format args output: %v", err)
+	}
+	expectedArgs := `--rds=m5.large:2:postgresql:true:us-east-1 --elasticache=m5.large:1:redis:ap-northeast-1`
+	if argsOutput != expectedArgs {
+		t.Errorf("Args output mismatch.\nExpected: %s\nGot: %s", expectedArgs, argsOutput)
+	}
+
+	// 生成したargsをtotalコマンドで解析できること
+	total := NewTotalCommand(TotalOption{
+		RDSInstances:         []string{"m5.large:2:postgresql:true:us-east-1"},
+		ElasticacheInstances: []string{"m5.large:1:redis:ap-northeast-1"},
+	})
+	parsed, err := total.parseInstancesInfo()
+	if err != nil {
+		t.Fatalf("Failed to parse generated args: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, instances) {
+		t.Errorf("Parsed instances mismatch.\nExpected: %+v\nGot: %+v", instances, parsed)
+	}
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	cmd := NewGenerateCommand(GenerateOption{
+		Duration:     3,
+		OfferingType: "All Upfront",
+	})
+
+	instances := []InstanceInfo{
+		{
+			ServiceType:  "rds",
+			InstanceType: "db.r6g.large",
+			Count:        3,
+			Description:  "mysql",
+			MultiAz:      true,
+			Region:       "eu-west-1",
+		},
+		{
+			ServiceType:  "elasticache",
+			InstanceType: "cache.r6g.large",
+			Count:        2,
+			Description:  "redis",
+			Region:       "us-east-1",
+		},
+	}
+
+	jsonOutput, err := cmd.formatOutput(instances, "json")
+	if err != nil {
+		t.Fatalf("Failed to format JSON output: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, []byte(jsonOutput), 0o644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	manifest, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("Failed to load manifest: %v", err)
+	}
+	if manifest.Duration != 3 || manifest.OfferingType != "All Upfront" {
+		t.Errorf("Unexpected manifest options: %+v", manifest)
+	}
+	if manifest.Instances[0].InstanceType != "r6g.large" {
+		t.Errorf("Expected prefix to be trimmed in manifest, got: %s", manifest.Instances[0].InstanceType)
+	}
+
+	loaded := manifest.InstanceInfos()
+	if !reflect.DeepEqual(loaded, instances) {
+		t.Errorf("Manifest round trip mismatch.\nExpected: %+v\nGot: %+v", instances, loaded)
+	}
+
+	// total uses the manifest's duration and offering type unless the flags are given
+	total := NewTotalCommand(TotalOption{})
+	total.applyPurchaseDefaults(manifest)
+	if total.opts.Duration != 3 || total.opts.OfferingType != "All Upfront" {
+		t.Errorf("Expected the manifest options, got %d %s", total.opts.Duration, total.opts.OfferingType)
+	}
+	total = NewTotalCommand(TotalOption{Duration: 1})
+	total.applyPurchaseDefaults(manifest)
+	if total.opts.Duration != 1 || total.opts.OfferingType != "All Upfront" {
+		t.Errorf("Expected --duration to win over the manifest, got %d %s", total.opts.Duration, total.opts.OfferingType)
+	}
+	total = NewTotalCommand(TotalOption{})
+	total.applyPurchaseDefaults(nil)
+	if total.opts.Duration != 1 || total.opts.OfferingType != "Partial Upfront" {
+		t.Errorf("Expected the defaults without a manifest, got %d %s", total.opts.Duration, total.opts.OfferingType)
+	}
+}
+
+func TestApplyTagOptions(t *testing.T) {
+	cmd := NewGenerateCommand(GenerateOption{
+		IncludeTags: []string{"env=prod"},
+		ExcludeTags: []string{"reserve=false"},
+		GroupByTag:  "team",
+	})
+
+	instances := []InstanceInfo{
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, Description: "postgres", Tags: map[string]string{"env": "prod", "team": "payments"}},
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, Description: "postgres", Tags: map[string]string{"env": "prod", "team": "payments", "Name": "db-2"}},
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, Description: "postgres", Tags: map[string]string{"env": "prod", "team": "search"}},
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, Description: "postgres", Tags: map[string]string{"env": "prod"}},
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, Description: "postgres", Tags: map[string]string{"env": "staging", "team": "payments"}},
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, Description: "postgres", Tags: map[string]string{"env": "prod", "team": "payments", "reserve": "false"}},
+	}
+
+	filtered, err := cmd.applyTagOptions(instances)
+	if err != nil {
+		t.Fatalf("Failed to apply tag options: %v", err)
+	}
+
+	got := aggregateInstances(filtered)
+	expected := []InstanceInfo{
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, Description: "postgres", Tags: map[string]string{"team": ""}},
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 2, Description: "postgres", Tags: map[string]string{"team": "payments"}},
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, Description: "postgres", Tags: map[string]string{"team": "search"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Grouped instances mismatch.\nExpected: %+v\nGot: %+v", expected, got)
+	}
+}
+
+func TestFilterSteadyInstances(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	instances := []InstanceInfo{
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, ResourceID: "old-db", Status: "available", CreatedAt: now.AddDate(0, -6, 0)},
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, ResourceID: "new-db", Status: "available", CreatedAt: now.AddDate(0, 0, -10)},
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 1, ResourceID: "stopped-db", Status: "stopped", CreatedAt: now.AddDate(-1, 0, 0)},
+		{ServiceType: "elasticache", InstanceType: "cache.m5.large", Count: 2, ResourceID: "cache-1", Status: "snapshotting", CreatedAt: now.AddDate(-1, 0, 0)},
+		{ServiceType: "ec2", InstanceType: "m5.large", Count: 1, ResourceID: "i-0123", Status: "stopped"},
+		{ServiceType: "neptune", InstanceType: "db.serverless", Count: 1, ResourceID: "graph-1", Status: rdsCompatibleInstanceStatus("db.serverless", "available"), CreatedAt: now.AddDate(-1, 0, 0)},
+		{ServiceType: "fargate", InstanceType: "1024/2048", Count: 1, Description: "x86_64", CreatedAt: now.Add(-time.Hour)},
+	}
+
+	minAge, err := parseAge("90d")
+	if err != nil {
+		t.Fatalf("Failed to parse age: %v", err)
+	}
+	kept, skipped := filterSteadyInstances(instances, minAge, now)
+
+	var keptIDs []string
+	for _, instance := range kept {
+		keptIDs = append(keptIDs, instance.ResourceID)
+	}
+	if expected := []string{"old-db", "cache-1", ""}; !reflect.DeepEqual(keptIDs, expected) {
+		t.Errorf("Kept instances mismatch. Expected: %v, Got: %v", expected, keptIDs)
+	}
+
+	reasons := make(map[string]string)
+	for _, s := range skipped {
+		reasons[s.ResourceID] = s.Reason
+	}
+	expected := map[string]string{
+		"new-db":     "created 2024-05-22, younger than 90d",
+		"stopped-db": "status is stopped",
+		"i-0123":     "status is stopped",
+		"graph-1":    "serverless instances cannot use reserved instances",
+	}
+	if !reflect.DeepEqual(reasons, expected) {
+		t.Errorf("Skipped reasons mismatch.\nExpected: %v\nGot: %v", expected, reasons)
+	}
+
+	if _, err := parseAge("3 months"); err == nil {
+		t.Error("Expected error for invalid age")
+	}
+}
+
+func TestFormatOutputNodeServices(t *testing.T) {
+	cmd := NewGenerateCommand(GenerateOption{})
+
+	instances := []InstanceInfo{
+		{ServiceType: "opensearch", InstanceType: "r6g.large.search", Count: 3, Region: "us-east-1"},
+		{ServiceType: "redshift", InstanceType: "ra3.xlplus", Count: 2, Region: "us-east-1"},
+		{ServiceType: "memorydb", InstanceType: "db.r6g.large", Count: 4, Region: "ap-northeast-1"},
+		{ServiceType: "docdb", InstanceType: "db.r6g.xlarge", Count: 3, Description: "docdb", Region: "ap-northeast-1"},
+		{ServiceType: "neptune", InstanceType: "db.r5.large", Count: 2, Description: "neptune", Region: "us-west-2"},
+	}
+
+	argsOutput := cmd.formatArgsOutput(instances)
+	expectedArgs := `--opensearch=r6g.large.search:3:us-east-1 --redshift=ra3.xlplus:2:us-east-1 --memorydb=r6g.large:4:ap-northeast-1 ` +
+		`--docdb=r6g.xlarge:3:ap-northeast-1 --neptune=r5.large:2:us-west-2`
+	if argsOutput != expectedArgs {
+		t.Errorf("Args output mismatch.\nExpected: %s\nGot: %s", expectedArgs, argsOutput)
+	}
+
+	// 生成したargsをtotalコマンドで解析できること
+	total := NewTotalCommand(TotalOption{
+		OpenSearchInstances: []string{"r6g.large.search:3:us-east-1"},
+		RedshiftNodes:       []string{"ra3.xlplus:2:us-east-1"},
+		MemoryDBNodes:       []string{"r6g.large:4:ap-northeast-1"},
+		DocDBInstances:      []string{"r6g.xlarge:3:ap-northeast-1"},
+		NeptuneInstances:    []string{"r5.large:2:us-west-2"},
+	})
+	parsed, err := total.parseInstancesInfo()
+	if err != nil {
+		t.Fatalf("Failed to parse generated args: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, instances) {
+		t.Errorf("Parsed instances mismatch.\nExpected: %+v\nGot: %+v", instances, parsed)
+	}
+}
+
+func TestIsAccessDeniedError(t *testing.T) {
+	denied := fmt.Errorf("operation error ECS: ListClusters: %w", &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized"})
+	if !isAccessDeniedError(denied) {
+		t.Error("Expected AccessDeniedException to be skipped")
+	}
+	if !isAccessDeniedError(&smithy.GenericAPIError{Code: "UnauthorizedOperation"}) {
+		t.Error("Expected EC2 UnauthorizedOperation to be skipped")
+	}
+	if isAccessDeniedError(&smithy.GenericAPIError{Code: "ThrottlingException"}) {
+		t.Error("Expected throttling to fail the scan")
+	}
+	if isAccessDeniedError(errors.New("connection reset")) {
+		t.Error("Expected other errors to fail the scan")
+	}
+}
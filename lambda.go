@@ -0,0 +1,272 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans"
+	savingsplansTypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+)
+
+// lambdaUsage はCompute Savings Plansの対象となるLambdaの料金
+type lambdaUsage struct {
+	pricingGroup string // Pricing APIのgroup属性
+	usageType    string // リージョンのプレフィックスを除いたSavings Planのレートの使用タイプ
+}
+
+// Compute Savings Plansの対象となるLambdaの料金（x86_64。ARMはどちらにも"-ARM"を付ける）
+var (
+	lambdaDurationUsage               = lambdaUsage{pricingGroup: "AWS-Lambda-Duration", usageType: "Lambda-GB-Second"}
+	lambdaProvisionedConcurrencyUsage = lambdaUsage{pricingGroup: "AWS-Lambda-Provisioned-Concurrency", usageType: "Lambda-Provisioned-Concurrency"}
+	lambdaProvisionedDurationUsage    = lambdaUsage{pricingGroup: "AWS-Lambda-Duration-Provisioned", usageType: "Lambda-Provisioned-GB-Second"}
+)
+
+type LambdaOption struct {
+	Region                       string  `name:"region" default:"ap-northeast-1" help:"AWS region"`
+	GBSecondsPerMonth            float64 `name:"gb-seconds-per-month" help:"Duration in GB-seconds per month of on-demand invocations"`
+	ProvisionedConcurrency       int     `name:"provisioned-concurrency" help:"Number of provisioned concurrent executions kept all month"`
+	ProvisionedMemoryMB          float64 `name:"provisioned-memory-mb" default:"1024" help:"Memory MB of each provisioned concurrent execution"`
+	ProvisionedGBSecondsPerMonth float64 `name:"provisioned-gb-seconds-per-month" help:"Duration in GB-seconds per month of invocations running on provisioned concurrency"`
+	Duration                     int     `name:"duration" default:"1" help:"Duration in years (1 or 3)"`
+	Architecture                 string  `name:"architecture" default:"x86_64" help:"Architecture (x86_64 or arm)"`
+	PaymentOption                string  `name:"payment-option" default:"no-upfront" help:"Payment option (no-upfront, partial-upfront, all-upfront)"`
+	NoHeader                     bool    `name:"no-header" help:"Do not output CSV header"`
+}
+
+type LambdaCommand struct {
+	opts LambdaOption
+}
+
+func NewLambdaCommand(opts LambdaOption) *LambdaCommand {
+	return &LambdaCommand{opts: opts}
+}
+
+func (c *LambdaCommand) Run(ctx context.Context) error {
+	// 期間の検証（1年または3年のみ）
+	if c.opts.Duration != 1 && c.opts.Duration != 3 {
+		return fmt.Errorf("duration must be 1 or 3 years, got: %d", c.opts.Duration)
+	}
+	if c.opts.Architecture != "x86_64" && c.opts.Architecture != "arm" {
+		return fmt.Errorf("architecture must be x86_64 or arm, got: %s", c.opts.Architecture)
+	}
+
+	// 料金ごとの月間の使用量（GB秒）
+	// プロビジョニングされた同時実行は設定したメモリに対して1か月（720時間）課金される
+	hoursPerMonth := 720.0
+	usages := []lambdaUsage{lambdaDurationUsage, lambdaProvisionedConcurrencyUsage, lambdaProvisionedDurationUsage}
+	gbSecondsPerMonth := []float64{
+		c.opts.GBSecondsPerMonth,
+		float64(c.opts.ProvisionedConcurrency) * c.opts.ProvisionedMemoryMB / 1024.0 * hoursPerMonth * 3600,
+		c.opts.ProvisionedGBSecondsPerMonth,
+	}
+	total := 0.0
+	for _, gbSeconds := range gbSecondsPerMonth {
+		total += gbSeconds
+	}
+	if total == 0 {
+		return fmt.Errorf("no Lambda usage given (use --gb-seconds-per-month, --provisioned-concurrency or --provisioned-gb-seconds-per-month)")
+	}
+
+	// Pricing APIとSavings Plans APIはus-east-1でのみ利用可能
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config: %v", err)
+	}
+
+	// Savings Planの料金を取得
+	spRates, err := c.getComputeSavingsPlanRates(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to get Savings Plan price: %v", err)
+	}
+
+	currentCostPerMonth := 0.0
+	spCostPerMonth := 0.0
+	for i, usage := range usages {
+		gbSeconds := gbSecondsPerMonth[i]
+		if gbSeconds == 0 {
+			continue
+		}
+
+		// オンデマンド料金を取得
+		onDemandPrice, err := c.getLambdaOnDemandPrice(ctx, cfg, usage)
+		if err != nil {
+			return fmt.Errorf("failed to get on-demand price for %s: %v", usage.pricingGroup, err)
+		}
+
+		spPrice, ok := spRates[c.usageType(usage)]
+		if !ok {
+			return fmt.Errorf("Savings Plan price not found for usage type %s", c.usageType(usage))
+		}
+
+		currentCostPerMonth += gbSeconds * onDemandPrice
+		spCostPerMonth += gbSeconds * spPrice
+	}
+
+	// 時間あたりのコミットメント = Savings Plan適用後の時間あたりのコスト
+	hourlyCommitment := spCostPerMonth / hoursPerMonth
+
+	// SP/RI購入額（USD） = 時間あたりのコミットメント × 720時間 × 12か月 × 期間（年）
+	spPurchaseAmount := hourlyCommitment * hoursPerMonth * 12.0 * float64(c.opts.Duration)
+
+	// 節約額と節約率を計算
+	savingsAmount := currentCostPerMonth - spCostPerMonth
+	savingsRate := (savingsAmount / currentCostPerMonth) * 100.0
+
+	// CSVを出力
+	renderCSV(hourlyCommitment, spPurchaseAmount, currentCostPerMonth, spCostPerMonth, savingsAmount, savingsRate, c.opts.NoHeader)
+
+	return nil
+}
+
+// pricingGroup はアーキテクチャに応じた料金のPricing APIのgroupを返す
+func (c *LambdaCommand) pricingGroup(usage lambdaUsage) string {
+	if c.opts.Architecture == "arm" {
+		return usage.pricingGroup + "-ARM"
+	}
+	return usage.pricingGroup
+}
+
+// usageType はアーキテクチャに応じた料金のSavings Planの使用タイプを返す
+func (c *LambdaCommand) usageType(usage lambdaUsage) string {
+	if c.opts.Architecture == "arm" {
+		return usage.usageType + "-ARM"
+	}
+	return usage.usageType
+}
+
+// getLambdaOnDemandPrice はPricing APIからGB秒あたりのオンデマンド料金を取得する
+func (c *LambdaCommand) getLambdaOnDemandPrice(ctx context.Context, cfg aws.Config, usage lambdaUsage) (float64, error) {
+	svc := pricing.NewFromConfig(cfg)
+
+	filters := []types.Filter{
+		{
+			Field: aws.String("regionCode"),
+			Value: aws.String(c.opts.Region),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			Field: aws.String("group"),
+			Value: aws.String(c.pricingGroup(usage)),
+			Type:  types.FilterTypeTermMatch,
+		},
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AWSLambda"),
+		Filters:     filters,
+		MaxResults:  aws.Int32(100),
+	}
+
+	result, err := svc.GetProducts(ctx, input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get products: %v", err)
+	}
+
+	if len(result.PriceList) == 0 {
+		return 0, fmt.Errorf("no pricing information found for group %s in region %s", c.pricingGroup(usage), c.opts.Region)
+	}
+
+	product, err := parsePriceListProduct(result.PriceList[0])
+	if err != nil {
+		return 0, err
+	}
+
+	// 実行時間の料金は段階制のため、最初の（最も高い）段階を使う
+	price := 0.0
+	for _, term := range product.Terms.OnDemand {
+		for _, dimension := range term.PriceDimensions {
+			if p := dimension.usd(); p > price {
+				price = p
+			}
+		}
+	}
+	if price == 0 {
+		return 0, fmt.Errorf("price not found in pricing data")
+	}
+
+	return price, nil
+}
+
+// getComputeSavingsPlanRates はSavings Plans APIからLambdaのSavings Planのレートを取得する
+func (c *LambdaCommand) getComputeSavingsPlanRates(ctx context.Context, cfg aws.Config) (map[string]float64, error) {
+	svc := savingsplans.NewFromConfig(cfg)
+
+	// 引数から支払いオプションを取得
+	paymentOptionStr := c.opts.PaymentOption
+	if paymentOptionStr == "" {
+		paymentOptionStr = "no-upfront"
+	}
+
+	// 小文字のハイフン区切りの値をAWS APIの形式に変換
+	awsPaymentOption, err := convertPaymentOptionToAWSFormat(paymentOptionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &savingsplans.DescribeSavingsPlansOfferingRatesInput{
+		SavingsPlanTypes: []savingsplansTypes.SavingsPlanType{
+			savingsplansTypes.SavingsPlanTypeCompute,
+		},
+		Products: []savingsplansTypes.SavingsPlanProductType{
+			savingsplansTypes.SavingsPlanProductTypeLambda,
+		},
+		ServiceCodes: []savingsplansTypes.SavingsPlanRateServiceCode{
+			savingsplansTypes.SavingsPlanRateServiceCode("AWSLambda"),
+		},
+		SavingsPlanPaymentOptions: []savingsplansTypes.SavingsPlanPaymentOption{
+			savingsplansTypes.SavingsPlanPaymentOption(awsPaymentOption),
+		},
+		Filters: []savingsplansTypes.SavingsPlanOfferingRateFilterElement{
+			{
+				Name: savingsplansTypes.SavingsPlanRateFilterAttributeRegion,
+				Values: []string{
+					c.opts.Region,
+				},
+			},
+		},
+		MaxResults: 100,
+	}
+
+	result, err := svc.DescribeSavingsPlansOfferingRates(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe savings plans offering rates: %v", err)
+	}
+
+	durationSeconds := int64(c.opts.Duration * 365 * 24 * 60 * 60) // 年を秒に変換
+	return lambdaSavingsPlanRates(result.SearchResults, durationSeconds), nil
+}
+
+// lambdaSavingsPlanRates は期間に一致するSavings Planのレートを、リージョンのプレフィックスを除いた
+// 使用タイプ（例: APN1-Lambda-GB-Second-ARM -> Lambda-GB-Second-ARM）ごとに返す
+func lambdaSavingsPlanRates(searchResults []savingsplansTypes.SavingsPlanOfferingRate, durationSeconds int64) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, offering := range searchResults {
+		// 期間が一致するか確認
+		if offering.SavingsPlanOffering != nil && offering.SavingsPlanOffering.DurationSeconds != durationSeconds {
+			continue
+		}
+		if offering.UsageType == nil || offering.Rate == nil {
+			continue
+		}
+
+		usageType := *offering.UsageType
+		if i := strings.Index(usageType, "Lambda-"); i >= 0 {
+			usageType = usageType[i:]
+		}
+		if _, ok := rates[usageType]; ok {
+			continue
+		}
+
+		rate, err := strconv.ParseFloat(*offering.Rate, 64)
+		if err == nil {
+			rates[usageType] = rate
+		}
+	}
+	return rates
+}
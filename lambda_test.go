@@ -0,0 +1,43 @@
+package awsri
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	savingsplansTypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+)
+
+func TestLambdaSavingsPlanRates(t *testing.T) {
+	oneYear := int64(365 * 24 * 60 * 60)
+	threeYears := 3 * oneYear
+	results := []savingsplansTypes.SavingsPlanOfferingRate{
+		{SavingsPlanOffering: &savingsplansTypes.ParentSavingsPlanOffering{DurationSeconds: threeYears},
+			UsageType: aws.String("APN1-Lambda-GB-Second"), Rate: aws.String("0.0000100")},
+		{SavingsPlanOffering: &savingsplansTypes.ParentSavingsPlanOffering{DurationSeconds: oneYear},
+			UsageType: aws.String("APN1-Lambda-GB-Second"), Rate: aws.String("0.0000139")},
+		{SavingsPlanOffering: &savingsplansTypes.ParentSavingsPlanOffering{DurationSeconds: oneYear},
+			UsageType: aws.String("APN1-Lambda-GB-Second-ARM"), Rate: aws.String("0.0000111")},
+		{SavingsPlanOffering: &savingsplansTypes.ParentSavingsPlanOffering{DurationSeconds: oneYear},
+			UsageType: aws.String("Lambda-Provisioned-Concurrency"), Rate: aws.String("0.0000036")},
+	}
+
+	rates := lambdaSavingsPlanRates(results, oneYear)
+	expected := map[string]float64{
+		"Lambda-GB-Second":               0.0000139,
+		"Lambda-GB-Second-ARM":           0.0000111,
+		"Lambda-Provisioned-Concurrency": 0.0000036,
+	}
+	if len(rates) != len(expected) {
+		t.Fatalf("Expected %d rates, got %v", len(expected), rates)
+	}
+	for usageType, rate := range expected {
+		if rates[usageType] != rate {
+			t.Errorf("%s: expected %v, got %v", usageType, rate, rates[usageType])
+		}
+	}
+
+	cmd := NewLambdaCommand(LambdaOption{Architecture: "arm"})
+	if got := cmd.usageType(lambdaDurationUsage); got != "Lambda-GB-Second-ARM" {
+		t.Errorf("Expected ARM usage type, got %s", got)
+	}
+}
@@ -0,0 +1,124 @@
+package awsri
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ManifestInstance はマニフェストの1行を表す構造体
+type ManifestInstance struct {
+	ServiceType  string `json:"service_type"`
+	InstanceType string `json:"instance_type"`
+	Count        int    `json:"count"`
+	Description  string `json:"description"`
+	MultiAz      bool   `json:"multi_az,omitempty"`
+	Region       string `json:"region,omitempty"`
+	// Accounts はCountのアカウントごとの内訳（複数アカウントを探索した場合のみ）
+	Accounts map[string]int `json:"accounts,omitempty"`
+	// Tags は行をまとめたタグ（generate --group-by-tagの場合のみ）
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// Manifest は'awsri generate --output=json'が出力し、'awsri total --manifest'が読み込むJSONドキュメント
+// DurationとOfferingTypeはtotalで--durationと--offering-typeが指定されていない場合に使われる
+type Manifest struct {
+	Instances    []ManifestInstance `json:"instances"`
+	Duration     int                `json:"duration"`
+	OfferingType string             `json:"offering_type"`
+	Skipped      []SkippedResource  `json:"skipped,omitempty"`
+}
+
+// SkippedResource は需要から除外したリソースとその理由を表す構造体
+type SkippedResource struct {
+	ServiceType  string `json:"service_type"`
+	ResourceID   string `json:"resource_id"`
+	InstanceType string `json:"instance_type"`
+	Region       string `json:"region,omitempty"`
+	Account      string `json:"account,omitempty"`
+	Reason       string `json:"reason"`
+}
+
+// NewManifest は検出したインスタンスからマニフェストを作成する
+// totalコマンドの引数の形式に合わせるため、インスタンスタイプから"db."や"cache."などのプレフィックスを取り除く
+func NewManifest(instances []InstanceInfo, duration int, offeringType string) Manifest {
+	manifest := Manifest{
+		Duration:     duration,
+		OfferingType: offeringType,
+		Instances:    make([]ManifestInstance, 0, len(instances)),
+	}
+
+	for _, instance := range instances {
+		manifest.Instances = append(manifest.Instances, ManifestInstance{
+			ServiceType:  instance.ServiceType,
+			InstanceType: trimInstanceTypePrefix(instance.ServiceType, instance.InstanceType),
+			Count:        instance.Count,
+			Description:  instance.Description,
+			MultiAz:      instance.MultiAz,
+			Region:       instance.Region,
+			Accounts:     instance.Accounts,
+			Tags:         instance.Tags,
+		})
+	}
+
+	return manifest
+}
+
+// LoadManifest はマニフェストのJSONファイルを読み込む
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	return &manifest, nil
+}
+
+// InstanceInfos はマニフェストの行をInstanceInfoに変換する
+func (m *Manifest) InstanceInfos() []InstanceInfo {
+	instances := make([]InstanceInfo, 0, len(m.Instances))
+	for _, instance := range m.Instances {
+		instances = append(instances, InstanceInfo{
+			ServiceType:  instance.ServiceType,
+			InstanceType: addInstanceTypePrefix(instance.ServiceType, instance.InstanceType),
+			Count:        instance.Count,
+			Description:  instance.Description,
+			MultiAz:      instance.MultiAz,
+			Region:       instance.Region,
+			Accounts:     instance.Accounts,
+			Tags:         instance.Tags,
+		})
+	}
+	return instances
+}
+
+// instanceTypePrefixes はサービスタイプごとのインスタンスタイプのプレフィックス
+var instanceTypePrefixes = map[string]string{
+	"rds":         "db.",
+	"elasticache": "cache.",
+	"memorydb":    "db.",
+	"docdb":       "db.",
+	"neptune":     "db.",
+}
+
+// trimInstanceTypePrefix はインスタンスタイプからサービスのプレフィックスを取り除く（例: db.m5.large -> m5.large）
+func trimInstanceTypePrefix(serviceType, instanceType string) string {
+	if prefix, ok := instanceTypePrefixes[serviceType]; ok {
+		return strings.TrimPrefix(instanceType, prefix)
+	}
+	return instanceType
+}
+
+// addInstanceTypePrefix はインスタンスタイプにサービスのプレフィックスがなければ付ける（例: m5.large -> db.m5.large）
+func addInstanceTypePrefix(serviceType, instanceType string) string {
+	if prefix, ok := instanceTypePrefixes[serviceType]; ok && !strings.HasPrefix(instanceType, prefix) {
+		return prefix + instanceType
+	}
+	return instanceType
+}
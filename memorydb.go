@@ -0,0 +1,134 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
+	memorydbTypes "github.com/aws/aws-sdk-go-v2/service/memorydb/types"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+)
+
+type MemoryDBOption struct {
+	NodeType string `required:"" help:"Node type (e.g. db.r6g.large)"`
+	Engine   string `name:"engine" default:"redis" help:"Engine of the on-demand price (redis, valkey)"`
+	Region   string `default:"ap-northeast-1" help:"AWS region"`
+}
+
+type MemoryDBCommand struct {
+	opts MemoryDBOption
+}
+
+func NewMemoryDBCommand(opts MemoryDBOption) *MemoryDBCommand {
+	return &MemoryDBCommand{opts: opts}
+}
+
+func (c *MemoryDBCommand) Run(ctx context.Context) error {
+	if c.opts.Engine != "redis" && c.opts.Engine != "valkey" {
+		return fmt.Errorf("engine must be redis or valkey, got: %s", c.opts.Engine)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(c.opts.Region))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config, %v", err)
+	}
+
+	svc := memorydb.NewFromConfig(cfg)
+	nodeType := addInstanceTypePrefix("memorydb", c.opts.NodeType)
+
+	// オンデマンド料金をAPI経由で取得
+	pricingSvc, region := newPricingClient(cfg)
+	onDemandPrice, err := getMemoryDBOnDemandPrice(ctx, pricingSvc, region, nodeType, c.opts.Engine)
+	if err != nil {
+		return fmt.Errorf("failed to get on-demand price: %v", err)
+	}
+
+	return RenderReservedPriceTable(onDemandPrice, ReservedOfferingTypes(), func(duration int, offeringType string) (float64, float64, bool, error) {
+		offering, err := describeMemoryDBOffering(ctx, svc, nodeType, duration, offeringType)
+		if err != nil || offering == nil {
+			return 0, 0, false, err
+		}
+		fixedPrice, monthlyRecurring := memoryDBOfferingCharges(*offering)
+		return fixedPrice, monthlyRecurring, true, nil
+	})
+}
+
+// getMemoryDBOnDemandPrice はregionのノードタイプとエンジンの月額のオンデマンド料金を取得する
+// ValkeyとRedis OSSは同じノードタイプで料金が異なるため、エンジンの一致する製品を選ぶ
+func getMemoryDBOnDemandPrice(ctx context.Context, client pricing.GetProductsAPIClient, region string, nodeType string, engine string) (float64, error) {
+	filters := []types.Filter{
+		{
+			Field: aws.String("instanceType"),
+			Value: aws.String(nodeType),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+			Type:  types.FilterTypeTermMatch,
+		},
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonMemoryDB"),
+		Filters:     filters,
+	}
+
+	result, err := client.GetProducts(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, priceListEntry := range result.PriceList {
+		product, err := parsePriceListProduct(priceListEntry)
+		if err != nil {
+			return 0, err
+		}
+		if memoryDBProductEngine(product.Product.Attributes) == engine {
+			return extractPriceFromResult(&pricing.GetProductsOutput{PriceList: []string{priceListEntry}})
+		}
+	}
+	return 0, fmt.Errorf("no pricing information found for MemoryDB %s (%s) in region %s", nodeType, engine, region)
+}
+
+// memoryDBProductEngine はPricing APIの製品のエンジン（redis, valkey）を返す
+// エンジンの属性は "Redis OSS" や "Valkey" のため先頭の語を小文字にする。属性がない製品はRedis OSS
+func memoryDBProductEngine(attributes map[string]string) string {
+	for _, key := range []string{"engine", "cacheEngine"} {
+		if fields := strings.Fields(attributes[key]); len(fields) > 0 {
+			return strings.ToLower(fields[0])
+		}
+	}
+	return "redis"
+}
+
+// describeMemoryDBOffering はノードタイプ・期間（年）・オファリングタイプに一致するリザーブドノードのオファリングを取得する
+func describeMemoryDBOffering(ctx context.Context, client memorydb.DescribeReservedNodesOfferingsAPIClient, nodeType string, duration int, offeringType string) (*memorydbTypes.ReservedNodesOffering, error) {
+	params := &memorydb.DescribeReservedNodesOfferingsInput{
+		Duration:     aws.String(strconv.Itoa(duration)),
+		OfferingType: aws.String(offeringType),
+		NodeType:     aws.String(nodeType),
+	}
+	o, err := client.DescribeReservedNodesOfferings(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.ReservedNodesOfferings) == 0 {
+		return nil, nil
+	}
+	return &o.ReservedNodesOfferings[0], nil
+}
+
+// memoryDBOfferingCharges はオファリングの前払い料金と月額料金を返す
+func memoryDBOfferingCharges(offering memorydbTypes.ReservedNodesOffering) (float64, float64) {
+	hourly := 0.0
+	for _, charge := range offering.RecurringCharges {
+		hourly += charge.RecurringChargeAmount
+	}
+	return offering.FixedPrice, hourly * 24 * 30
+}
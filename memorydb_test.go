@@ -0,0 +1,126 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
+	memorydbTypes "github.com/aws/aws-sdk-go-v2/service/memorydb/types"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+)
+
+// fakeMemoryDBOfferings returns the offerings matching the requested node type, duration and offering type
+type fakeMemoryDBOfferings struct {
+	offerings []memorydbTypes.ReservedNodesOffering
+}
+
+func (f *fakeMemoryDBOfferings) DescribeReservedNodesOfferings(ctx context.Context, params *memorydb.DescribeReservedNodesOfferingsInput, optFns ...func(*memorydb.Options)) (*memorydb.DescribeReservedNodesOfferingsOutput, error) {
+	output := &memorydb.DescribeReservedNodesOfferingsOutput{}
+	for _, offering := range f.offerings {
+		duration := aws.ToString(params.Duration)
+		if aws.ToString(offering.NodeType) == aws.ToString(params.NodeType) &&
+			aws.ToString(offering.OfferingType) == aws.ToString(params.OfferingType) &&
+			(duration == "1" && offering.Duration == 365*24*60*60 || duration == "3" && offering.Duration == 3*365*24*60*60) {
+			output.ReservedNodesOfferings = append(output.ReservedNodesOfferings, offering)
+		}
+	}
+	return output, nil
+}
+
+func TestDescribeMemoryDBOffering(t *testing.T) {
+	oneYear := int32(365 * 24 * 60 * 60)
+	client := &fakeMemoryDBOfferings{offerings: []memorydbTypes.ReservedNodesOffering{
+		{NodeType: aws.String("db.r6g.large"), Duration: oneYear, OfferingType: aws.String("Partial Upfront"), FixedPrice: 800,
+			RecurringCharges: []memorydbTypes.RecurringCharge{{RecurringChargeAmount: 0.25, RecurringChargeFrequency: aws.String("Hourly")}}},
+		{NodeType: aws.String("db.r6g.xlarge"), Duration: oneYear, OfferingType: aws.String("Partial Upfront"), FixedPrice: 1600},
+		{NodeType: aws.String("db.r6g.large"), Duration: 3 * oneYear, OfferingType: aws.String("All Upfront"), FixedPrice: 4000},
+	}}
+	nodeType := addInstanceTypePrefix("memorydb", "r6g.large")
+
+	offering, err := describeMemoryDBOffering(context.Background(), client, nodeType, 1, "Partial Upfront")
+	if err != nil {
+		t.Fatalf("Failed to describe offering: %v", err)
+	}
+	if offering == nil {
+		t.Fatal("Expected a 1y Partial Upfront offering")
+	}
+	if fixedPrice, monthly := memoryDBOfferingCharges(*offering); fixedPrice != 800 || monthly != 180 {
+		t.Errorf("Unexpected charges: upfront=%v monthly=%v", fixedPrice, monthly)
+	}
+
+	offering, err = describeMemoryDBOffering(context.Background(), client, nodeType, 3, "All Upfront")
+	if err != nil {
+		t.Fatalf("Failed to describe offering: %v", err)
+	}
+	if offering == nil {
+		t.Fatal("Expected a 3y All Upfront offering")
+	}
+	if fixedPrice, monthly := memoryDBOfferingCharges(*offering); fixedPrice != 4000 || monthly != 0 {
+		t.Errorf("Unexpected charges: upfront=%v monthly=%v", fixedPrice, monthly)
+	}
+
+	offering, err = describeMemoryDBOffering(context.Background(), client, nodeType, 1, "No Upfront")
+	if err != nil {
+		t.Fatalf("Failed to describe offering: %v", err)
+	}
+	if offering != nil {
+		t.Errorf("Expected no 1y No Upfront offering, got %+v", offering)
+	}
+}
+
+// fakePriceList returns the same price list entries for any request
+type fakePriceList struct {
+	entries []string
+	params  *pricing.GetProductsInput
+}
+
+func (f *fakePriceList) GetProducts(ctx context.Context, params *pricing.GetProductsInput, optFns ...func(*pricing.Options)) (*pricing.GetProductsOutput, error) {
+	f.params = params
+	return &pricing.GetProductsOutput{PriceList: f.entries}, nil
+}
+
+// priceListEntry returns a price list entry with the product attributes and an hourly on-demand price
+func priceListEntry(attributes string, hourly string) string {
+	return fmt.Sprintf(`{
+		"product": {"attributes": %s},
+		"terms": {"OnDemand": {"SKU.1": {"priceDimensions": {"SKU.1.1": {"unit": "Hrs", "pricePerUnit": {"USD": "%s"}}}}}}
+	}`, attributes, hourly)
+}
+
+func TestGetMemoryDBOnDemandPrice(t *testing.T) {
+	client := &fakePriceList{entries: []string{
+		priceListEntry(`{"instanceType": "db.r6g.large", "engine": "Valkey"}`, "0.25"),
+		priceListEntry(`{"instanceType": "db.r6g.large", "engine": "Redis OSS"}`, "0.5"),
+	}}
+
+	tests := []struct {
+		engine   string
+		expected float64
+	}{
+		{"redis", 360},
+		{"valkey", 180},
+	}
+	for _, tt := range tests {
+		price, err := getMemoryDBOnDemandPrice(context.Background(), client, "ap-northeast-1", "db.r6g.large", tt.engine)
+		if err != nil {
+			t.Fatalf("%s: failed to get price: %v", tt.engine, err)
+		}
+		if price != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.engine, tt.expected, price)
+		}
+	}
+	if aws.ToString(client.params.ServiceCode) != "AmazonMemoryDB" {
+		t.Errorf("Unexpected service code: %s", aws.ToString(client.params.ServiceCode))
+	}
+
+	// エンジンの属性がない製品はRedis OSSとして扱う
+	client = &fakePriceList{entries: []string{priceListEntry(`{"instanceType": "db.r6g.large"}`, "0.5")}}
+	if price, err := getMemoryDBOnDemandPrice(context.Background(), client, "ap-northeast-1", "db.r6g.large", "redis"); err != nil || price != 360 {
+		t.Errorf("Expected 360 for a product without an engine, got %v (%v)", price, err)
+	}
+	if _, err := getMemoryDBOnDemandPrice(context.Background(), client, "ap-northeast-1", "db.r6g.large", "valkey"); err == nil {
+		t.Error("Expected an error without a Valkey product")
+	}
+}
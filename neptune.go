@@ -0,0 +1,31 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+type NeptuneOption struct {
+	InstanceClass string `required:"" help:"Instance class (e.g. db.r6g.large)"`
+	Region        string `default:"ap-northeast-1" help:"AWS region"`
+}
+
+type NeptuneCommand struct {
+	opts NeptuneOption
+}
+
+func NewNeptuneCommand(opts NeptuneOption) *NeptuneCommand {
+	return &NeptuneCommand{opts: opts}
+}
+
+func (c *NeptuneCommand) Run(ctx context.Context) error {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(c.opts.Region))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config, %v", err)
+	}
+
+	// NeptuneのリザーブドインスタンスはRDSのオファリングAPIで販売される
+	return renderRDSCompatibleTable(ctx, cfg, neptuneEngine, addInstanceTypePrefix("neptune", c.opts.InstanceClass))
+}
@@ -0,0 +1,227 @@
+package awsri
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// rdsSizeUnits はインスタンスサイズごとのRDSの正規化ユニット
+// "<N>xlarge"の形式のサイズはxlargeの値から計算する
+var rdsSizeUnits = map[string]float64{
+	"nano":   0.25,
+	"micro":  0.5,
+	"small":  1,
+	"medium": 2,
+	"large":  4,
+	"xlarge": 8,
+}
+
+// rdsSizeFlexibleEngines はRIがインスタンスファミリー内でサイズフレキシブルなRDSのエンジン
+// OracleはBring Your Own Licenseの場合のみサイズフレキシブル（isRDSSizeFlexibleを参照）
+var rdsSizeFlexibleEngines = map[string]bool{
+	"mysql":             true,
+	"mariadb":           true,
+	"postgresql":        true,
+	"aurora-mysql":      true,
+	"aurora-postgresql": true,
+}
+
+// splitInstanceClass はインスタンスクラスをファミリーとサイズに分ける（例: db.r6g.2xlarge -> r6g, 2xlarge）
+func splitInstanceClass(instanceClass string) (string, string, bool) {
+	for _, prefix := range instanceTypePrefixes {
+		instanceClass = strings.TrimPrefix(instanceClass, prefix)
+	}
+	i := strings.LastIndex(instanceClass, ".")
+	if i <= 0 || i == len(instanceClass)-1 {
+		return "", "", false
+	}
+	return instanceClass[:i], instanceClass[i+1:], true
+}
+
+// sizeUnits はインスタンスサイズの正規化ユニットを返す
+func sizeUnits(size string) (float64, bool) {
+	if units, ok := rdsSizeUnits[size]; ok {
+		return units, true
+	}
+	if multiplier, ok := strings.CutSuffix(size, "xlarge"); ok {
+		n, err := strconv.Atoi(multiplier)
+		if err == nil && n > 0 {
+			return float64(n) * rdsSizeUnits["xlarge"], true
+		}
+	}
+	return 0, false
+}
+
+// rdsNormalizationUnits はRDSのインスタンスクラスのファミリーと正規化ユニットを返す
+// マルチAZの場合は2倍にする
+func rdsNormalizationUnits(instanceClass string, multiAz bool) (string, float64, bool) {
+	family, size, ok := splitInstanceClass(instanceClass)
+	if !ok {
+		return "", 0, false
+	}
+	units, ok := sizeUnits(size)
+	if !ok {
+		return "", 0, false
+	}
+	if multiAz {
+		units *= 2
+	}
+	return family, units, true
+}
+
+// normalizeRDSEngine はRDSのエンジン名やRIのproductDescriptionを共通の形式に変換する
+// （例: postgres -> postgresql, oracle-ee(byol) -> oracle-ee）
+func normalizeRDSEngine(engine string) string {
+	engine = strings.ToLower(strings.TrimSpace(engine))
+	if i := strings.Index(engine, "("); i >= 0 {
+		engine = engine[:i]
+	}
+	switch engine {
+	case "postgres":
+		return "postgresql"
+	case "aurora":
+		return "aurora-mysql"
+	}
+	return engine
+}
+
+// isRDSSizeFlexible はエンジンまたはproductDescriptionのRDSのRIがサイズフレキシブルかどうかを返す
+// OracleはproductDescriptionがBring Your Own Licenseの場合（例: oracle-ee(byol)）のみサイズフレキシブル
+func isRDSSizeFlexible(engine string) bool {
+	normalized := normalizeRDSEngine(engine)
+	if strings.HasPrefix(normalized, "oracle") {
+		return strings.HasSuffix(strings.ToLower(strings.TrimSpace(engine)), "(byol)")
+	}
+	return rdsSizeFlexibleEngines[normalized]
+}
+
+// cacheSizeFlexibleEngines はリザーブドノードがノードファミリー内でサイズフレキシブルなElastiCacheのエンジン
+var cacheSizeFlexibleEngines = map[string]bool{
+	"redis": true,
+}
+
+// isCacheSizeFlexible はエンジンまたはproductDescriptionのElastiCacheのリザーブドノードがサイズフレキシブルかどうかを返す
+func isCacheSizeFlexible(engine string) bool {
+	return cacheSizeFlexibleEngines[normalizeCacheEngine(engine)]
+}
+
+// cacheNormalizationUnits はElastiCacheのノードタイプのファミリーと正規化ユニットを返す
+// ElastiCacheのサイズごとのユニットはRDSと同じ
+func cacheNormalizationUnits(nodeType string) (string, float64, bool) {
+	return rdsNormalizationUnits(nodeType, false)
+}
+
+// normalizationUnits はサイズフレキシブルな行のファミリー・正規化ユニット・正規化したエンジンを返す
+// RIがサイズフレキシブルでない行の場合はfalseを返す
+func normalizationUnits(serviceType, instanceType, description string, multiAz bool) (string, float64, string, bool) {
+	switch serviceType {
+	case "rds":
+		if !isRDSSizeFlexible(description) {
+			return "", 0, "", false
+		}
+		family, units, ok := rdsNormalizationUnits(instanceType, multiAz)
+		return family, units, normalizeRDSEngine(description), ok
+	case "elasticache":
+		if !isCacheSizeFlexible(description) {
+			return "", 0, "", false
+		}
+		family, units, ok := cacheNormalizationUnits(instanceType)
+		return family, units, normalizeCacheEngine(description), ok
+	default:
+		return "", 0, "", false
+	}
+}
+
+// normalizedPool はサービス・ファミリー・リージョン・エンジンごとのサイズフレキシブルな需要を正規化ユニットで表す構造体
+type normalizedPool struct {
+	serviceType  string // "rds", "elasticache"
+	region       string
+	engine       string // 正規化したエンジン（例: postgresql, redis）
+	description  string // オファリングの検索に使う最初の行のproductDescription
+	family       string // サービスのプレフィックスを除いたファミリー（例: r6g）
+	units        float64
+	smallestSize string // プールの行の最小のサイズ（例: large）
+	lines        []InstanceInfo
+}
+
+// normalizeFleet はサイズフレキシブルなRDSとElastiCacheの行をサービス・ファミリー・リージョン・エンジンごとの正規化ユニットに換算する
+// プールと、それ以外の行を元の順序で返す。リージョンのない行はdefaultRegionを使う
+func normalizeFleet(instances []InstanceInfo, defaultRegion string) ([]*normalizedPool, []InstanceInfo) {
+	var pools []*normalizedPool
+	index := make(map[string]*normalizedPool)
+	var rest []InstanceInfo
+	for _, instance := range instances {
+		family, units, engine, ok := normalizationUnits(instance.ServiceType, instance.InstanceType, instance.Description, instance.MultiAz)
+		if !ok {
+			rest = append(rest, instance)
+			continue
+		}
+		_, size, _ := splitInstanceClass(instance.InstanceType)
+
+		region := instance.Region
+		if region == "" {
+			region = defaultRegion
+		}
+		key := fmt.Sprintf("%s|%s|%s|%s", instance.ServiceType, region, engine, family)
+		pool, ok := index[key]
+		if !ok {
+			pool = &normalizedPool{serviceType: instance.ServiceType, region: region, engine: engine, description: instance.Description, family: family, smallestSize: size}
+			index[key] = pool
+			pools = append(pools, pool)
+		}
+		pool.units += units * float64(instance.Count)
+		current, _ := sizeUnits(pool.smallestSize)
+		if candidate, _ := sizeUnits(size); candidate < current {
+			pool.smallestSize = size
+		}
+		pool.lines = append(pool.lines, instance)
+	}
+	return pools, rest
+}
+
+// instanceType はプールのファミリーのサイズのインスタンスタイプを返す（例: db.r6g.large）
+func (p *normalizedPool) instanceType(size string) string {
+	return addInstanceTypePrefix(p.serviceType, p.family+"."+size)
+}
+
+// candidateSizes はプールを購入できるサイズを小さい順に返す
+// 購入をすべての行に割り当てられるように、プールの行の最小のサイズ以下に限る
+func (p *normalizedPool) candidateSizes() []string {
+	limit, _ := sizeUnits(p.smallestSize)
+	var sizes []string
+	for _, size := range []string{"nano", "micro", "small", "medium", "large", "xlarge"} {
+		if units := rdsSizeUnits[size]; units <= limit {
+			sizes = append(sizes, size)
+		}
+	}
+	if _, ok := rdsSizeUnits[p.smallestSize]; !ok {
+		sizes = append(sizes, p.smallestSize)
+	}
+	return sizes
+}
+
+// count はユニットを賄うサイズのインスタンス数を切り上げで返す
+func (p *normalizedPool) count(size string, units float64) int {
+	per, ok := sizeUnits(size)
+	if !ok {
+		return 0
+	}
+	return int(math.Ceil(units/per - 1e-9))
+}
+
+// smallestOfferingSize はオファリングがあるプールの最小の候補サイズを返す
+// どの候補にもオファリングがない場合はプールの行の最小のサイズを返す
+func smallestOfferingSize(p *normalizedPool, hasOffering func(instanceType string) (bool, error)) (string, error) {
+	for _, size := range p.candidateSizes() {
+		ok, err := hasOffering(p.instanceType(size))
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return size, nil
+		}
+	}
+	return p.smallestSize, nil
+}
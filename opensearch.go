@@ -0,0 +1,152 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	opensearchTypes "github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+)
+
+type OpenSearchOption struct {
+	InstanceType string `required:"" help:"Instance type (e.g. r6g.large.search)"`
+	Region       string `default:"ap-northeast-1" help:"AWS region"`
+}
+
+type OpenSearchCommand struct {
+	opts OpenSearchOption
+}
+
+func NewOpenSearchCommand(opts OpenSearchOption) *OpenSearchCommand {
+	return &OpenSearchCommand{opts: opts}
+}
+
+// openSearchPaymentOptions はオファリングタイプとOpenSearchの支払いオプションの対応
+var openSearchPaymentOptions = map[string]opensearchTypes.ReservedInstancePaymentOption{
+	"No Upfront":      opensearchTypes.ReservedInstancePaymentOptionNoUpfront,
+	"Partial Upfront": opensearchTypes.ReservedInstancePaymentOptionPartialUpfront,
+	"All Upfront":     opensearchTypes.ReservedInstancePaymentOptionAllUpfront,
+}
+
+func (c *OpenSearchCommand) Run(ctx context.Context) error {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(c.opts.Region))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config, %v", err)
+	}
+
+	instanceType := openSearchInstanceType(c.opts.InstanceType)
+
+	// オンデマンド料金をAPI経由で取得
+	onDemandPrice, err := c.getOpenSearchOnDemandPrice(cfg, instanceType)
+	if err != nil {
+		return fmt.Errorf("failed to get on-demand price: %v", err)
+	}
+
+	// オファリングはインスタンスタイプで絞り込めないため、まとめて取得してから絞り込む
+	offerings, err := describeOpenSearchOfferings(ctx, opensearch.NewFromConfig(cfg), instanceType)
+	if err != nil {
+		return err
+	}
+
+	return RenderReservedPriceTable(onDemandPrice, ReservedOfferingTypes(), func(duration int, offeringType string) (float64, float64, bool, error) {
+		offering := findOpenSearchOffering(offerings, duration, offeringType)
+		if offering == nil {
+			return 0, 0, false, nil
+		}
+		fixedPrice, monthlyRecurring := openSearchOfferingCharges(*offering)
+		return fixedPrice, monthlyRecurring, true, nil
+	})
+}
+
+func (c *OpenSearchCommand) getOpenSearchOnDemandPrice(cfg aws.Config, instanceType string) (float64, error) {
+	svc, region := newPricingClient(cfg)
+
+	// OpenSearchのオンデマンド料金を取得
+	filters := []types.Filter{
+		{
+			Field: aws.String("instanceType"),
+			Value: aws.String(instanceType),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+			Type:  types.FilterTypeTermMatch,
+		},
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonES"),
+		Filters:     filters,
+	}
+
+	result, err := svc.GetProducts(context.TODO(), input)
+	if err != nil {
+		return 0, err
+	}
+
+	return extractPriceFromResult(result)
+}
+
+// openSearchInstanceType はインスタンスタイプに ".search" サフィックスを付与する
+func openSearchInstanceType(instanceType string) string {
+	if strings.HasSuffix(instanceType, ".search") {
+		return instanceType
+	}
+	return instanceType + ".search"
+}
+
+// describeOpenSearchOfferings は指定したインスタンスタイプのリザーブドインスタンスのオファリングを取得する
+func describeOpenSearchOfferings(ctx context.Context, client opensearch.DescribeReservedInstanceOfferingsAPIClient, instanceType string) ([]opensearchTypes.ReservedInstanceOffering, error) {
+	var offerings []opensearchTypes.ReservedInstanceOffering
+	paginator := opensearch.NewDescribeReservedInstanceOfferingsPaginator(client, &opensearch.DescribeReservedInstanceOfferingsInput{
+		MaxResults: 100,
+	})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, offering := range result.ReservedInstanceOfferings {
+			if string(offering.InstanceType) == instanceType {
+				offerings = append(offerings, offering)
+			}
+		}
+	}
+	return offerings, nil
+}
+
+// findOpenSearchOffering は期間（年）とオファリングタイプに一致するオファリングを返す
+func findOpenSearchOffering(offerings []opensearchTypes.ReservedInstanceOffering, duration int, offeringType string) *opensearchTypes.ReservedInstanceOffering {
+	paymentOption, ok := openSearchPaymentOptions[offeringType]
+	if !ok {
+		return nil
+	}
+
+	// OpenSearchのオファリングの期間は秒単位
+	durationSeconds := int32(duration * 365 * 24 * 60 * 60)
+	for i, offering := range offerings {
+		if offering.Duration == durationSeconds && offering.PaymentOption == paymentOption {
+			return &offerings[i]
+		}
+	}
+	return nil
+}
+
+// openSearchOfferingCharges はオファリングの前払い料金と月額料金を返す
+func openSearchOfferingCharges(offering opensearchTypes.ReservedInstanceOffering) (float64, float64) {
+	// 時間単位の料金はRecurringChargesに含まれる（含まれない場合はUsagePriceを使用）
+	hourly := 0.0
+	for _, charge := range offering.RecurringCharges {
+		hourly += aws.ToFloat64(charge.RecurringChargeAmount)
+	}
+	if len(offering.RecurringCharges) == 0 {
+		hourly = aws.ToFloat64(offering.UsagePrice)
+	}
+	return aws.ToFloat64(offering.FixedPrice), hourly * 24 * 30
+}
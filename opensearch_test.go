@@ -0,0 +1,67 @@
+package awsri
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	opensearchTypes "github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+)
+
+// fakeOpenSearchOfferings returns the offerings in two pages
+type fakeOpenSearchOfferings struct {
+	pages [][]opensearchTypes.ReservedInstanceOffering
+}
+
+func (f *fakeOpenSearchOfferings) DescribeReservedInstanceOfferings(ctx context.Context, params *opensearch.DescribeReservedInstanceOfferingsInput, optFns ...func(*opensearch.Options)) (*opensearch.DescribeReservedInstanceOfferingsOutput, error) {
+	page := 0
+	if params.NextToken != nil {
+		page = 1
+	}
+	output := &opensearch.DescribeReservedInstanceOfferingsOutput{ReservedInstanceOfferings: f.pages[page]}
+	if page == 0 {
+		output.NextToken = aws.String("next")
+	}
+	return output, nil
+}
+
+func TestFindOpenSearchOffering(t *testing.T) {
+	oneYear := int32(365 * 24 * 60 * 60)
+	client := &fakeOpenSearchOfferings{pages: [][]opensearchTypes.ReservedInstanceOffering{
+		{
+			{InstanceType: "r6g.large.search", Duration: oneYear, PaymentOption: opensearchTypes.ReservedInstancePaymentOptionNoUpfront, FixedPrice: aws.Float64(0),
+				RecurringCharges: []opensearchTypes.RecurringCharge{{RecurringChargeAmount: aws.Float64(0.1), RecurringChargeFrequency: aws.String("Hourly")}}},
+			{InstanceType: "m6g.large.search", Duration: oneYear, PaymentOption: opensearchTypes.ReservedInstancePaymentOptionPartialUpfront, FixedPrice: aws.Float64(300)},
+		},
+		{
+			{InstanceType: "r6g.large.search", Duration: oneYear, PaymentOption: opensearchTypes.ReservedInstancePaymentOptionPartialUpfront, FixedPrice: aws.Float64(400),
+				RecurringCharges: []opensearchTypes.RecurringCharge{{RecurringChargeAmount: aws.Float64(0.25), RecurringChargeFrequency: aws.String("Hourly")}}},
+			{InstanceType: "r6g.large.search", Duration: 3 * oneYear, PaymentOption: opensearchTypes.ReservedInstancePaymentOptionAllUpfront, FixedPrice: aws.Float64(2000)},
+		},
+	}}
+
+	offerings, err := describeOpenSearchOfferings(context.Background(), client, openSearchInstanceType("r6g.large"))
+	if err != nil {
+		t.Fatalf("Failed to describe offerings: %v", err)
+	}
+	if len(offerings) != 3 {
+		t.Fatalf("Expected 3 offerings for r6g.large.search, got %d", len(offerings))
+	}
+
+	offering := findOpenSearchOffering(offerings, 1, "Partial Upfront")
+	if offering == nil {
+		t.Fatal("Expected a 1y Partial Upfront offering")
+	}
+	fixedPrice, monthly := openSearchOfferingCharges(*offering)
+	if fixedPrice != 400 || monthly != 180 {
+		t.Errorf("Unexpected charges: upfront=%v monthly=%v", fixedPrice, monthly)
+	}
+
+	if offering := findOpenSearchOffering(offerings, 3, "All Upfront"); offering == nil || aws.ToFloat64(offering.FixedPrice) != 2000 {
+		t.Errorf("Expected the 3y All Upfront offering, got %+v", offering)
+	}
+	if offering := findOpenSearchOffering(offerings, 3, "No Upfront"); offering != nil {
+		t.Errorf("Expected no 3y No Upfront offering, got %+v", offering)
+	}
+}
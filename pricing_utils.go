@@ -3,12 +3,23 @@ package awsri
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
 	savingsplansTypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
 )
 
+// newPricingClient returns a Pricing API client and the region of cfg whose prices are looked up
+// The Pricing API is only available in us-east-1
+func newPricingClient(cfg aws.Config) (*pricing.Client, string) {
+	pricingCfg := cfg.Copy()
+	pricingCfg.Region = "us-east-1"
+	return pricing.NewFromConfig(pricingCfg), cfg.Region
+}
+
 // mapLocationToRegion maps location name to region code
 func mapLocationToRegion(location string) string {
 	locationMap := map[string]string{
@@ -48,46 +59,62 @@ func mapLocationToRegion(location string) string {
 	return ""
 }
 
+// regionLocations maps region code to location name for Pricing API
+var regionLocations = map[string]string{
+	"ap-northeast-1": "Asia Pacific (Tokyo)",
+	"ap-northeast-2": "Asia Pacific (Seoul)",
+	"ap-northeast-3": "Asia Pacific (Osaka)",
+	"ap-south-1":     "Asia Pacific (Mumbai)",
+	"ap-southeast-1": "Asia Pacific (Singapore)",
+	"ap-southeast-2": "Asia Pacific (Sydney)",
+	"ap-southeast-3": "Asia Pacific (Jakarta)",
+	"ap-southeast-4": "Asia Pacific (Melbourne)",
+	"ca-central-1":   "Canada (Central)",
+	"eu-central-1":   "EU (Frankfurt)",
+	"eu-west-1":      "EU (Ireland)",
+	"eu-west-2":      "EU (London)",
+	"eu-west-3":      "EU (Paris)",
+	"eu-south-1":     "EU (Milan)",
+	"eu-north-1":     "EU (Stockholm)",
+	"eu-south-2":     "EU (Spain)",
+	"eu-central-2":   "EU (Zurich)",
+	"me-south-1":     "Middle East (Bahrain)",
+	"me-central-1":   "Middle East (UAE)",
+	"sa-east-1":      "South America (São Paulo)",
+	"us-east-1":      "US East (N. Virginia)",
+	"us-east-2":      "US East (Ohio)",
+	"us-west-1":      "US West (N. California)",
+	"us-west-2":      "US West (Oregon)",
+	"af-south-1":     "Africa (Cape Town)",
+	"ap-east-1":      "Asia Pacific (Hong Kong)",
+	"cn-north-1":     "China (Beijing)",
+	"cn-northwest-1": "China (Ningxia)",
+	"il-central-1":   "Israel (Tel Aviv)",
+}
+
 // mapRegionToLocation maps region code to location name for Pricing API
 func mapRegionToLocation(region string) string {
-	regionMap := map[string]string{
-		"ap-northeast-1": "Asia Pacific (Tokyo)",
-		"ap-northeast-2": "Asia Pacific (Seoul)",
-		"ap-northeast-3": "Asia Pacific (Osaka)",
-		"ap-south-1":     "Asia Pacific (Mumbai)",
-		"ap-southeast-1": "Asia Pacific (Singapore)",
-		"ap-southeast-2": "Asia Pacific (Sydney)",
-		"ap-southeast-3": "Asia Pacific (Jakarta)",
-		"ap-southeast-4": "Asia Pacific (Melbourne)",
-		"ca-central-1":   "Canada (Central)",
-		"eu-central-1":   "EU (Frankfurt)",
-		"eu-west-1":      "EU (Ireland)",
-		"eu-west-2":      "EU (London)",
-		"eu-west-3":      "EU (Paris)",
-		"eu-south-1":     "EU (Milan)",
-		"eu-north-1":     "EU (Stockholm)",
-		"eu-south-2":     "EU (Spain)",
-		"eu-central-2":   "EU (Zurich)",
-		"me-south-1":     "Middle East (Bahrain)",
-		"me-central-1":   "Middle East (UAE)",
-		"sa-east-1":      "South America (São Paulo)",
-		"us-east-1":      "US East (N. Virginia)",
-		"us-east-2":      "US East (Ohio)",
-		"us-west-1":      "US West (N. California)",
-		"us-west-2":      "US West (Oregon)",
-		"af-south-1":     "Africa (Cape Town)",
-		"ap-east-1":      "Asia Pacific (Hong Kong)",
-		"cn-north-1":     "China (Beijing)",
-		"cn-northwest-1": "China (Ningxia)",
-		"il-central-1":   "Israel (Tel Aviv)",
-	}
-	if location, ok := regionMap[region]; ok {
+	if location, ok := regionLocations[region]; ok {
 		return location
 	}
 	// Default: use region name as is
 	return region
 }
 
+// allRegions returns the region codes known to awsri in sorted order.
+// China regions are excluded because they require a separate partition and credentials.
+func allRegions() []string {
+	var regions []string
+	for region := range regionLocations {
+		if strings.HasPrefix(region, "cn-") {
+			continue
+		}
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
 // getRegionCodeFromLocation retrieves region code from Properties
 func getRegionCodeFromLocation(properties []savingsplansTypes.SavingsPlanOfferingRateProperty) string {
 	for _, prop := range properties {
@@ -183,3 +210,41 @@ func renderCSV(hourlyCommitment, spPurchaseAmount, currentCost, spCost, savingsA
 		savingsRate,
 	)
 }
+
+// priceListProduct is the part of a Pricing API price list entry holding the attributes and terms
+type priceListProduct struct {
+	Product struct {
+		Attributes map[string]string `json:"attributes"`
+	} `json:"product"`
+	Terms struct {
+		OnDemand map[string]priceListTerm `json:"OnDemand"`
+		Reserved map[string]priceListTerm `json:"Reserved"`
+	} `json:"terms"`
+}
+
+// priceListTerm is an on-demand or reserved term of a price list entry
+type priceListTerm struct {
+	PriceDimensions map[string]priceListDimension `json:"priceDimensions"`
+	TermAttributes  map[string]string             `json:"termAttributes"`
+}
+
+// priceListDimension is a price dimension of a term (e.g. the hourly rate or the upfront fee)
+type priceListDimension struct {
+	Unit         string            `json:"unit"`
+	PricePerUnit map[string]string `json:"pricePerUnit"`
+}
+
+// usd returns the price of the dimension in USD
+func (d priceListDimension) usd() float64 {
+	price, _ := strconv.ParseFloat(d.PricePerUnit["USD"], 64)
+	return price
+}
+
+// parsePriceListProduct parses a price list entry returned by the Pricing API
+func parsePriceListProduct(priceListEntry string) (priceListProduct, error) {
+	var product priceListProduct
+	if err := json.Unmarshal([]byte(priceListEntry), &product); err != nil {
+		return product, fmt.Errorf("failed to unmarshal price data: %v", err)
+	}
+	return product, nil
+}
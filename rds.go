@@ -18,6 +18,11 @@ type RDSOption struct {
 	DbInstanceClass    string `required:"" help:"Instance class"`
 	ProductDescription string `required:"" help:"Product description"`
 	MultiAz            bool   `default:"false" help:"Multi-AZ"`
+	Region             string `default:"ap-northeast-1" help:"AWS region"`
+	LicenseModel       string `name:"license-model" help:"License model for Oracle and SQL Server (license-included, byol)"`
+	Edition            string `name:"edition" help:"Edition for Oracle (se2, ee) and SQL Server (ee, se, web, ex)"`
+
+	ScheduleOption `embed:""`
 }
 
 type RDSCommand struct {
@@ -29,7 +34,13 @@ func NewRDSCommand(opts RDSOption) *RDSCommand {
 }
 
 func (c *RDSCommand) Run(ctx context.Context) error {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion("ap-northeast-1"))
+	// 稼働スケジュールを検証（指定がなければ常時稼働）
+	hours, err := c.opts.scheduledHours()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(c.opts.Region))
 	if err != nil {
 		return fmt.Errorf("unable to load SDK config, %v", err)
 	}
@@ -37,6 +48,12 @@ func (c *RDSCommand) Run(ctx context.Context) error {
 	tableRenderer := NewTableRenderer()
 	svc := rds.NewFromConfig(cfg)
 
+	// OracleとSQL ServerはエディションとライセンスモデルごとのproductDescriptionでオファリングを取得する
+	productDescription, err := c.getProductDescription()
+	if err != nil {
+		return err
+	}
+
 	// オンデマンド料金をAPI経由で取得
 	databaseEngine, err := c.getDatabaseEngine(c.opts.ProductDescription)
 	if err != nil {
@@ -46,6 +63,11 @@ func (c *RDSCommand) Run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to get on-demand price: %v", err)
 	}
+	// オンデマンド料金は稼働時間分のみ、リザーブドは全時間分を支払う
+	onDemandPrice = scheduledOnDemand(onDemandPrice, hours)
+	if note := c.opts.scheduleNote(hours); note != "" {
+		fmt.Println(note)
+	}
 
 	for _, duration := range Durations {
 		durationMonths := DurationToMonths(duration)
@@ -60,7 +82,7 @@ func (c *RDSCommand) Run(ctx context.Context) error {
 				Duration:           aws.String(strconv.Itoa(duration)),
 				OfferingType:       aws.String(offeringType),
 				DBInstanceClass:    aws.String(c.opts.DbInstanceClass),
-				ProductDescription: aws.String(c.opts.ProductDescription),
+				ProductDescription: aws.String(productDescription),
 				MultiAZ:            aws.Bool(c.opts.MultiAz),
 			}
 			o, err := svc.DescribeReservedDBInstancesOfferings(context.TODO(), params)
@@ -70,7 +92,7 @@ func (c *RDSCommand) Run(ctx context.Context) error {
 			}
 
 			if len(o.ReservedDBInstancesOfferings) > 0 {
-				offering := c.getOffering(o.ReservedDBInstancesOfferings, c.opts.ProductDescription, c.opts.MultiAz)
+				offering := c.getOffering(o.ReservedDBInstancesOfferings, productDescription, c.opts.MultiAz)
 				if offering == nil {
 					tableRenderer.AppendNotAvailableRow(duration, offeringType)
 					continue
@@ -110,10 +132,16 @@ func (c *RDSCommand) Run(ctx context.Context) error {
 }
 
 func (c *RDSCommand) getRdsOnDemandPrice(cfg aws.Config, dbInstanceClass string, productDescription string, multiAz bool) (float64, error) {
-	// Pricing APIはus-east-1でのみ利用可能
-	pricingCfg := cfg.Copy()
-	pricingCfg.Region = "us-east-1"
-	svc := pricing.NewFromConfig(pricingCfg)
+	svc, region := newPricingClient(cfg)
+
+	// OracleとSQL Serverはエディションとライセンスモデルでも絞り込む
+	license, licensed, err := resolveRDSLicense(c.opts.ProductDescription, c.opts.Edition, c.opts.LicenseModel)
+	if err != nil {
+		return 0, err
+	}
+	if licensed {
+		productDescription = license.databaseEngine
+	}
 
 	// RDSのオンデマンド料金を取得
 	filters := []types.Filter{
@@ -134,10 +162,24 @@ func (c *RDSCommand) getRdsOnDemandPrice(cfg aws.Config, dbInstanceClass string,
 		},
 		{
 			Field: aws.String("regionCode"),
-			Value: aws.String("ap-northeast-1"),
+			Value: aws.String(region),
 			Type:  types.FilterTypeTermMatch,
 		},
 	}
+	if licensed {
+		filters = append(filters,
+			types.Filter{
+				Field: aws.String("databaseEdition"),
+				Value: aws.String(license.databaseEdition),
+				Type:  types.FilterTypeTermMatch,
+			},
+			types.Filter{
+				Field: aws.String("licenseModel"),
+				Value: aws.String(license.licenseModel),
+				Type:  types.FilterTypeTermMatch,
+			},
+		)
+	}
 
 	input := &pricing.GetProductsInput{
 		ServiceCode: aws.String("AmazonRDS"),
@@ -152,6 +194,19 @@ func (c *RDSCommand) getRdsOnDemandPrice(cfg aws.Config, dbInstanceClass string,
 	return extractPriceFromResult(result)
 }
 
+// getProductDescription はオファリングの検索に使うproductDescriptionを返す
+// OracleとSQL Serverはエディションとライセンスモデルを含む形式（例: oracle-se2(li)）にする
+func (c *RDSCommand) getProductDescription() (string, error) {
+	license, licensed, err := resolveRDSLicense(c.opts.ProductDescription, c.opts.Edition, c.opts.LicenseModel)
+	if err != nil {
+		return "", err
+	}
+	if licensed {
+		return license.productDescription, nil
+	}
+	return c.opts.ProductDescription, nil
+}
+
 func (c *RDSCommand) getDeploymentOption(multiAz bool) string {
 	if multiAz {
 		return "Multi-AZ"
@@ -209,7 +264,7 @@ func (c *RDSCommand) getDatabaseEngine(productDescription string) (string, error
 		return "Oracle", nil
 	}
 	if strings.Contains(productDescriptionLower, "sqlserver") || strings.Contains(productDescriptionLower, "sql server") {
-		return "SQLServer", nil
+		return "SQL Server", nil
 	}
 	if strings.Contains(productDescriptionLower, "aurora") {
 		if strings.Contains(productDescriptionLower, "mysql") {
@@ -228,3 +283,24 @@ func (c *RDSCommand) getDatabaseEngine(productDescription string) (string, error
 	
 	return "", fmt.Errorf("unsupported database engine: %s", productDescription)
 }
+
+// rdsOfferingExists はインスタンスクラス・productDescription・期間・オファリングタイプのSingle-AZのオファリングがあるかを返す
+func rdsOfferingExists(ctx context.Context, svc *rds.Client, instanceClass string, productDescription string, duration int, offeringType string) (bool, error) {
+	rdsCmd := NewRDSCommand(RDSOption{ProductDescription: productDescription})
+	productDescription, err := rdsCmd.getProductDescription()
+	if err != nil {
+		return false, err
+	}
+
+	o, err := svc.DescribeReservedDBInstancesOfferings(ctx, &rds.DescribeReservedDBInstancesOfferingsInput{
+		Duration:           aws.String(strconv.Itoa(duration)),
+		OfferingType:       aws.String(offeringType),
+		DBInstanceClass:    aws.String(instanceClass),
+		ProductDescription: aws.String(productDescription),
+		MultiAZ:            aws.Bool(false),
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(o.ReservedDBInstancesOfferings) > 0, nil
+}
@@ -0,0 +1,132 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// rdsCompatibleEngine はRDSのオファリングAPIでリザーブドインスタンスが販売されるRDS以外のデータベースサービス
+type rdsCompatibleEngine struct {
+	serviceType        string // "docdb", "neptune"
+	productDescription string // RDSのオファリングAPIのproductDescription
+	serviceCode        string // Pricing APIのサービスコード
+}
+
+var (
+	docDBEngine   = rdsCompatibleEngine{serviceType: "docdb", productDescription: "docdb", serviceCode: "AmazonDocDB"}
+	neptuneEngine = rdsCompatibleEngine{serviceType: "neptune", productDescription: "neptune", serviceCode: "AmazonNeptune"}
+)
+
+// rdsCompatibleEngines はサービスタイプ（RDSのエンジン名と同じ）ごとのデータベースサービス
+var rdsCompatibleEngines = map[string]rdsCompatibleEngine{
+	docDBEngine.serviceType:   docDBEngine,
+	neptuneEngine.serviceType: neptuneEngine,
+}
+
+// renderRDSCompatibleTable はインスタンスクラスのオンデマンドとリザーブドインスタンスの料金表を表示する
+func renderRDSCompatibleTable(ctx context.Context, cfg aws.Config, engine rdsCompatibleEngine, instanceClass string) error {
+	svc := rds.NewFromConfig(cfg)
+
+	// オンデマンド料金をAPI経由で取得
+	pricingSvc, region := newPricingClient(cfg)
+	onDemandPrice, err := getRDSCompatibleOnDemandPrice(ctx, pricingSvc, region, engine, instanceClass)
+	if err != nil {
+		return fmt.Errorf("failed to get on-demand price: %v", err)
+	}
+
+	return RenderReservedPriceTable(onDemandPrice, ReservedOfferingTypes(), func(duration int, offeringType string) (float64, float64, bool, error) {
+		offering, err := describeRDSCompatibleOffering(ctx, svc, engine, instanceClass, duration, offeringType)
+		if err != nil || offering == nil {
+			return 0, 0, false, err
+		}
+		fixedPrice, monthlyRecurring := rdsOfferingCharges(*offering)
+		return fixedPrice, monthlyRecurring, true, nil
+	})
+}
+
+// getRDSCompatibleOnDemandPrice はregionのインスタンスクラスの月額のオンデマンド料金を取得する
+func getRDSCompatibleOnDemandPrice(ctx context.Context, client pricing.GetProductsAPIClient, region string, engine rdsCompatibleEngine, instanceClass string) (float64, error) {
+	filters := []types.Filter{
+		{
+			Field: aws.String("instanceType"),
+			Value: aws.String(instanceClass),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			Field: aws.String("productFamily"),
+			Value: aws.String("Database Instance"),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+			Type:  types.FilterTypeTermMatch,
+		},
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String(engine.serviceCode),
+		Filters:     filters,
+	}
+
+	result, err := client.GetProducts(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+
+	// I/O-Optimizedのクラスタ構成も同じインスタンスクラスで返るため、Standardの製品を選ぶ
+	for _, priceListEntry := range result.PriceList {
+		product, err := parsePriceListProduct(priceListEntry)
+		if err != nil {
+			return 0, err
+		}
+		if rdsCompatibleStandardUsageType(product.Product.Attributes["usagetype"]) {
+			return extractPriceFromResult(&pricing.GetProductsOutput{PriceList: []string{priceListEntry}})
+		}
+	}
+
+	return 0, fmt.Errorf("no pricing information found for %s %s in region %s", engine.serviceType, instanceClass, region)
+}
+
+// rdsCompatibleStandardUsageType はusagetype（例: APN1-InstanceUsage:db.r6g.large）がStandardのインスタンス使用量かを返す
+func rdsCompatibleStandardUsageType(usageType string) bool {
+	name, _, _ := strings.Cut(usageType, ":")
+	return name == "InstanceUsage" || strings.HasSuffix(name, "-InstanceUsage")
+}
+
+// describeRDSCompatibleOffering はインスタンスクラス・期間（年）・オファリングタイプに一致するオファリングを取得する
+func describeRDSCompatibleOffering(ctx context.Context, client rds.DescribeReservedDBInstancesOfferingsAPIClient, engine rdsCompatibleEngine, instanceClass string, duration int, offeringType string) (*rdsTypes.ReservedDBInstancesOffering, error) {
+	params := &rds.DescribeReservedDBInstancesOfferingsInput{
+		Duration:           aws.String(strconv.Itoa(duration)),
+		OfferingType:       aws.String(offeringType),
+		DBInstanceClass:    aws.String(instanceClass),
+		ProductDescription: aws.String(engine.productDescription),
+	}
+	o, err := client.DescribeReservedDBInstancesOfferings(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	for i, offering := range o.ReservedDBInstancesOfferings {
+		if strings.EqualFold(aws.ToString(offering.ProductDescription), engine.productDescription) {
+			return &o.ReservedDBInstancesOfferings[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// rdsOfferingCharges はオファリングの前払い料金と月額料金を返す
+func rdsOfferingCharges(offering rdsTypes.ReservedDBInstancesOffering) (float64, float64) {
+	hourly := 0.0
+	for _, charge := range offering.RecurringCharges {
+		hourly += aws.ToFloat64(charge.RecurringChargeAmount)
+	}
+	return aws.ToFloat64(offering.FixedPrice), hourly * 24 * 30
+}
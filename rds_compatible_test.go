@@ -0,0 +1,136 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsTypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// fakeRDSOfferings returns the offerings of the requested instance class regardless of the product description
+type fakeRDSOfferings struct {
+	offerings []rdsTypes.ReservedDBInstancesOffering
+	params    []*rds.DescribeReservedDBInstancesOfferingsInput
+}
+
+func (f *fakeRDSOfferings) DescribeReservedDBInstancesOfferings(ctx context.Context, params *rds.DescribeReservedDBInstancesOfferingsInput, optFns ...func(*rds.Options)) (*rds.DescribeReservedDBInstancesOfferingsOutput, error) {
+	f.params = append(f.params, params)
+	output := &rds.DescribeReservedDBInstancesOfferingsOutput{}
+	for _, offering := range f.offerings {
+		if aws.ToString(offering.DBInstanceClass) == aws.ToString(params.DBInstanceClass) {
+			output.ReservedDBInstancesOfferings = append(output.ReservedDBInstancesOfferings, offering)
+		}
+	}
+	return output, nil
+}
+
+// fakeRDSCompatiblePricing returns an hourly on-demand price per service code,
+// preceded by a more expensive I/O-Optimized product of the same instance class
+type fakeRDSCompatiblePricing struct {
+	hourly map[string]string
+	params []*pricing.GetProductsInput
+}
+
+func (f *fakeRDSCompatiblePricing) GetProducts(ctx context.Context, params *pricing.GetProductsInput, optFns ...func(*pricing.Options)) (*pricing.GetProductsOutput, error) {
+	f.params = append(f.params, params)
+	price, ok := f.hourly[aws.ToString(params.ServiceCode)]
+	if !ok {
+		return &pricing.GetProductsOutput{}, nil
+	}
+	entry := func(usageType string, price string) string {
+		return fmt.Sprintf(`{
+		"product": {"productFamily": "Database Instance", "attributes": {"usagetype": "APN1-%s:db.r6g.large"}},
+		"terms": {"OnDemand": {"SKU.1": {"priceDimensions": {"SKU.1.1": {"unit": "Hrs", "pricePerUnit": {"USD": "%s"}}}}}}
+	}`, usageType, price)
+	}
+	return &pricing.GetProductsOutput{PriceList: []string{
+		entry("InstanceUsageIOOptimized", "9.99"),
+		entry("InstanceUsage", price),
+	}}, nil
+}
+
+func TestDescribeRDSCompatibleOffering(t *testing.T) {
+	client := &fakeRDSOfferings{offerings: []rdsTypes.ReservedDBInstancesOffering{
+		{DBInstanceClass: aws.String("db.r6g.large"), ProductDescription: aws.String("mysql"), FixedPrice: aws.Float64(100)},
+		{DBInstanceClass: aws.String("db.r6g.large"), ProductDescription: aws.String("DocDB"), FixedPrice: aws.Float64(600),
+			RecurringCharges: []rdsTypes.RecurringCharge{{RecurringChargeAmount: aws.Float64(0.25), RecurringChargeFrequency: aws.String("Hourly")}}},
+		{DBInstanceClass: aws.String("db.r6g.large"), ProductDescription: aws.String("neptune"), FixedPrice: aws.Float64(700)},
+	}}
+
+	tests := []struct {
+		engine          rdsCompatibleEngine
+		expectedUpfront float64
+		expectedMonthly float64
+	}{
+		// productDescriptionは大文字小文字を区別せずに一致させる
+		{docDBEngine, 600, 180},
+		{neptuneEngine, 700, 0},
+	}
+	for _, tt := range tests {
+		offering, err := describeRDSCompatibleOffering(context.Background(), client, tt.engine, "db.r6g.large", 1, "Partial Upfront")
+		if err != nil {
+			t.Fatalf("%s: failed to describe offering: %v", tt.engine.serviceType, err)
+		}
+		if offering == nil {
+			t.Fatalf("%s: expected an offering", tt.engine.serviceType)
+		}
+		if fixedPrice, monthly := rdsOfferingCharges(*offering); fixedPrice != tt.expectedUpfront || monthly != tt.expectedMonthly {
+			t.Errorf("%s: expected upfront=%v monthly=%v, got upfront=%v monthly=%v",
+				tt.engine.serviceType, tt.expectedUpfront, tt.expectedMonthly, fixedPrice, monthly)
+		}
+	}
+
+	params := client.params[0]
+	if aws.ToString(params.ProductDescription) != "docdb" || aws.ToString(params.Duration) != "1" || aws.ToString(params.OfferingType) != "Partial Upfront" {
+		t.Errorf("Unexpected request: %+v", params)
+	}
+
+	offering, err := describeRDSCompatibleOffering(context.Background(), client, docDBEngine, "db.r6g.xlarge", 1, "Partial Upfront")
+	if err != nil {
+		t.Fatalf("Failed to describe offering: %v", err)
+	}
+	if offering != nil {
+		t.Errorf("Expected no offering for db.r6g.xlarge, got %+v", offering)
+	}
+}
+
+func TestGetRDSCompatibleOnDemandPrice(t *testing.T) {
+	client := &fakeRDSCompatiblePricing{hourly: map[string]string{
+		"AmazonDocDB":   "0.5",
+		"AmazonNeptune": "0.75",
+	}}
+
+	tests := []struct {
+		engine   rdsCompatibleEngine
+		expected float64
+	}{
+		// I/O-Optimizedではなく、Standardの料金を使う
+		{docDBEngine, 360},
+		{neptuneEngine, 540},
+	}
+	for i, tt := range tests {
+		price, err := getRDSCompatibleOnDemandPrice(context.Background(), client, "ap-northeast-1", tt.engine, "db.r6g.large")
+		if err != nil {
+			t.Fatalf("%s: failed to get price: %v", tt.engine.serviceType, err)
+		}
+		if price != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.engine.serviceType, tt.expected, price)
+		}
+
+		params := client.params[i]
+		if aws.ToString(params.ServiceCode) != tt.engine.serviceCode {
+			t.Errorf("%s: expected service code %s, got %s", tt.engine.serviceType, tt.engine.serviceCode, aws.ToString(params.ServiceCode))
+		}
+		filters := map[string]string{}
+		for _, filter := range params.Filters {
+			filters[aws.ToString(filter.Field)] = aws.ToString(filter.Value)
+		}
+		if filters["instanceType"] != "db.r6g.large" || filters["regionCode"] != "ap-northeast-1" || filters["productFamily"] != "Database Instance" {
+			t.Errorf("%s: unexpected filters: %v", tt.engine.serviceType, filters)
+		}
+	}
+}
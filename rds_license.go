@@ -0,0 +1,178 @@
+package awsri
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// rdsLicensedEngine はエディションとライセンスモデルで料金が異なるエンジン（OracleとSQL Server）
+type rdsLicensedEngine struct {
+	databaseEngine string            // Pricing APIのdatabaseEngine
+	editions       map[string]string // エディション → Pricing APIのdatabaseEdition
+	defaultEdition string
+}
+
+var rdsLicensedEngines = map[string]rdsLicensedEngine{
+	"oracle": {
+		databaseEngine: "Oracle",
+		editions:       map[string]string{"se2": "Standard Two", "ee": "Enterprise"},
+		defaultEdition: "se2",
+	},
+	"sqlserver": {
+		databaseEngine: "SQL Server",
+		editions:       map[string]string{"ee": "Enterprise", "se": "Standard", "web": "Web", "ex": "Express"},
+		defaultEdition: "se",
+	},
+}
+
+// rdsLicenseModels はライセンスモデルごとのPricing APIのlicenseModelとproductDescriptionの接尾辞
+var rdsLicenseModels = map[string]struct {
+	pricing string
+	suffix  string
+}{
+	"license-included": {"License included", "li"},
+	"byol":             {"Bring your own license", "byol"},
+}
+
+// rdsLicenseModelAliases はproductDescriptionの接尾辞とDescribeDBInstancesのLicenseModelをライセンスモデルに変換する
+var rdsLicenseModelAliases = map[string]string{
+	"li":                     "license-included",
+	"license-included":       "license-included",
+	"byol":                   "byol",
+	"bring-your-own-license": "byol",
+}
+
+// rdsLicense はOracleまたはSQL Serverのエディションとライセンスモデル
+type rdsLicense struct {
+	databaseEngine     string // Pricing APIのdatabaseEngine（例: Oracle）
+	databaseEdition    string // Pricing APIのdatabaseEdition（例: Standard Two）
+	licenseModel       string // Pricing APIのlicenseModel（例: License included）
+	productDescription string // RIのオファリングのproductDescription（例: oracle-se2(li)）
+}
+
+// resolveRDSLicense はproductDescription（oracle, oracle-ee, oracle-se2(byol), sqlserver-web(li) など）と
+// --edition・--license-modelのオプションからエディションとライセンスモデルを決める
+// オプションはproductDescriptionの値より優先する
+// OracleとSQL Server以外のエンジンの場合はfalseを返す
+func resolveRDSLicense(productDescription, edition, licenseModel string) (rdsLicense, bool, error) {
+	description := strings.ToLower(strings.TrimSpace(productDescription))
+
+	// 末尾の "(li)" や "(byol)" をライセンスモデルとして取り出す
+	descriptionLicense := ""
+	if i := strings.Index(description, "("); i >= 0 && strings.HasSuffix(description, ")") {
+		descriptionLicense = description[i+1 : len(description)-1]
+		description = description[:i]
+	}
+
+	// DescribeDBInstancesのエンジン名（oracle-ee-cdb など）もproductDescriptionと同じ形式にする
+	description = strings.TrimSuffix(description, "-cdb")
+	name, descriptionEdition, _ := strings.Cut(description, "-")
+	if name == "sql server" {
+		name = "sqlserver"
+	}
+
+	engine, ok := rdsLicensedEngines[name]
+	if !ok {
+		if edition != "" || licenseModel != "" {
+			return rdsLicense{}, false, fmt.Errorf("edition and license-model are only available for oracle and sqlserver, got: %s", productDescription)
+		}
+		return rdsLicense{}, false, nil
+	}
+
+	if edition == "" {
+		edition = descriptionEdition
+	}
+	if edition == "" {
+		edition = engine.defaultEdition
+	}
+	databaseEdition, ok := engine.editions[edition]
+	if !ok {
+		return rdsLicense{}, false, fmt.Errorf("invalid edition for %s: %s (must be one of: %s)", name, edition, strings.Join(rdsEditionNames(engine), ", "))
+	}
+
+	if licenseModel == "" {
+		licenseModel = descriptionLicense
+	}
+	if licenseModel == "" {
+		// Oracle Enterprise EditionはBYOLのみ
+		licenseModel = "license-included"
+		if name == "oracle" && edition == "ee" {
+			licenseModel = "byol"
+		}
+	}
+	model, ok := rdsLicenseModels[rdsLicenseModelAliases[licenseModel]]
+	if !ok {
+		return rdsLicense{}, false, fmt.Errorf("invalid license-model: %s (must be one of: license-included, byol)", licenseModel)
+	}
+
+	return rdsLicense{
+		databaseEngine:     engine.databaseEngine,
+		databaseEdition:    databaseEdition,
+		licenseModel:       model.pricing,
+		productDescription: fmt.Sprintf("%s-%s(%s)", name, edition, model.suffix),
+	}, true, nil
+}
+
+// rdsEditionNames はエンジンのエディションを名前順に返す
+func rdsEditionNames(engine rdsLicensedEngine) []string {
+	names := make([]string, 0, len(engine.editions))
+	for name := range engine.editions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rdsProductDescription はDescribeDBInstancesのエンジンとライセンスモデルからRIのproductDescriptionを返す
+// OracleとSQL Server以外のエンジンはエンジン名をそのまま返す
+func rdsProductDescription(engine, licenseModel string) string {
+	if _, ok := rdsLicenseModelAliases[licenseModel]; !ok {
+		licenseModel = ""
+	}
+	license, ok, err := resolveRDSLicense(engine, "", licenseModel)
+	if err != nil || !ok {
+		return engine
+	}
+	return license.productDescription
+}
+
+// rdsPricingEngines はPricing APIのdatabaseEngineをRIのproductDescriptionのエンジン名に変換する
+var rdsPricingEngines = map[string]string{
+	"postgresql":        "postgresql",
+	"mysql":             "mysql",
+	"mariadb":           "mariadb",
+	"aurora postgresql": "aurora-postgresql",
+	"aurora mysql":      "aurora-mysql",
+	"oracle":            "oracle",
+	"sql server":        "sqlserver",
+}
+
+// rdsPricingProductDescription はPricing APIやCURのdatabaseEngine・databaseEdition・licenseModelからRIのproductDescriptionを返す
+// 対応していないエンジンの場合は空文字を返す
+func rdsPricingProductDescription(databaseEngine, databaseEdition, licenseModel string) string {
+	engine, ok := rdsPricingEngines[strings.ToLower(databaseEngine)]
+	if !ok {
+		return ""
+	}
+	licensed, ok := rdsLicensedEngines[engine]
+	if !ok {
+		return engine
+	}
+
+	edition := ""
+	for name, pricingEdition := range licensed.editions {
+		if strings.EqualFold(pricingEdition, databaseEdition) {
+			edition = name
+		}
+	}
+	model := "license-included"
+	if strings.EqualFold(licenseModel, rdsLicenseModels["byol"].pricing) {
+		model = "byol"
+	}
+	license, _, err := resolveRDSLicense(engine, edition, model)
+	if err != nil {
+		return engine
+	}
+	return license.productDescription
+}
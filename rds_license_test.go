@@ -0,0 +1,66 @@
+package awsri
+
+import "testing"
+
+func TestResolveRDSLicense(t *testing.T) {
+	tests := []struct {
+		productDescription         string
+		edition                    string
+		licenseModel               string
+		expectedProductDescription string
+		expectedEdition            string
+		expectedLicenseModel       string
+	}{
+		{"oracle", "", "", "oracle-se2(li)", "Standard Two", "License included"},
+		{"oracle-ee", "", "", "oracle-ee(byol)", "Enterprise", "Bring your own license"},
+		{"oracle-se2(byol)", "", "", "oracle-se2(byol)", "Standard Two", "Bring your own license"},
+		{"sqlserver-web(li)", "", "", "sqlserver-web(li)", "Web", "License included"},
+		// オプションはproductDescriptionの値より優先する
+		{"sqlserver-se(li)", "ee", "", "sqlserver-ee(li)", "Enterprise", "License included"},
+		{"oracle", "se2", "byol", "oracle-se2(byol)", "Standard Two", "Bring your own license"},
+	}
+
+	for _, tt := range tests {
+		license, ok, err := resolveRDSLicense(tt.productDescription, tt.edition, tt.licenseModel)
+		if err != nil || !ok {
+			t.Errorf("%s: unexpected result ok=%v err=%v", tt.productDescription, ok, err)
+			continue
+		}
+		if license.productDescription != tt.expectedProductDescription || license.databaseEdition != tt.expectedEdition ||
+			license.licenseModel != tt.expectedLicenseModel {
+			t.Errorf("%s: unexpected license %+v", tt.productDescription, license)
+		}
+	}
+
+	if _, ok, err := resolveRDSLicense("postgresql", "", ""); ok || err != nil {
+		t.Errorf("Expected postgresql to have no license, got ok=%v err=%v", ok, err)
+	}
+	for _, args := range [][3]string{
+		{"postgresql", "", "byol"},
+		{"oracle", "web", ""},
+		{"sqlserver", "se", "free"},
+	} {
+		if _, _, err := resolveRDSLicense(args[0], args[1], args[2]); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestRDSProductDescription(t *testing.T) {
+	tests := []struct {
+		engine       string
+		licenseModel string
+		expected     string
+	}{
+		{"oracle-ee-cdb", "bring-your-own-license", "oracle-ee(byol)"},
+		{"sqlserver-ex", "license-included", "sqlserver-ex(li)"},
+		{"postgres", "postgresql-license", "postgres"},
+		{"mysql", "general-public-license", "mysql"},
+	}
+
+	for _, tt := range tests {
+		if got := rdsProductDescription(tt.engine, tt.licenseModel); got != tt.expected {
+			t.Errorf("%s/%s: expected %s, got %s", tt.engine, tt.licenseModel, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,700 @@
+package awsri
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costexplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+// recommendationHeadings はawsriとCost Explorerの比較表の見出し
+var recommendationHeadings = []string{"Type", "Service", "Instance", "Region", "awsri", "Cost Explorer", "Difference", "awsri Savings (USD/mo)", "CE Savings (USD/mo)"}
+
+type RecommendationsOption struct {
+	Manifest     string `name:"manifest" required:"" help:"Path to a manifest JSON file generated by 'awsri generate --output=json' or 'awsri cur --output=json'"`
+	Region       string `name:"region" default:"ap-northeast-1" help:"Default AWS region for lines without a region"`
+	Duration     int    `name:"duration" default:"1" help:"Duration in years (1 or 3)"`
+	OfferingType string `name:"offering-type" default:"Partial Upfront" help:"Offering type (No Upfront, Partial Upfront, All Upfront)"`
+	LookbackDays int    `name:"lookback-days" default:"30" help:"Lookback period of the Cost Explorer recommendations in days (7, 30, 60)"`
+	Format       string `name:"format" default:"table" help:"Output format (table, csv, json)"`
+}
+
+type RecommendationsCommand struct {
+	opts RecommendationsOption
+
+	// テストでスタブに差し替えられるようにAPIクライアントの生成処理と料金の計算処理を保持する
+	newCostExplorerClient func(cfg aws.Config) costExplorerRecommendationAPI
+	estimate              func(ctx context.Context, instance InstanceInfo) (awsriEstimate, error)
+}
+
+// costExplorerRecommendationAPI は購入の推奨の取得に使うCost Explorer APIのサブセット
+type costExplorerRecommendationAPI interface {
+	GetReservationPurchaseRecommendation(ctx context.Context, params *costexplorer.GetReservationPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationPurchaseRecommendationOutput, error)
+	GetSavingsPlansPurchaseRecommendation(ctx context.Context, params *costexplorer.GetSavingsPlansPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansPurchaseRecommendationOutput, error)
+}
+
+// recommendationRIServices はCost ExplorerのRIの推奨と比較するサービス
+var recommendationRIServices = []string{"rds", "elasticache"}
+
+// recommendationLookbackPeriods は--lookback-daysの値ごとのCost Explorerの期間
+var recommendationLookbackPeriods = map[int]costexplorerTypes.LookbackPeriodInDays{
+	7:  costexplorerTypes.LookbackPeriodInDaysSevenDays,
+	30: costexplorerTypes.LookbackPeriodInDaysThirtyDays,
+	60: costexplorerTypes.LookbackPeriodInDaysSixtyDays,
+}
+
+// recommendationPaymentOptions はオファリングタイプごとのCost Explorerの支払いオプション
+var recommendationPaymentOptions = map[string]costexplorerTypes.PaymentOption{
+	"No Upfront":      costexplorerTypes.PaymentOptionNoUpfront,
+	"Partial Upfront": costexplorerTypes.PaymentOptionPartialUpfront,
+	"All Upfront":     costexplorerTypes.PaymentOptionAllUpfront,
+}
+
+// awsriEstimate はawsriで計算した1行の購入量と節約額
+type awsriEstimate struct {
+	quantity       float64 // RIの台数、またはSavings Plansの時間あたりのコミットメント（USD）
+	monthlySavings float64 // オンデマンドと比べた月あたりの節約額（USD）
+	onDemandHourly float64 // 時間あたりのオンデマンド料金（USD）
+}
+
+// ceRIRecommendation はCost ExplorerのRIの推奨をインスタンスタイプごとに合計したもの
+type ceRIRecommendation struct {
+	serviceType        string
+	instanceType       string
+	description        string
+	multiAz            bool
+	region             string
+	count              float64
+	averageUsed        float64 // 時間あたりの平均のインスタンス数
+	minimumUsed        float64
+	maximumUsed        float64
+	averageUtilization float64 // 推奨どおりに購入した場合の平均の利用率（%）
+	monthlySavings     float64
+}
+
+// ceSPRecommendation はCost ExplorerのCompute Savings Plansの推奨
+type ceSPRecommendation struct {
+	commitment      float64 // 時間あたりのコミットメント（USD）
+	monthlySavings  float64
+	averageOnDemand float64 // 期間内の時間あたりのオンデマンドの利用額（USD）
+	minimumOnDemand float64
+	maximumOnDemand float64
+}
+
+// RecommendationLine はawsriとCost Explorerの購入量と節約額の比較の1行
+type RecommendationLine struct {
+	Type                string   `json:"type"` // ri, savings-plans
+	Service             string   `json:"service"`
+	InstanceType        string   `json:"instance_type,omitempty"`
+	Description         string   `json:"description,omitempty"`
+	MultiAz             bool     `json:"multi_az,omitempty"`
+	Region              string   `json:"region,omitempty"`
+	Awsri               float64  `json:"awsri"`         // RIの台数、またはSavings Plansの時間あたりのコミットメント
+	CostExplorer        float64  `json:"cost_explorer"` // 同上
+	AwsriSavings        float64  `json:"awsri_monthly_savings"`
+	CostExplorerSavings float64  `json:"cost_explorer_monthly_savings"`
+	Reasons             []string `json:"reasons,omitempty"`
+}
+
+// RecommendationReport はマニフェストの全行の比較結果
+type RecommendationReport struct {
+	LookbackDays int                  `json:"lookback_days"`
+	Duration     int                  `json:"duration"`
+	OfferingType string               `json:"offering_type"`
+	Lines        []RecommendationLine `json:"lines"`
+}
+
+func NewRecommendationsCommand(opts RecommendationsOption) *RecommendationsCommand {
+	c := &RecommendationsCommand{
+		opts: opts,
+		newCostExplorerClient: func(cfg aws.Config) costExplorerRecommendationAPI {
+			return costexplorer.NewFromConfig(cfg)
+		},
+	}
+	c.estimate = c.estimateInstance
+	return c
+}
+
+func (c *RecommendationsCommand) Run(ctx context.Context) error {
+	if c.opts.Duration != 1 && c.opts.Duration != 3 {
+		return fmt.Errorf("duration must be 1 or 3 years, got: %d", c.opts.Duration)
+	}
+	if _, ok := recommendationLookbackPeriods[c.opts.LookbackDays]; !ok {
+		return fmt.Errorf("lookback-days must be 7, 30 or 60, got: %d", c.opts.LookbackDays)
+	}
+	if _, ok := recommendationPaymentOptions[c.opts.OfferingType]; !ok {
+		return fmt.Errorf("invalid offering-type: %s (must be one of: No Upfront, Partial Upfront, All Upfront)", c.opts.OfferingType)
+	}
+	if c.opts.Format != "table" && c.opts.Format != "csv" && c.opts.Format != "json" {
+		return fmt.Errorf("unsupported output format: %s", c.opts.Format)
+	}
+
+	manifest, err := LoadManifest(c.opts.Manifest)
+	if err != nil {
+		return err
+	}
+
+	// Cost Explorerのエンドポイントはus-east-1のみ
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config: %w", err)
+	}
+
+	report, err := c.report(ctx, c.newCostExplorerClient(cfg), manifest.InstanceInfos())
+	if err != nil {
+		return err
+	}
+
+	switch c.opts.Format {
+	case "csv":
+		return renderRecommendationCSV(report)
+	case "json":
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+	default:
+		renderRecommendationTable(report)
+	}
+	return nil
+}
+
+// report はCost Explorerの推奨を取得し、マニフェストの行ごとのawsriの計算と並べる
+func (c *RecommendationsCommand) report(ctx context.Context, client costExplorerRecommendationAPI, instances []InstanceInfo) (RecommendationReport, error) {
+	report := RecommendationReport{LookbackDays: c.opts.LookbackDays, Duration: c.opts.Duration, OfferingType: c.opts.OfferingType}
+
+	riRecommendations := make(map[string]*ceRIRecommendation)
+	for _, service := range recommendationRIServices {
+		if err := c.getRIRecommendations(ctx, client, service, riRecommendations); err != nil {
+			return report, err
+		}
+	}
+	spRecommendation, err := c.getSPRecommendation(ctx, client)
+	if err != nil {
+		return report, err
+	}
+
+	// 同じインスタンスタイプの行（タグやアカウントごとの行）をまとめる
+	lines := make(map[string]*InstanceInfo)
+	var keys []string
+	var sp awsriEstimate
+	spLines := 0
+	for _, instance := range instances {
+		if instance.Region == "" {
+			instance.Region = c.opts.Region
+		}
+		switch instance.ServiceType {
+		case "rds", "elasticache":
+			key := recommendationKey(instance.ServiceType, instance.InstanceType, instance.Description, instance.MultiAz, instance.Region)
+			if line, ok := lines[key]; ok {
+				line.Count += instance.Count
+				continue
+			}
+			line := instance
+			lines[key] = &line
+			keys = append(keys, key)
+		case "ec2", "fargate":
+			// Compute Savings Plansはアカウント全体で1つの推奨のため、EC2とFargateの行を合計する
+			estimate, err := c.estimate(ctx, instance)
+			if err != nil {
+				return report, err
+			}
+			sp.quantity += estimate.quantity
+			sp.monthlySavings += estimate.monthlySavings
+			sp.onDemandHourly += estimate.onDemandHourly
+			spLines++
+		default:
+			fmt.Fprintf(os.Stderr, "Note: skipping %s %s: Cost Explorer recommendations are compared for RDS, ElastiCache, EC2 and Fargate only\n", instance.ServiceType, instance.InstanceType)
+		}
+	}
+
+	for _, key := range keys {
+		instance := lines[key]
+		estimate, err := c.estimate(ctx, *instance)
+		if err != nil {
+			return report, err
+		}
+		recommendation := riRecommendations[key]
+		delete(riRecommendations, key)
+
+		line := RecommendationLine{
+			Type:         "ri",
+			Service:      serviceDisplayName(instance.ServiceType),
+			InstanceType: instance.InstanceType,
+			Description:  instance.Description,
+			MultiAz:      instance.MultiAz,
+			Region:       instance.Region,
+			Awsri:        estimate.quantity,
+			AwsriSavings: estimate.monthlySavings,
+		}
+		if recommendation != nil {
+			line.CostExplorer = recommendation.count
+			line.CostExplorerSavings = recommendation.monthlySavings
+		}
+		line.Reasons = riDifferenceReasons(instance.Count, estimate, recommendation, c.opts.LookbackDays)
+		report.Lines = append(report.Lines, line)
+	}
+
+	// マニフェストにないCost Explorerの推奨
+	var remaining []*ceRIRecommendation
+	for _, recommendation := range riRecommendations {
+		remaining = append(remaining, recommendation)
+	}
+	sort.Slice(remaining, func(i, j int) bool {
+		a, b := remaining[i], remaining[j]
+		return recommendationKey(a.serviceType, a.instanceType, a.description, a.multiAz, a.region) <
+			recommendationKey(b.serviceType, b.instanceType, b.description, b.multiAz, b.region)
+	})
+	for _, recommendation := range remaining {
+		report.Lines = append(report.Lines, RecommendationLine{
+			Type:                "ri",
+			Service:             serviceDisplayName(recommendation.serviceType),
+			InstanceType:        recommendation.instanceType,
+			Description:         recommendation.description,
+			MultiAz:             recommendation.multiAz,
+			Region:              recommendation.region,
+			CostExplorer:        recommendation.count,
+			CostExplorerSavings: recommendation.monthlySavings,
+			Reasons:             riDifferenceReasons(0, awsriEstimate{}, recommendation, c.opts.LookbackDays),
+		})
+	}
+
+	if spLines > 0 || spRecommendation != nil {
+		line := RecommendationLine{
+			Type:         "savings-plans",
+			Service:      "Compute Savings Plans",
+			Awsri:        sp.quantity,
+			AwsriSavings: sp.monthlySavings,
+		}
+		if spRecommendation != nil {
+			line.CostExplorer = spRecommendation.commitment
+			line.CostExplorerSavings = spRecommendation.monthlySavings
+		}
+		line.Reasons = spDifferenceReasons(sp, spLines, spRecommendation, c.opts.LookbackDays)
+		report.Lines = append(report.Lines, line)
+	}
+
+	return report, nil
+}
+
+// getRIRecommendations はサービスのRIの推奨を取得し、インスタンスタイプごとに合計する
+func (c *RecommendationsCommand) getRIRecommendations(ctx context.Context, client costExplorerRecommendationAPI, service string, recommendations map[string]*ceRIRecommendation) error {
+	input := &costexplorer.GetReservationPurchaseRecommendationInput{
+		Service:              aws.String(costExplorerServices[service]),
+		LookbackPeriodInDays: recommendationLookbackPeriods[c.opts.LookbackDays],
+		TermInYears:          recommendationTerm(c.opts.Duration),
+		PaymentOption:        recommendationPaymentOptions[c.opts.OfferingType],
+	}
+	for {
+		output, err := client.GetReservationPurchaseRecommendation(ctx, input)
+		if err != nil {
+			if isDataUnavailable(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to get reservation purchase recommendations for %s: %w", service, err)
+		}
+		for _, recommendation := range output.Recommendations {
+			for _, detail := range recommendation.RecommendationDetails {
+				next, ok := ceRIRecommendationFromDetail(detail)
+				if !ok {
+					continue
+				}
+				key := recommendationKey(next.serviceType, next.instanceType, next.description, next.multiAz, next.region)
+				existing, ok := recommendations[key]
+				if !ok {
+					recommendations[key] = &next
+					continue
+				}
+				// 連結アカウントごとの推奨は合計する
+				existing.count += next.count
+				existing.averageUsed += next.averageUsed
+				existing.minimumUsed += next.minimumUsed
+				existing.maximumUsed += next.maximumUsed
+				existing.monthlySavings += next.monthlySavings
+			}
+		}
+		if output.NextPageToken == nil {
+			return nil
+		}
+		input.NextPageToken = output.NextPageToken
+	}
+}
+
+// ceRIRecommendationFromDetail はRDSまたはElastiCacheの推奨の明細を変換する
+func ceRIRecommendationFromDetail(detail costexplorerTypes.ReservationPurchaseRecommendationDetail) (ceRIRecommendation, bool) {
+	recommendation := ceRIRecommendation{
+		count:              parseCostExplorerAmount(detail.RecommendedNumberOfInstancesToPurchase),
+		averageUsed:        parseCostExplorerAmount(detail.AverageNumberOfInstancesUsedPerHour),
+		minimumUsed:        parseCostExplorerAmount(detail.MinimumNumberOfInstancesUsedPerHour),
+		maximumUsed:        parseCostExplorerAmount(detail.MaximumNumberOfInstancesUsedPerHour),
+		averageUtilization: parseCostExplorerAmount(detail.AverageUtilization),
+		monthlySavings:     parseCostExplorerAmount(detail.EstimatedMonthlySavingsAmount),
+	}
+	if detail.InstanceDetails == nil {
+		return recommendation, false
+	}
+
+	switch {
+	case detail.InstanceDetails.RDSInstanceDetails != nil:
+		rdsDetails := detail.InstanceDetails.RDSInstanceDetails
+		recommendation.serviceType = "rds"
+		recommendation.instanceType = aws.ToString(rdsDetails.InstanceType)
+		recommendation.region = recommendationRegion(aws.ToString(rdsDetails.Region))
+		recommendation.multiAz = strings.HasPrefix(aws.ToString(rdsDetails.DeploymentOption), "Multi-AZ")
+		recommendation.description = rdsPricingProductDescription(aws.ToString(rdsDetails.DatabaseEngine), aws.ToString(rdsDetails.DatabaseEdition), aws.ToString(rdsDetails.LicenseModel))
+		if recommendation.description == "" {
+			recommendation.description = strings.ToLower(aws.ToString(rdsDetails.DatabaseEngine))
+		}
+	case detail.InstanceDetails.ElastiCacheInstanceDetails != nil:
+		cacheDetails := detail.InstanceDetails.ElastiCacheInstanceDetails
+		recommendation.serviceType = "elasticache"
+		recommendation.instanceType = aws.ToString(cacheDetails.NodeType)
+		recommendation.region = recommendationRegion(aws.ToString(cacheDetails.Region))
+		recommendation.description = strings.ToLower(aws.ToString(cacheDetails.ProductDescription))
+	default:
+		return recommendation, false
+	}
+	return recommendation, true
+}
+
+// getSPRecommendation はCompute Savings Plansの推奨を取得する（推奨がない場合はnil）
+func (c *RecommendationsCommand) getSPRecommendation(ctx context.Context, client costExplorerRecommendationAPI) (*ceSPRecommendation, error) {
+	input := &costexplorer.GetSavingsPlansPurchaseRecommendationInput{
+		SavingsPlansType:     costexplorerTypes.SupportedSavingsPlansTypeComputeSp,
+		LookbackPeriodInDays: recommendationLookbackPeriods[c.opts.LookbackDays],
+		TermInYears:          recommendationTerm(c.opts.Duration),
+		PaymentOption:        recommendationPaymentOptions[c.opts.OfferingType],
+	}
+	var result *ceSPRecommendation
+	for {
+		output, err := client.GetSavingsPlansPurchaseRecommendation(ctx, input)
+		if err != nil {
+			if isDataUnavailable(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get Savings Plans purchase recommendation: %w", err)
+		}
+		recommendation := output.SavingsPlansPurchaseRecommendation
+		if recommendation != nil {
+			if result == nil && recommendation.SavingsPlansPurchaseRecommendationSummary != nil {
+				summary := recommendation.SavingsPlansPurchaseRecommendationSummary
+				result = &ceSPRecommendation{
+					commitment:     parseCostExplorerAmount(summary.HourlyCommitmentToPurchase),
+					monthlySavings: parseCostExplorerAmount(summary.EstimatedMonthlySavingsAmount),
+				}
+			}
+			if result != nil {
+				for _, detail := range recommendation.SavingsPlansPurchaseRecommendationDetails {
+					result.averageOnDemand += parseCostExplorerAmount(detail.CurrentAverageHourlyOnDemandSpend)
+					result.minimumOnDemand += parseCostExplorerAmount(detail.CurrentMinimumHourlyOnDemandSpend)
+					result.maximumOnDemand += parseCostExplorerAmount(detail.CurrentMaximumHourlyOnDemandSpend)
+				}
+			}
+		}
+		if output.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = output.NextPageToken
+	}
+	if result != nil && result.commitment == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// estimateInstance はマニフェストの1行のawsriの購入量と節約額を計算する
+// RDSとElastiCacheはtotalと同じオファリングの料金、EC2とFargateはcompute-savings-plansと同じレートを使う
+func (c *RecommendationsCommand) estimateInstance(ctx context.Context, instance InstanceInfo) (awsriEstimate, error) {
+	count := float64(instance.Count)
+	hoursPerMonth := 720.0
+	months := DurationToMonths(c.opts.Duration)
+	paymentOption := strings.ToLower(strings.ReplaceAll(c.opts.OfferingType, " ", "-"))
+
+	switch instance.ServiceType {
+	case "rds", "elasticache":
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(instance.Region))
+		if err != nil {
+			return awsriEstimate{}, fmt.Errorf("unable to load SDK config: %w", err)
+		}
+		total := NewTotalCommand(TotalOption{Duration: c.opts.Duration, OfferingType: c.opts.OfferingType})
+
+		var upfront, monthly, onDemandMonthly float64
+		if instance.ServiceType == "rds" {
+			upfront, monthly, _, err = total.calculateRDSPrice(ctx, cfg, instance)
+			if err != nil {
+				return awsriEstimate{}, err
+			}
+			rdsCmd := NewRDSCommand(RDSOption{DbInstanceClass: instance.InstanceType, ProductDescription: instance.Description, MultiAz: instance.MultiAz})
+			databaseEngine, err := rdsCmd.getDatabaseEngine(instance.Description)
+			if err != nil {
+				return awsriEstimate{}, fmt.Errorf("failed to get database engine: %w", err)
+			}
+			onDemandMonthly, err = rdsCmd.getRdsOnDemandPrice(cfg, instance.InstanceType, databaseEngine, instance.MultiAz)
+			if err != nil {
+				return awsriEstimate{}, fmt.Errorf("failed to get on-demand price for RDS %s: %w", instance.InstanceType, err)
+			}
+		} else {
+			upfront, monthly, _, err = total.calculateElastiCachePrice(ctx, cfg, instance)
+			if err != nil {
+				return awsriEstimate{}, err
+			}
+			cacheCmd := NewElastiCacheCommand(ElasticacheOption{CacheNodeType: instance.InstanceType, ProductDescription: instance.Description})
+			onDemandMonthly, err = cacheCmd.getElastiCacheOnDemandPrice(cfg, instance.InstanceType, instance.Description)
+			if err != nil {
+				return awsriEstimate{}, fmt.Errorf("failed to get on-demand price for ElastiCache %s: %w", instance.InstanceType, err)
+			}
+		}
+
+		reservedMonthly := upfront/float64(months) + monthly
+		return awsriEstimate{
+			quantity:       count,
+			monthlySavings: (onDemandMonthly - reservedMonthly) * count,
+			onDemandHourly: onDemandMonthly / hoursPerMonth * count,
+		}, nil
+
+	case "ec2":
+		platform, ok := ec2PlatformFromDescription(instance.Description)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Warning: unknown EC2 platform %q for %s; using Linux/UNIX\n", instance.Description, instance.InstanceType)
+		}
+		// Pricing APIとSavings Plans APIはus-east-1でのみ利用可能
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+		if err != nil {
+			return awsriEstimate{}, fmt.Errorf("unable to load SDK config: %w", err)
+		}
+		ec2Cmd := NewEC2Command(EC2Option{
+			Region:            instance.Region,
+			InstanceType:      instance.InstanceType,
+			Count:             instance.Count,
+			Duration:          c.opts.Duration,
+			PaymentOption:     paymentOption,
+			EC2PlatformOption: platform,
+		})
+		onDemand, err := ec2Cmd.getEC2OnDemandPrice(cfg)
+		if err != nil {
+			return awsriEstimate{}, fmt.Errorf("failed to get on-demand price for EC2 %s: %w", instance.InstanceType, err)
+		}
+		spPrice, err := ec2Cmd.getComputeSavingsPlanPrice(ctx, cfg)
+		if err != nil {
+			return awsriEstimate{}, fmt.Errorf("failed to get Savings Plan price for EC2 %s: %w", instance.InstanceType, err)
+		}
+		return awsriEstimate{
+			quantity:       spPrice * count,
+			monthlySavings: (onDemand - spPrice) * hoursPerMonth * count,
+			onDemandHourly: onDemand * count,
+		}, nil
+
+	case "fargate":
+		// InstanceTypeはECSのタスクのCPUユニット/メモリ（MB）（例: 512/1024）
+		var cpu, memory float64
+		if _, err := fmt.Sscanf(instance.InstanceType, "%g/%g", &cpu, &memory); err != nil {
+			return awsriEstimate{}, fmt.Errorf("invalid Fargate task size: %s", instance.InstanceType)
+		}
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+		if err != nil {
+			return awsriEstimate{}, fmt.Errorf("unable to load SDK config: %w", err)
+		}
+		fargateCmd := NewFargateCommand(FargateOption{
+			Region:        instance.Region,
+			Duration:      c.opts.Duration,
+			Architecture:  instance.Description,
+			PaymentOption: paymentOption,
+		})
+		onDemand, err := fargateCmd.getFargateOnDemandPrice(ctx, cfg)
+		if err != nil {
+			return awsriEstimate{}, fmt.Errorf("failed to get on-demand price for Fargate: %w", err)
+		}
+		spPricing, err := fargateCmd.getComputeSavingsPlanPrice(ctx, cfg)
+		if err != nil {
+			return awsriEstimate{}, fmt.Errorf("failed to get Savings Plan price for Fargate: %w", err)
+		}
+		// ECSのCPUユニットは1024で1 vCPU
+		vcpu := cpu / 1024 * count
+		memoryGB := memory / 1024 * count
+		onDemandHourly := vcpu*onDemand.VCPUOnDemandPrice + memoryGB*onDemand.MemoryOnDemandPrice
+		spHourly := vcpu*spPricing.VCPUSPPrice + memoryGB*spPricing.MemorySPPrice
+		return awsriEstimate{
+			quantity:       spHourly,
+			monthlySavings: (onDemandHourly - spHourly) * hoursPerMonth,
+			onDemandHourly: onDemandHourly,
+		}, nil
+	}
+
+	return awsriEstimate{}, fmt.Errorf("unsupported service type: %s", instance.ServiceType)
+}
+
+// riDifferenceReasons はRIの台数と節約額がCost Explorerと異なる理由を推定する
+func riDifferenceReasons(count int, estimate awsriEstimate, recommendation *ceRIRecommendation, lookbackDays int) []string {
+	if recommendation == nil || recommendation.count == 0 {
+		return []string{fmt.Sprintf("no Cost Explorer recommendation: the usage of the last %d days is already covered by reservations, or too short or irregular to break even", lookbackDays)}
+	}
+	if count == 0 {
+		return []string{fmt.Sprintf("not in the manifest: Cost Explorer saw up to %.1f instances per hour in the last %d days, e.g. in accounts or regions not scanned, or instances stopped since", recommendation.maximumUsed, lookbackDays)}
+	}
+
+	var reasons []string
+	fleet := float64(count)
+	switch {
+	case recommendation.count < fleet && recommendation.averageUsed < fleet:
+		reasons = append(reasons, fmt.Sprintf("Cost Explorer saw %.1f on-demand instances per hour on average (minimum %.1f) in the last %d days, fewer than the %d in the manifest; usage covered by existing reservations and instances launched since are not counted",
+			recommendation.averageUsed, recommendation.minimumUsed, lookbackDays, count))
+	case recommendation.count < fleet:
+		reasons = append(reasons, fmt.Sprintf("Cost Explorer buys fewer than the average usage (%.1f per hour) to keep the reservations used in hours with less usage (minimum %.1f)",
+			recommendation.averageUsed, recommendation.minimumUsed))
+	case recommendation.count > fleet:
+		reasons = append(reasons, fmt.Sprintf("Cost Explorer saw up to %.1f instances per hour in the last %d days, more than the %d in the manifest; instances stopped since or in accounts and regions not in the manifest are counted",
+			recommendation.maximumUsed, lookbackDays, count))
+	}
+
+	// 1台あたりの節約額の差
+	awsriPerInstance := estimate.monthlySavings / fleet
+	cePerInstance := recommendation.monthlySavings / recommendation.count
+	if relativeDifference(awsriPerInstance, cePerInstance) > 0.1 {
+		if recommendation.averageUtilization > 0 && recommendation.averageUtilization < 99.5 {
+			reasons = append(reasons, fmt.Sprintf("Cost Explorer's savings assume %.1f%% average utilization of the purchase; awsri assumes every reserved instance runs all month",
+				recommendation.averageUtilization))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("savings per instance differ (awsri %.2f, Cost Explorer %.2f USD/month): awsri uses list prices for 720 hours a month, Cost Explorer the on-demand cost billed in the last %d days",
+				awsriPerInstance, cePerInstance, lookbackDays))
+		}
+	}
+	return reasons
+}
+
+// spDifferenceReasons はSavings Plansのコミットメントと節約額がCost Explorerと異なる理由を推定する
+func spDifferenceReasons(estimate awsriEstimate, lines int, recommendation *ceSPRecommendation, lookbackDays int) []string {
+	if recommendation == nil {
+		return []string{fmt.Sprintf("no Cost Explorer recommendation: the compute usage of the last %d days is already covered by Savings Plans and reservations", lookbackDays)}
+	}
+	if lines == 0 {
+		return []string{"no EC2 or Fargate lines in the manifest; Cost Explorer's commitment covers EC2, Fargate and Lambda usage of the account"}
+	}
+	if relativeDifference(estimate.quantity, recommendation.commitment) <= 0.05 &&
+		relativeDifference(estimate.monthlySavings, recommendation.monthlySavings) <= 0.1 {
+		return nil
+	}
+
+	reasons := []string{fmt.Sprintf("Cost Explorer covers all EC2, Fargate and Lambda usage of the account in the last %d days; awsri covers the %d EC2 and Fargate lines of the manifest running all month", lookbackDays, lines)}
+	if recommendation.averageOnDemand > 0 && recommendation.averageOnDemand < estimate.onDemandHourly*0.95 {
+		reasons = append(reasons, fmt.Sprintf("on-demand spend in the last %d days averaged %.2f USD/h against %.2f USD/h for the manifest; usage already covered by reservations or Savings Plans is not on demand",
+			lookbackDays, recommendation.averageOnDemand, estimate.onDemandHourly))
+	}
+	if recommendation.commitment < estimate.quantity && recommendation.minimumOnDemand < recommendation.maximumOnDemand {
+		reasons = append(reasons, fmt.Sprintf("hourly on-demand spend varied between %.2f and %.2f USD/h; Cost Explorer sizes the commitment for that variation (see sp-optimize)",
+			recommendation.minimumOnDemand, recommendation.maximumOnDemand))
+	}
+	return reasons
+}
+
+// recommendationKey はRIの推奨とマニフェストの行を突き合わせるキーを返す
+func recommendationKey(serviceType, instanceType, description string, multiAz bool, region string) string {
+	switch serviceType {
+	case "rds":
+		description = normalizeRDSEngine(description)
+	case "elasticache":
+		description = normalizeCacheEngine(description)
+	}
+	return fmt.Sprintf("%s|%s|%s|%t|%s", serviceType, addInstanceTypePrefix(serviceType, instanceType), description, multiAz, region)
+}
+
+// recommendationRegion はCost Explorerの推奨のリージョン名（例: Asia Pacific (Tokyo)）をリージョンコードに変換する
+func recommendationRegion(location string) string {
+	if region := mapLocationToRegion(location); region != "" {
+		return region
+	}
+	return location
+}
+
+// recommendationTerm はRIの期間（年）をCost Explorerの期間に変換する
+func recommendationTerm(duration int) costexplorerTypes.TermInYears {
+	if duration == 3 {
+		return costexplorerTypes.TermInYearsThreeYears
+	}
+	return costexplorerTypes.TermInYearsOneYear
+}
+
+// relativeDifference は2つの値の相対的な差を返す
+func relativeDifference(a, b float64) float64 {
+	if a == b {
+		return 0
+	}
+	return math.Abs(a-b) / math.Max(math.Abs(a), math.Abs(b))
+}
+
+// recommendationRow は比較の1行を表のセルに変換する
+func recommendationRow(line RecommendationLine) []string {
+	format := "%.0f"
+	if line.Type == "savings-plans" {
+		format = "%.5f"
+	}
+	instance := line.InstanceType
+	if line.Description != "" {
+		instance += " " + line.Description
+	}
+	if line.MultiAz {
+		instance += " (Multi-AZ)"
+	}
+	difference := "="
+	if line.Awsri != line.CostExplorer {
+		difference = fmt.Sprintf("%+"+format[1:], line.CostExplorer-line.Awsri)
+	}
+	return []string{
+		line.Type,
+		line.Service,
+		instance,
+		line.Region,
+		fmt.Sprintf(format, line.Awsri),
+		fmt.Sprintf(format, line.CostExplorer),
+		difference,
+		fmt.Sprintf("%.0f", line.AwsriSavings),
+		fmt.Sprintf("%.0f", line.CostExplorerSavings),
+	}
+}
+
+// renderRecommendationTable はテーブル形式で比較結果と差の理由を表示する
+func renderRecommendationTable(report RecommendationReport) {
+	table := NewTable(recommendationHeadings)
+	for _, line := range report.Lines {
+		table.Append(recommendationRow(line))
+	}
+	table.Render()
+
+	var notes []string
+	for _, line := range report.Lines {
+		row := recommendationRow(line)
+		label := strings.Join(strings.Fields(strings.Join(row[1:4], " ")), " ")
+		for _, reason := range line.Reasons {
+			notes = append(notes, fmt.Sprintf("- %s: %s", label, reason))
+		}
+	}
+	if len(notes) > 0 {
+		fmt.Println()
+		fmt.Println("Why the numbers differ:")
+		fmt.Println(strings.Join(notes, "\n"))
+	}
+}
+
+// renderRecommendationCSV はCSV形式で比較結果を表示する
+func renderRecommendationCSV(report RecommendationReport) error {
+	writer := csv.NewWriter(os.Stdout)
+	if err := writer.Write(append(recommendationHeadings, "Reasons")); err != nil {
+		return err
+	}
+	for _, line := range report.Lines {
+		if err := writer.Write(append(recommendationRow(line), strings.Join(line.Reasons, "; "))); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
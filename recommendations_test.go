@@ -0,0 +1,141 @@
+package awsri
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costexplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+// fakeRecommendations はRDSとElastiCacheのRIの推奨とCompute Savings Plansの推奨を返すCost Explorerのスタブ
+type fakeRecommendations struct{}
+
+func (f *fakeRecommendations) GetReservationPurchaseRecommendation(ctx context.Context, params *costexplorer.GetReservationPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationPurchaseRecommendationOutput, error) {
+	if aws.ToString(params.Service) != costExplorerServices["rds"] {
+		return nil, &costexplorerTypes.DataUnavailableException{}
+	}
+	detail := func(engine, instanceType, count, average, savings string) costexplorerTypes.ReservationPurchaseRecommendationDetail {
+		return costexplorerTypes.ReservationPurchaseRecommendationDetail{
+			RecommendedNumberOfInstancesToPurchase: aws.String(count),
+			AverageNumberOfInstancesUsedPerHour:    aws.String(average),
+			MinimumNumberOfInstancesUsedPerHour:    aws.String("1"),
+			MaximumNumberOfInstancesUsedPerHour:    aws.String("4"),
+			AverageUtilization:                     aws.String("100"),
+			EstimatedMonthlySavingsAmount:          aws.String(savings),
+			InstanceDetails: &costexplorerTypes.InstanceDetails{
+				RDSInstanceDetails: &costexplorerTypes.RDSInstanceDetails{
+					DatabaseEngine:   aws.String(engine),
+					InstanceType:     aws.String(instanceType),
+					DeploymentOption: aws.String("Single-AZ"),
+					LicenseModel:     aws.String("No license required"),
+					Region:           aws.String("Asia Pacific (Tokyo)"),
+				},
+			},
+		}
+	}
+	// 連結アカウントごとの推奨は2ページ目に分かれている
+	if params.NextPageToken == nil {
+		return &costexplorer.GetReservationPurchaseRecommendationOutput{
+			Recommendations: []costexplorerTypes.ReservationPurchaseRecommendation{
+				{RecommendationDetails: []costexplorerTypes.ReservationPurchaseRecommendationDetail{detail("PostgreSQL", "db.r6g.large", "1", "1.5", "50")}},
+			},
+			NextPageToken: aws.String("page2"),
+		}, nil
+	}
+	return &costexplorer.GetReservationPurchaseRecommendationOutput{
+		Recommendations: []costexplorerTypes.ReservationPurchaseRecommendation{
+			{RecommendationDetails: []costexplorerTypes.ReservationPurchaseRecommendationDetail{
+				detail("PostgreSQL", "db.r6g.large", "1", "0.5", "50"),
+				detail("MySQL", "db.m6g.large", "2", "2", "80"),
+			}},
+		},
+	}, nil
+}
+
+func (f *fakeRecommendations) GetSavingsPlansPurchaseRecommendation(ctx context.Context, params *costexplorer.GetSavingsPlansPurchaseRecommendationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansPurchaseRecommendationOutput, error) {
+	return &costexplorer.GetSavingsPlansPurchaseRecommendationOutput{
+		SavingsPlansPurchaseRecommendation: &costexplorerTypes.SavingsPlansPurchaseRecommendation{
+			SavingsPlansPurchaseRecommendationSummary: &costexplorerTypes.SavingsPlansPurchaseRecommendationSummary{
+				HourlyCommitmentToPurchase:    aws.String("0.5"),
+				EstimatedMonthlySavingsAmount: aws.String("100"),
+			},
+			SavingsPlansPurchaseRecommendationDetails: []costexplorerTypes.SavingsPlansPurchaseRecommendationDetail{
+				{
+					CurrentAverageHourlyOnDemandSpend: aws.String("0.8"),
+					CurrentMinimumHourlyOnDemandSpend: aws.String("0.4"),
+					CurrentMaximumHourlyOnDemandSpend: aws.String("1.2"),
+				},
+			},
+		},
+	}, nil
+}
+
+func TestRecommendationsReport(t *testing.T) {
+	cmd := NewRecommendationsCommand(RecommendationsOption{Region: "ap-northeast-1", Duration: 1, OfferingType: "Partial Upfront", LookbackDays: 30})
+	// 料金はPricing APIの代わりに1台あたりの固定値を使う
+	cmd.estimate = func(ctx context.Context, instance InstanceInfo) (awsriEstimate, error) {
+		count := float64(instance.Count)
+		if instance.ServiceType == "ec2" {
+			return awsriEstimate{quantity: 0.1 * count, monthlySavings: 30 * count, onDemandHourly: 0.15 * count}, nil
+		}
+		return awsriEstimate{quantity: count, monthlySavings: 50 * count, onDemandHourly: 0.2 * count}, nil
+	}
+
+	instances := []InstanceInfo{
+		{ServiceType: "rds", InstanceType: "db.r6g.large", Description: "postgres", Count: 2, Region: "ap-northeast-1"},
+		{ServiceType: "rds", InstanceType: "db.r6g.large", Description: "postgres", Count: 1},
+		{ServiceType: "elasticache", InstanceType: "cache.r7g.large", Description: "redis", Count: 2, Region: "ap-northeast-1"},
+		{ServiceType: "ec2", InstanceType: "m7g.large", Description: "Linux/UNIX", Count: 10, Region: "ap-northeast-1"},
+		{ServiceType: "opensearch", InstanceType: "r6g.large.search", Count: 1, Region: "ap-northeast-1"},
+	}
+	report, err := cmd.report(context.Background(), &fakeRecommendations{}, instances)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []RecommendationLine{
+		// 2ページの推奨を合計して2台、マニフェストの行も合計して3台
+		{Type: "ri", Service: "RDS", InstanceType: "db.r6g.large", Region: "ap-northeast-1", Awsri: 3, CostExplorer: 2, AwsriSavings: 150, CostExplorerSavings: 100},
+		{Type: "ri", Service: "ElastiCache", InstanceType: "cache.r7g.large", Region: "ap-northeast-1", Awsri: 2, AwsriSavings: 100},
+		// マニフェストにない推奨
+		{Type: "ri", Service: "RDS", InstanceType: "db.m6g.large", Region: "ap-northeast-1", CostExplorer: 2, CostExplorerSavings: 80},
+		{Type: "savings-plans", Service: "Compute Savings Plans", Awsri: 1, CostExplorer: 0.5, AwsriSavings: 300, CostExplorerSavings: 100},
+	}
+	if len(report.Lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d: %+v", len(expected), len(report.Lines), report.Lines)
+	}
+	for i, line := range report.Lines {
+		want := expected[i]
+		if line.Type != want.Type || line.Service != want.Service || line.InstanceType != want.InstanceType || line.Region != want.Region ||
+			math.Abs(line.Awsri-want.Awsri) > 1e-9 || math.Abs(line.CostExplorer-want.CostExplorer) > 1e-9 ||
+			math.Abs(line.AwsriSavings-want.AwsriSavings) > 1e-9 || math.Abs(line.CostExplorerSavings-want.CostExplorerSavings) > 1e-9 {
+			t.Errorf("line %d: expected %+v, got %+v", i, want, line)
+		}
+		if len(line.Reasons) == 0 {
+			t.Errorf("line %d: expected reasons for the difference", i)
+		}
+	}
+}
+
+func TestRiDifferenceReasons(t *testing.T) {
+	recommendation := &ceRIRecommendation{count: 2, averageUsed: 2.5, minimumUsed: 2, maximumUsed: 3, averageUtilization: 100, monthlySavings: 100}
+
+	// 台数も1台あたりの節約額も同じ場合は理由はない
+	if reasons := riDifferenceReasons(2, awsriEstimate{quantity: 2, monthlySavings: 100}, recommendation, 30); len(reasons) != 0 {
+		t.Errorf("expected no reasons, got %v", reasons)
+	}
+	// 平均の利用が台数以上でも少なく推奨されるのは利用率を保つため
+	if reasons := riDifferenceReasons(3, awsriEstimate{quantity: 3, monthlySavings: 150}, &ceRIRecommendation{count: 2, averageUsed: 3, minimumUsed: 2, monthlySavings: 100}, 30); len(reasons) != 1 {
+		t.Errorf("expected one reason, got %v", reasons)
+	}
+	// 利用率100%未満を前提とした節約額
+	lowUtilization := *recommendation
+	lowUtilization.averageUtilization = 80
+	lowUtilization.monthlySavings = 60
+	if reasons := riDifferenceReasons(2, awsriEstimate{quantity: 2, monthlySavings: 100}, &lowUtilization, 30); len(reasons) != 1 {
+		t.Errorf("expected one reason, got %v", reasons)
+	}
+}
@@ -0,0 +1,167 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	redshiftTypes "github.com/aws/aws-sdk-go-v2/service/redshift/types"
+)
+
+// redshiftServerlessNote はRedshift Serverlessがリザーブドノードの対象外であることを示す注記
+const redshiftServerlessNote = "Note: Redshift Serverless cannot use reserved nodes. Reserved nodes only apply to provisioned RA3 and DC2 clusters."
+
+// redshiftReservableNodeFamilies はリザーブドノードを購入できるノードファミリー
+var redshiftReservableNodeFamilies = []string{"ra3.", "dc2."}
+
+type RedshiftOption struct {
+	NodeType string `required:"" help:"Node type (RA3 or DC2, e.g. ra3.xlplus)"`
+	Region   string `default:"ap-northeast-1" help:"AWS region"`
+}
+
+type RedshiftCommand struct {
+	opts RedshiftOption
+}
+
+func NewRedshiftCommand(opts RedshiftOption) *RedshiftCommand {
+	return &RedshiftCommand{opts: opts}
+}
+
+func (c *RedshiftCommand) Run(ctx context.Context) error {
+	if !isRedshiftReservable(c.opts.NodeType) {
+		return fmt.Errorf("unsupported node type: %s (reserved nodes are available for RA3 and DC2 node types)", c.opts.NodeType)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(c.opts.Region))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config, %v", err)
+	}
+
+	// オンデマンド料金をAPI経由で取得
+	onDemandPrice, err := c.getRedshiftOnDemandPrice(cfg, c.opts.NodeType)
+	if err != nil {
+		return fmt.Errorf("failed to get on-demand price: %v", err)
+	}
+
+	// オファリングはノードタイプで絞り込めないため、まとめて取得してから絞り込む
+	offerings, err := describeRedshiftOfferings(ctx, redshift.NewFromConfig(cfg), c.opts.NodeType)
+	if err != nil {
+		return err
+	}
+
+	if err := RenderReservedPriceTable(onDemandPrice, ReservedOfferingTypes(), func(duration int, offeringType string) (float64, float64, bool, error) {
+		offering := findRedshiftOffering(offerings, duration, offeringType)
+		if offering == nil {
+			return 0, 0, false, nil
+		}
+		fixedPrice, monthlyRecurring := redshiftOfferingCharges(*offering)
+		return fixedPrice, monthlyRecurring, true, nil
+	}); err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Println(redshiftServerlessNote)
+	return nil
+}
+
+func (c *RedshiftCommand) getRedshiftOnDemandPrice(cfg aws.Config, nodeType string) (float64, error) {
+	svc, region := newPricingClient(cfg)
+
+	// Redshiftのオンデマンド料金を取得
+	filters := []types.Filter{
+		{
+			Field: aws.String("instanceType"),
+			Value: aws.String(nodeType),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			Field: aws.String("productFamily"),
+			Value: aws.String("Compute Instance"),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			Field: aws.String("regionCode"),
+			Value: aws.String(region),
+			Type:  types.FilterTypeTermMatch,
+		},
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonRedshift"),
+		Filters:     filters,
+	}
+
+	result, err := svc.GetProducts(context.TODO(), input)
+	if err != nil {
+		return 0, err
+	}
+
+	return extractPriceFromResult(result)
+}
+
+// isRedshiftReservable はリザーブドノードを購入できるノードタイプかどうかを返す
+func isRedshiftReservable(nodeType string) bool {
+	for _, family := range redshiftReservableNodeFamilies {
+		if strings.HasPrefix(nodeType, family) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeRedshiftOfferings は指定したノードタイプのリザーブドノードのオファリングを取得する
+func describeRedshiftOfferings(ctx context.Context, client redshift.DescribeReservedNodeOfferingsAPIClient, nodeType string) ([]redshiftTypes.ReservedNodeOffering, error) {
+	var offerings []redshiftTypes.ReservedNodeOffering
+	paginator := redshift.NewDescribeReservedNodeOfferingsPaginator(client, &redshift.DescribeReservedNodeOfferingsInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, offering := range result.ReservedNodeOfferings {
+			if aws.ToString(offering.NodeType) == nodeType {
+				offerings = append(offerings, offering)
+			}
+		}
+	}
+	return offerings, nil
+}
+
+// findRedshiftOffering は期間（年）とオファリングタイプに一致するオファリングを返す
+// 通常のオファリングを優先し、なければアップグレード可能なオファリングを返す
+func findRedshiftOffering(offerings []redshiftTypes.ReservedNodeOffering, duration int, offeringType string) *redshiftTypes.ReservedNodeOffering {
+	// Redshiftのオファリングの期間は秒単位
+	durationSeconds := int32(duration * 365 * 24 * 60 * 60)
+
+	var found *redshiftTypes.ReservedNodeOffering
+	for i, offering := range offerings {
+		if aws.ToInt32(offering.Duration) != durationSeconds || aws.ToString(offering.OfferingType) != offeringType {
+			continue
+		}
+		if offering.ReservedNodeOfferingType == redshiftTypes.ReservedNodeOfferingTypeRegular {
+			return &offerings[i]
+		}
+		if found == nil {
+			found = &offerings[i]
+		}
+	}
+	return found
+}
+
+// redshiftOfferingCharges はオファリングの前払い料金と月額料金を返す
+func redshiftOfferingCharges(offering redshiftTypes.ReservedNodeOffering) (float64, float64) {
+	// 時間単位の料金はRecurringChargesに含まれる（含まれない場合はUsagePriceを使用）
+	hourly := 0.0
+	for _, charge := range offering.RecurringCharges {
+		hourly += aws.ToFloat64(charge.RecurringChargeAmount)
+	}
+	if len(offering.RecurringCharges) == 0 {
+		hourly = aws.ToFloat64(offering.UsagePrice)
+	}
+	return aws.ToFloat64(offering.FixedPrice), hourly * 24 * 30
+}
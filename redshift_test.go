@@ -0,0 +1,51 @@
+package awsri
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	redshiftTypes "github.com/aws/aws-sdk-go-v2/service/redshift/types"
+)
+
+func TestFindRedshiftOffering(t *testing.T) {
+	oneYear := aws.Int32(365 * 24 * 60 * 60)
+	offerings := []redshiftTypes.ReservedNodeOffering{
+		{NodeType: aws.String("ra3.xlplus"), Duration: oneYear, OfferingType: aws.String("All Upfront"), FixedPrice: aws.Float64(5000),
+			ReservedNodeOfferingType: redshiftTypes.ReservedNodeOfferingTypeUpgradable},
+		{NodeType: aws.String("ra3.xlplus"), Duration: oneYear, OfferingType: aws.String("All Upfront"), FixedPrice: aws.Float64(4500),
+			ReservedNodeOfferingType: redshiftTypes.ReservedNodeOfferingTypeRegular},
+		{NodeType: aws.String("ra3.xlplus"), Duration: oneYear, OfferingType: aws.String("No Upfront"), UsagePrice: aws.Float64(0.5),
+			ReservedNodeOfferingType: redshiftTypes.ReservedNodeOfferingTypeUpgradable},
+	}
+
+	offering := findRedshiftOffering(offerings, 1, "All Upfront")
+	if offering == nil || aws.ToFloat64(offering.FixedPrice) != 4500 {
+		t.Errorf("Expected the regular All Upfront offering, got %+v", offering)
+	}
+
+	// 通常のオファリングがない場合はアップグレード可能なオファリングを使用する
+	offering = findRedshiftOffering(offerings, 1, "No Upfront")
+	if offering == nil {
+		t.Fatal("Expected the upgradable No Upfront offering")
+	}
+	if fixedPrice, monthly := redshiftOfferingCharges(*offering); fixedPrice != 0 || monthly != 360 {
+		t.Errorf("Unexpected charges: upfront=%v monthly=%v", fixedPrice, monthly)
+	}
+
+	if offering := findRedshiftOffering(offerings, 3, "All Upfront"); offering != nil {
+		t.Errorf("Expected no 3y offering, got %+v", offering)
+	}
+}
+
+func TestFormatArgsOutputRedshift(t *testing.T) {
+	cmd := NewGenerateCommand(GenerateOption{})
+	instances := []InstanceInfo{
+		{ServiceType: "redshift", InstanceType: "ra3.4xlarge", Count: 4, Region: "us-east-1"},
+		{ServiceType: "redshift", InstanceType: "ds2.xlarge", Count: 2, Region: "us-east-1"},
+	}
+
+	expected := "--redshift=ra3.4xlarge:4:us-east-1"
+	if got := cmd.formatArgsOutput(instances); got != expected {
+		t.Errorf("Expected: %s, Got: %s", expected, got)
+	}
+}
@@ -0,0 +1,190 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// Reservation はアカウントで見つかった有効なRI（リザーブドノードを含む）を表す構造体
+type Reservation struct {
+	ServiceType  string // "rds", "elasticache", "docdb", "neptune"
+	InstanceType string // "db.m5.large", "cache.m5.large"
+	Count        int
+	Description  string // RIのproductDescription
+	MultiAz      bool
+	Region       string
+	Account      string
+}
+
+// getReservations はアカウントの有効なRIを取得する
+func (c *GenerateCommand) getReservations(ctx context.Context, cfg aws.Config) ([]Reservation, error) {
+	var reservations []Reservation
+
+	rdsReservations, err := c.getRDSReservations(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RDS reservations: %w", err)
+	}
+	reservations = append(reservations, rdsReservations...)
+
+	elasticacheReservations, err := c.getElastiCacheReservations(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ElastiCache reservations: %w", err)
+	}
+	reservations = append(reservations, elasticacheReservations...)
+
+	return reservations, nil
+}
+
+// getRDSReservations は有効なRDSのリザーブドインスタンスを取得する
+func (c *GenerateCommand) getRDSReservations(ctx context.Context, cfg aws.Config) ([]Reservation, error) {
+	svc := rds.NewFromConfig(cfg)
+
+	var reservations []Reservation
+	paginator := rds.NewDescribeReservedDBInstancesPaginator(svc, &rds.DescribeReservedDBInstancesInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, ri := range result.ReservedDBInstances {
+			if aws.ToString(ri.State) != "active" {
+				continue
+			}
+			// DocumentDBとNeptuneのRIもRDSのAPIで購入される
+			serviceType := "rds"
+			if engine, ok := rdsCompatibleEngines[strings.ToLower(aws.ToString(ri.ProductDescription))]; ok {
+				serviceType = engine.serviceType
+			}
+			reservations = append(reservations, Reservation{
+				ServiceType:  serviceType,
+				InstanceType: aws.ToString(ri.DBInstanceClass),
+				Count:        int(aws.ToInt32(ri.DBInstanceCount)),
+				Description:  aws.ToString(ri.ProductDescription),
+				MultiAz:      aws.ToBool(ri.MultiAZ),
+			})
+		}
+	}
+
+	return reservations, nil
+}
+
+// getElastiCacheReservations は有効なElastiCacheのリザーブドノードを取得する
+func (c *GenerateCommand) getElastiCacheReservations(ctx context.Context, cfg aws.Config) ([]Reservation, error) {
+	svc := elasticache.NewFromConfig(cfg)
+
+	var reservations []Reservation
+	paginator := elasticache.NewDescribeReservedCacheNodesPaginator(svc, &elasticache.DescribeReservedCacheNodesInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range result.ReservedCacheNodes {
+			if aws.ToString(node.State) != "active" {
+				continue
+			}
+			reservations = append(reservations, Reservation{
+				ServiceType:  "elasticache",
+				InstanceType: aws.ToString(node.CacheNodeType),
+				Count:        int(aws.ToInt32(node.CacheNodeCount)),
+				Description:  aws.ToString(node.ProductDescription),
+			})
+		}
+	}
+
+	return reservations, nil
+}
+
+// reservationPoolKey はRIまたはインスタンスが属するプールのキーと、プールでの大きさを返す
+// サイズフレキシブルなRDSのRIとRedis/Valkeyのリザーブドノードはファミリーごとに正規化ユニットで、
+// それ以外はインスタンスタイプごとに台数でまとめる
+// RIは一括請求のファミリーのアカウント間で共有されるため、アカウントはキーに含めない
+func reservationPoolKey(serviceType, instanceType, description string, multiAz bool, region string) (string, float64) {
+	if family, units, engine, ok := normalizationUnits(serviceType, instanceType, description, multiAz); ok {
+		return fmt.Sprintf("%s|%s|%s|%s", serviceType, region, engine, family), units
+	}
+	switch serviceType {
+	case "rds":
+		return fmt.Sprintf("rds|%s|%s|%s|%t", region, normalizeRDSEngine(description), instanceType, multiAz), 1
+	case "elasticache":
+		return fmt.Sprintf("elasticache|%s|%s|%s", region, normalizeCacheEngine(description), instanceType), 1
+	default:
+		return fmt.Sprintf("%s|%s|%s|%s", serviceType, region, description, instanceType), 1
+	}
+}
+
+// normalizeCacheEngine はElastiCacheのエンジンやproductDescriptionを共通の形式に変換する
+// Redis OSSのリザーブドノードはValkeyのノードにも適用されるため、同じプールにまとめる
+func normalizeCacheEngine(engine string) string {
+	engine = strings.ToLower(strings.TrimSpace(engine))
+	if engine == "valkey" {
+		return "redis"
+	}
+	return engine
+}
+
+// subtractReservations は有効なRIで賄われる需要を差し引き、賄われない行を返す
+// 残りが整数の台数になるように、プール内では大きいインスタンスから割り当てる
+func subtractReservations(instances []InstanceInfo, reservations []Reservation) []InstanceInfo {
+	pools := make(map[string]float64)
+	for _, reservation := range reservations {
+		key, size := reservationPoolKey(reservation.ServiceType, reservation.InstanceType, reservation.Description, reservation.MultiAz, reservation.Region)
+		pools[key] += size * float64(reservation.Count)
+	}
+
+	// 大きいインスタンスから割り当てる
+	order := make([]int, len(instances))
+	sizes := make([]float64, len(instances))
+	keys := make([]string, len(instances))
+	for i, instance := range instances {
+		order[i] = i
+		keys[i], sizes[i] = reservationPoolKey(instance.ServiceType, instance.InstanceType, instance.Description, instance.MultiAz, instance.Region)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sizes[order[a]] > sizes[order[b]]
+	})
+
+	uncovered := make([]InstanceInfo, len(instances))
+	copy(uncovered, instances)
+	for _, i := range order {
+		available := pools[keys[i]]
+		if available <= 0 {
+			continue
+		}
+		covered := int(available / sizes[i])
+		if covered > uncovered[i].Count {
+			covered = uncovered[i].Count
+		}
+		if covered == 0 {
+			continue
+		}
+		pools[keys[i]] -= float64(covered) * sizes[i]
+		uncovered[i].Count -= covered
+		// アカウントごとの内訳は賄われない台数と一致しなくなるため削除する
+		uncovered[i].Accounts = nil
+	}
+
+	// 残りのインスタンスを1台分賄えない端数のユニットを表示する
+	for i, instance := range uncovered {
+		if instance.Count > 0 && pools[keys[i]] > 0 {
+			fmt.Fprintf(os.Stderr, "Note: %.1f unused reserved units partially cover %s %s in %s\n",
+				pools[keys[i]], instance.ServiceType, instance.InstanceType, instance.Region)
+			pools[keys[i]] = 0
+		}
+	}
+
+	var result []InstanceInfo
+	for _, instance := range uncovered {
+		if instance.Count > 0 {
+			result = append(result, instance)
+		}
+	}
+	return result
+}
@@ -0,0 +1,155 @@
+package awsri
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubtractReservations(t *testing.T) {
+	instances := []InstanceInfo{
+		{ServiceType: "rds", InstanceType: "db.r6g.2xlarge", Count: 2, Description: "postgres", Region: "ap-northeast-1"},
+		{ServiceType: "rds", InstanceType: "db.r6g.large", Count: 3, Description: "postgres", Region: "ap-northeast-1"},
+		{ServiceType: "rds", InstanceType: "db.r6g.large", Count: 1, Description: "postgres", MultiAz: true, Region: "us-east-1"},
+		{ServiceType: "rds", InstanceType: "db.m5.xlarge", Count: 2, Description: "sqlserver-se", Region: "ap-northeast-1"},
+		{ServiceType: "elasticache", InstanceType: "cache.r6g.large", Count: 4, Description: "valkey", Region: "ap-northeast-1"},
+	}
+	reservations := []Reservation{
+		// 8 x large = 32 units, which covers the two 2xlarge instances
+		{ServiceType: "rds", InstanceType: "db.r6g.large", Count: 8, Description: "postgresql", Region: "ap-northeast-1", Account: "111111111111"},
+		// Single-AZ xlarge = 8 units, which covers one Multi-AZ large
+		{ServiceType: "rds", InstanceType: "db.r6g.xlarge", Count: 1, Description: "postgresql", Region: "us-east-1", Account: "222222222222"},
+		// SQL Server is not size-flexible, so a large reservation does not cover xlarge instances
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 4, Description: "sqlserver-se(li)", Region: "ap-northeast-1"},
+		{ServiceType: "elasticache", InstanceType: "cache.r6g.large", Count: 3, Description: "redis", Region: "ap-northeast-1"},
+	}
+
+	got := subtractReservations(instances, reservations)
+	expected := []InstanceInfo{
+		{ServiceType: "rds", InstanceType: "db.r6g.large", Count: 3, Description: "postgres", Region: "ap-northeast-1"},
+		{ServiceType: "rds", InstanceType: "db.m5.xlarge", Count: 2, Description: "sqlserver-se", Region: "ap-northeast-1"},
+		{ServiceType: "elasticache", InstanceType: "cache.r6g.large", Count: 1, Description: "valkey", Region: "ap-northeast-1"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Uncovered instances mismatch.\nExpected: %+v\nGot: %+v", expected, got)
+	}
+}
+
+func TestRDSNormalizationUnits(t *testing.T) {
+	tests := []struct {
+		instanceClass string
+		multiAz       bool
+		family        string
+		units         float64
+	}{
+		{"db.t4g.nano", false, "t4g", 0.25},
+		{"db.t4g.micro", false, "t4g", 0.5},
+		{"db.r6g.large", false, "r6g", 4},
+		{"db.r6g.2xlarge", false, "r6g", 16},
+		{"db.x2iedn.24xlarge", false, "x2iedn", 192},
+		{"db.m5.xlarge", true, "m5", 16},
+	}
+	for _, tt := range tests {
+		family, units, ok := rdsNormalizationUnits(tt.instanceClass, tt.multiAz)
+		if !ok || family != tt.family || units != tt.units {
+			t.Errorf("rdsNormalizationUnits(%s, %t) = %s, %v, %t; expected %s, %v", tt.instanceClass, tt.multiAz, family, units, ok, tt.family, tt.units)
+		}
+	}
+}
+
+func TestNormalizeFleet(t *testing.T) {
+	instances := []InstanceInfo{
+		{ServiceType: "rds", InstanceType: "db.r6g.2xlarge", Count: 3, Description: "postgresql"},
+		{ServiceType: "rds", InstanceType: "db.r6g.xlarge", Count: 1, Description: "postgres", MultiAz: true},
+		{ServiceType: "rds", InstanceType: "db.r6g.xlarge", Count: 2, Description: "postgresql", Region: "us-east-1"},
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 2, Description: "oracle-se2(byol)"},
+		{ServiceType: "rds", InstanceType: "db.m5.large", Count: 2, Description: "sqlserver-se(li)"},
+		{ServiceType: "elasticache", InstanceType: "cache.r6g.2xlarge", Count: 1, Description: "redis"},
+		{ServiceType: "elasticache", InstanceType: "cache.r6g.large", Count: 2, Description: "valkey"},
+		{ServiceType: "elasticache", InstanceType: "cache.r6g.large", Count: 1, Description: "memcached"},
+	}
+
+	pools, rest := normalizeFleet(instances, "ap-northeast-1")
+	if len(pools) != 4 {
+		t.Fatalf("Expected 4 pools, got %d", len(pools))
+	}
+
+	// 3 x 2xlarge (48) + Multi-AZ xlarge (16) = 64 units, bought as 16 x large
+	pool := pools[0]
+	if pool.region != "ap-northeast-1" || pool.engine != "postgresql" || pool.family != "r6g" || pool.units != 64 || pool.smallestSize != "xlarge" {
+		t.Errorf("Unexpected pool %+v", pool)
+	}
+	if expected := []string{"nano", "micro", "small", "medium", "large", "xlarge"}; !reflect.DeepEqual(pool.candidateSizes(), expected) {
+		t.Errorf("Expected candidate sizes %v, got %v", expected, pool.candidateSizes())
+	}
+	if count := pool.count("large", pool.units); count != 16 {
+		t.Errorf("Expected 16 x large, got %d", count)
+	}
+	if instanceType := pool.instanceType("large"); instanceType != "db.r6g.large" {
+		t.Errorf("Expected db.r6g.large, got %s", instanceType)
+	}
+
+	if pools[1].region != "us-east-1" || pools[1].units != 16 {
+		t.Errorf("Unexpected us-east-1 pool %+v", pools[1])
+	}
+	if pools[2].engine != "oracle-se2" || pools[2].units != 8 {
+		t.Errorf("Unexpected Oracle BYOL pool %+v", pools[2])
+	}
+
+	// Redis and Valkey nodes share one pool: 2xlarge (16) + 2 x large (8) = 24 units
+	cache := pools[3]
+	if cache.serviceType != "elasticache" || cache.engine != "redis" || cache.units != 24 || cache.smallestSize != "large" {
+		t.Errorf("Unexpected ElastiCache pool %+v", cache)
+	}
+	if instanceType := cache.instanceType("large"); instanceType != "cache.r6g.large" {
+		t.Errorf("Expected cache.r6g.large, got %s", instanceType)
+	}
+
+	// Smallest size with an offering is picked
+	size, err := smallestOfferingSize(cache, func(instanceType string) (bool, error) {
+		return instanceType == "cache.r6g.large", nil
+	})
+	if err != nil || size != "large" {
+		t.Errorf("Expected large, got %s (%v)", size, err)
+	}
+
+	expectedRest := []InstanceInfo{instances[4], instances[7]}
+	if !reflect.DeepEqual(rest, expectedRest) {
+		t.Errorf("Expected the lines that are not size-flexible to be kept.\nExpected: %+v\nGot: %+v", expectedRest, rest)
+	}
+}
+
+func TestSubtractReservationsAfterCacheResize(t *testing.T) {
+	// The nodes were resized from 4 x large to one 2xlarge; the large reserved nodes still cover it
+	instances := []InstanceInfo{
+		{ServiceType: "elasticache", InstanceType: "cache.r6g.2xlarge", Count: 1, Description: "redis", Region: "ap-northeast-1"},
+		{ServiceType: "elasticache", InstanceType: "cache.r6g.large", Count: 1, Description: "redis", Region: "ap-northeast-1"},
+	}
+	reservations := []Reservation{
+		{ServiceType: "elasticache", InstanceType: "cache.r6g.large", Count: 4, Description: "redis", Region: "ap-northeast-1"},
+	}
+
+	got := subtractReservations(instances, reservations)
+	expected := []InstanceInfo{instances[1]}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Uncovered instances mismatch.\nExpected: %+v\nGot: %+v", expected, got)
+	}
+}
+
+func TestSubtractReservationsNano(t *testing.T) {
+	// db.t4g is size-flexible: one small (1 unit) covers one micro and two nano instances
+	instances := []InstanceInfo{
+		{ServiceType: "rds", InstanceType: "db.t4g.micro", Count: 1, Description: "mysql", Region: "ap-northeast-1"},
+		{ServiceType: "rds", InstanceType: "db.t4g.nano", Count: 3, Description: "mysql", Region: "ap-northeast-1"},
+	}
+	reservations := []Reservation{
+		{ServiceType: "rds", InstanceType: "db.t4g.small", Count: 1, Description: "mysql", Region: "ap-northeast-1"},
+	}
+
+	got := subtractReservations(instances, reservations)
+	expected := []InstanceInfo{
+		{ServiceType: "rds", InstanceType: "db.t4g.nano", Count: 1, Description: "mysql", Region: "ap-northeast-1"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Uncovered instances mismatch.\nExpected: %+v\nGot: %+v", expected, got)
+	}
+}
@@ -0,0 +1,247 @@
+package awsri
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans"
+	savingsplansTypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+)
+
+// sageMakerUsageCodes maps the component of a SageMaker instance to the usage type code
+// used in the price list and Savings Plan rates (e.g. APN1-Host:ml.m5.large)
+var sageMakerUsageCodes = map[string]string{
+	"training":  "Train",
+	"inference": "Host",
+	"notebook":  "Notebk",
+}
+
+type SageMakerSavingsPlansOption struct {
+	Region        string   `name:"region" default:"ap-northeast-1" help:"AWS region"`
+	Instances     []string `name:"instance" required:"" help:"SageMaker instance usage in format: training|inference|notebook:instance-type:hours-per-month (repeatable)"`
+	Duration      int      `name:"duration" default:"1" help:"Duration in years (1 or 3)"`
+	PaymentOption string   `name:"payment-option" default:"no-upfront" help:"Payment option (no-upfront, partial-upfront, all-upfront)"`
+	NoHeader      bool     `name:"no-header" help:"Do not output CSV header"`
+}
+
+type SageMakerSavingsPlansCommand struct {
+	opts SageMakerSavingsPlansOption
+}
+
+// sageMakerUsage is the monthly usage of a SageMaker instance type
+type sageMakerUsage struct {
+	component     string
+	instanceType  string
+	hoursPerMonth float64
+}
+
+func NewSageMakerSavingsPlansCommand(opts SageMakerSavingsPlansOption) *SageMakerSavingsPlansCommand {
+	return &SageMakerSavingsPlansCommand{opts: opts}
+}
+
+func (c *SageMakerSavingsPlansCommand) Run(ctx context.Context) error {
+	// Validate duration (must be 1 or 3 years)
+	if c.opts.Duration != 1 && c.opts.Duration != 3 {
+		return fmt.Errorf("duration must be 1 or 3 years, got: %d", c.opts.Duration)
+	}
+
+	usages, err := parseSageMakerUsages(c.opts.Instances)
+	if err != nil {
+		return err
+	}
+
+	// Convert lowercase hyphenated value to the format expected by AWS API
+	awsPaymentOption, err := convertPaymentOptionToAWSFormat(c.opts.PaymentOption)
+	if err != nil {
+		return err
+	}
+
+	// Pricing API and Savings Plans API are only available in us-east-1
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config: %v", err)
+	}
+
+	currentCostPerMonth := 0.0
+	spCostPerMonth := 0.0
+	for _, usage := range usages {
+		// Get on-demand pricing
+		onDemandPrice, err := c.getSageMakerOnDemandPrice(ctx, cfg, usage)
+		if err != nil {
+			return fmt.Errorf("failed to get on-demand price for %s %s: %v", usage.component, usage.instanceType, err)
+		}
+
+		// Get Savings Plan pricing
+		spPrice, err := c.getSageMakerSavingsPlanPrice(ctx, cfg, usage, savingsplansTypes.SavingsPlanPaymentOption(awsPaymentOption))
+		if err != nil {
+			return fmt.Errorf("failed to get Savings Plan price for %s %s: %v", usage.component, usage.instanceType, err)
+		}
+
+		currentCostPerMonth += usage.hoursPerMonth * onDemandPrice
+		spCostPerMonth += usage.hoursPerMonth * spPrice
+	}
+
+	// Hourly commitment = hourly cost after applying Savings Plan
+	hoursPerMonth := 720.0
+	hourlyCommitment := spCostPerMonth / hoursPerMonth
+
+	// SP/RI purchase amount (USD) = Hourly commitment × 720 hours × 12 months × duration (years)
+	spPurchaseAmount := hourlyCommitment * hoursPerMonth * 12.0 * float64(c.opts.Duration)
+
+	// Calculate savings amount and savings rate
+	savingsAmount := currentCostPerMonth - spCostPerMonth
+	savingsRate := (savingsAmount / currentCostPerMonth) * 100.0
+
+	// Output CSV
+	renderCSV(hourlyCommitment, spPurchaseAmount, currentCostPerMonth, spCostPerMonth, savingsAmount, savingsRate, c.opts.NoHeader)
+
+	return nil
+}
+
+// parseSageMakerUsages parses instance usages in format component:instance-type:hours-per-month
+func parseSageMakerUsages(defs []string) ([]sageMakerUsage, error) {
+	var usages []sageMakerUsage
+	for _, def := range defs {
+		parts := strings.Split(def, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid instance format: %s, expected format: training|inference|notebook:instance-type:hours-per-month", def)
+		}
+		if _, ok := sageMakerUsageCodes[parts[0]]; !ok {
+			return nil, fmt.Errorf("invalid component: %s (must be training, inference or notebook)", parts[0])
+		}
+		hours, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || hours <= 0 {
+			return nil, fmt.Errorf("invalid hours per month: %s", parts[2])
+		}
+		instanceType := parts[1]
+		if !strings.HasPrefix(instanceType, "ml.") {
+			instanceType = "ml." + instanceType
+		}
+		usages = append(usages, sageMakerUsage{component: parts[0], instanceType: instanceType, hoursPerMonth: hours})
+	}
+	return usages, nil
+}
+
+// sageMakerUsageTypeMatches reports whether the usage type (e.g. APN1-Host:ml.m5.large) is
+// for the component and instance type
+func sageMakerUsageTypeMatches(usageType string, usage sageMakerUsage) bool {
+	name, instanceType, ok := strings.Cut(usageType, ":")
+	if !ok || instanceType != usage.instanceType {
+		return false
+	}
+	code := sageMakerUsageCodes[usage.component]
+	return name == code || strings.HasSuffix(name, "-"+code)
+}
+
+// getSageMakerOnDemandPrice retrieves the hourly on-demand price using the Pricing API
+func (c *SageMakerSavingsPlansCommand) getSageMakerOnDemandPrice(ctx context.Context, cfg aws.Config, usage sageMakerUsage) (float64, error) {
+	svc := pricing.NewFromConfig(cfg)
+
+	filters := []types.Filter{
+		{
+			Field: aws.String("regionCode"),
+			Value: aws.String(c.opts.Region),
+			Type:  types.FilterTypeTermMatch,
+		},
+		{
+			Field: aws.String("instanceName"),
+			Value: aws.String(usage.instanceType),
+			Type:  types.FilterTypeTermMatch,
+		},
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonSageMaker"),
+		Filters:     filters,
+		MaxResults:  aws.Int32(100),
+	}
+
+	result, err := svc.GetProducts(ctx, input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get products: %v", err)
+	}
+
+	// The same instance type is listed for each component; pick the one by usage type
+	for _, priceListEntry := range result.PriceList {
+		product, err := parsePriceListProduct(priceListEntry)
+		if err != nil {
+			continue
+		}
+		if sageMakerUsageTypeMatches(product.Product.Attributes["usagetype"], usage) {
+			return extractOnDemandPriceFromResult(priceListEntry)
+		}
+	}
+
+	return 0, fmt.Errorf("no pricing information found in region %s", c.opts.Region)
+}
+
+// getSageMakerSavingsPlanPrice retrieves the hourly SageMaker Savings Plan rate using the Savings Plans API
+func (c *SageMakerSavingsPlansCommand) getSageMakerSavingsPlanPrice(ctx context.Context, cfg aws.Config, usage sageMakerUsage, paymentOption savingsplansTypes.SavingsPlanPaymentOption) (float64, error) {
+	svc := savingsplans.NewFromConfig(cfg)
+
+	input := &savingsplans.DescribeSavingsPlansOfferingRatesInput{
+		SavingsPlanTypes: []savingsplansTypes.SavingsPlanType{
+			savingsplansTypes.SavingsPlanTypeSagemaker,
+		},
+		Products: []savingsplansTypes.SavingsPlanProductType{
+			savingsplansTypes.SavingsPlanProductTypeSagemaker,
+		},
+		ServiceCodes: []savingsplansTypes.SavingsPlanRateServiceCode{
+			savingsplansTypes.SavingsPlanRateServiceCode("AmazonSageMaker"),
+		},
+		SavingsPlanPaymentOptions: []savingsplansTypes.SavingsPlanPaymentOption{
+			paymentOption,
+		},
+		Filters: []savingsplansTypes.SavingsPlanOfferingRateFilterElement{
+			{
+				Name: savingsplansTypes.SavingsPlanRateFilterAttributeRegion,
+				Values: []string{
+					c.opts.Region,
+				},
+			},
+			{
+				Name: savingsplansTypes.SavingsPlanRateFilterAttributeInstanceType,
+				Values: []string{
+					usage.instanceType,
+				},
+			},
+		},
+		MaxResults: 100,
+	}
+
+	result, err := svc.DescribeSavingsPlansOfferingRates(ctx, input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to describe savings plans offering rates: %v", err)
+	}
+
+	durationSeconds := int64(c.opts.Duration * 365 * 24 * 60 * 60) // Convert years to seconds
+	rate, ok := findSageMakerSavingsPlanRate(result.SearchResults, usage, durationSeconds)
+	if !ok {
+		return 0, fmt.Errorf("Savings Plan price not found with duration %d years", c.opts.Duration)
+	}
+	return rate, nil
+}
+
+// findSageMakerSavingsPlanRate returns the rate matching the usage and duration
+func findSageMakerSavingsPlanRate(searchResults []savingsplansTypes.SavingsPlanOfferingRate, usage sageMakerUsage, durationSeconds int64) (float64, bool) {
+	for _, offering := range searchResults {
+		// Check if duration matches
+		if offering.SavingsPlanOffering != nil && offering.SavingsPlanOffering.DurationSeconds != durationSeconds {
+			continue
+		}
+		if offering.UsageType == nil || offering.Rate == nil || !sageMakerUsageTypeMatches(*offering.UsageType, usage) {
+			continue
+		}
+		rate, err := strconv.ParseFloat(*offering.Rate, 64)
+		if err == nil {
+			return rate, true
+		}
+	}
+	return 0, false
+}
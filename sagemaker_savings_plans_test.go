@@ -0,0 +1,56 @@
+package awsri
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	savingsplansTypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+)
+
+func TestParseSageMakerUsages(t *testing.T) {
+	usages, err := parseSageMakerUsages([]string{"inference:ml.m5.large:2160", "training:g5.xlarge:40"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []sageMakerUsage{
+		{component: "inference", instanceType: "ml.m5.large", hoursPerMonth: 2160},
+		{component: "training", instanceType: "ml.g5.xlarge", hoursPerMonth: 40},
+	}
+	if len(usages) != len(expected) {
+		t.Fatalf("Expected %d usages, got %d", len(expected), len(usages))
+	}
+	for i := range expected {
+		if usages[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], usages[i])
+		}
+	}
+
+	for _, def := range []string{"batch:ml.m5.large:720", "inference:ml.m5.large", "inference:ml.m5.large:abc"} {
+		if _, err := parseSageMakerUsages([]string{def}); err == nil {
+			t.Errorf("Expected error for %s", def)
+		}
+	}
+}
+
+func TestFindSageMakerSavingsPlanRate(t *testing.T) {
+	oneYear := int64(365 * 24 * 60 * 60)
+	results := []savingsplansTypes.SavingsPlanOfferingRate{
+		{SavingsPlanOffering: &savingsplansTypes.ParentSavingsPlanOffering{DurationSeconds: oneYear},
+			UsageType: aws.String("APN1-Train:ml.m5.large"), Rate: aws.String("0.100")},
+		{SavingsPlanOffering: &savingsplansTypes.ParentSavingsPlanOffering{DurationSeconds: oneYear},
+			UsageType: aws.String("APN1-Host:ml.m5.large"), Rate: aws.String("0.090")},
+	}
+
+	usage := sageMakerUsage{component: "inference", instanceType: "ml.m5.large", hoursPerMonth: 720}
+	if rate, ok := findSageMakerSavingsPlanRate(results, usage, oneYear); !ok || rate != 0.09 {
+		t.Errorf("Expected the hosting rate 0.09, got %v (found=%v)", rate, ok)
+	}
+	if _, ok := findSageMakerSavingsPlanRate(results, usage, 3*oneYear); ok {
+		t.Error("Expected no 3y rate")
+	}
+
+	notebook := sageMakerUsage{component: "notebook", instanceType: "ml.m5.large", hoursPerMonth: 720}
+	if _, ok := findSageMakerSavingsPlanRate(results, notebook, oneYear); ok {
+		t.Error("Expected no notebook rate")
+	}
+}
@@ -0,0 +1,167 @@
+package awsri
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// scheduleMonthHours はawsriのすべての料金の基準となる1か月の時間数
+const scheduleMonthHours = 720.0
+
+// scheduleDays はスケジュールの曜日名から週内のインデックスへの対応
+var scheduleDays = map[string]int{"mon": 0, "tue": 1, "wed": 2, "thu": 3, "fri": 4, "sat": 5, "sun": 6}
+
+// ScheduleOption は予約とオンデマンドの利用を比較するコマンドに埋め込むオプション
+type ScheduleOption struct {
+	Schedule string `name:"schedule" help:"Running schedule as hours per month (e.g. 200 or 200/month) or weekly windows (e.g. 'mon-fri 09:00-18:00;sat 10:00-14:00'); on-demand costs are for these hours only (default: always running)"`
+}
+
+// scheduledHours はスケジュールの720時間の月あたりの稼働時間を返す
+func (o ScheduleOption) scheduledHours() (float64, error) {
+	if strings.TrimSpace(o.Schedule) == "" {
+		return scheduleMonthHours, nil
+	}
+	return parseSchedule(o.Schedule)
+}
+
+// scheduleNote はスケジュールの指定時に料金表の上に出力する行を返す（指定がなければ""）
+func (o ScheduleOption) scheduleNote(hours float64) string {
+	if strings.TrimSpace(o.Schedule) == "" {
+		return ""
+	}
+	return fmt.Sprintf("Schedule: %s (%.1f of %.0f hours/month); on-demand costs are for the scheduled hours and reservations are paid for every hour",
+		o.Schedule, hours, scheduleMonthHours)
+}
+
+// parseSchedule はスケジュールを720時間の月あたりの稼働時間に変換する
+// 数値（/monthを付けてもよい）はそのまま使う。それ以外は";"区切りの週単位の時間帯で、それぞれ"[曜日] HH:MM-HH:MM"の形式
+// 曜日は"*"（既定）、曜日名と範囲（例: mon-fri,sun）。終了が開始より前の時間帯は日付をまたぐ
+// 週あたりの稼働時間は168時間あたり720時間に換算する
+func parseSchedule(schedule string) (float64, error) {
+	schedule = strings.TrimSpace(schedule)
+	if hours, err := strconv.ParseFloat(strings.TrimSuffix(schedule, "/month"), 64); err == nil {
+		if hours <= 0 || hours > scheduleMonthHours {
+			return 0, fmt.Errorf("invalid schedule: %s (hours per month must be between 0 and %.0f)", schedule, scheduleMonthHours)
+		}
+		return hours, nil
+	}
+
+	// 重なる時間帯を一度だけ数えるため、週内の稼働している時間に印を付ける
+	var week [7 * 24 * 60]bool
+	for _, window := range strings.Split(schedule, ";") {
+		fields := strings.Fields(strings.ToLower(window))
+		if len(fields) == 0 {
+			continue
+		}
+		days := "*"
+		if len(fields) == 2 {
+			days = fields[0]
+		} else if len(fields) != 1 {
+			return 0, fmt.Errorf("invalid schedule window: %q (expected format: [days] HH:MM-HH:MM)", window)
+		}
+
+		dayIndexes, err := parseScheduleDays(days)
+		if err != nil {
+			return 0, err
+		}
+		start, end, err := parseScheduleTimes(fields[len(fields)-1])
+		if err != nil {
+			return 0, err
+		}
+		if end <= start {
+			end += 24 * 60
+		}
+		for _, day := range dayIndexes {
+			for minute := start; minute < end; minute++ {
+				week[(day*24*60+minute)%len(week)] = true
+			}
+		}
+	}
+
+	minutes := 0
+	for _, running := range week {
+		if running {
+			minutes++
+		}
+	}
+	if minutes == 0 {
+		return 0, fmt.Errorf("invalid schedule: %s (no running hours)", schedule)
+	}
+	return float64(minutes) / 60 / (7 * 24) * scheduleMonthHours, nil
+}
+
+// parseScheduleDays は"*"またはカンマ区切りの曜日と曜日の範囲（例: mon-fri,sun）を解析する
+func parseScheduleDays(days string) ([]int, error) {
+	if days == "*" {
+		return []int{0, 1, 2, 3, 4, 5, 6}, nil
+	}
+	var result []int
+	for _, part := range strings.Split(days, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			to = from
+		}
+		first, ok := scheduleDays[from]
+		if !ok {
+			return nil, fmt.Errorf("invalid day in schedule: %s (must be mon, tue, wed, thu, fri, sat or sun)", from)
+		}
+		last, ok := scheduleDays[to]
+		if !ok {
+			return nil, fmt.Errorf("invalid day in schedule: %s (must be mon, tue, wed, thu, fri, sat or sun)", to)
+		}
+		// 範囲は週をまたいでもよい（例: fri-mon）
+		for day := first; ; day = (day + 1) % 7 {
+			result = append(result, day)
+			if day == last {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// parseScheduleTimes は時間帯"HH[:MM]-HH[:MM]"を0時からの分に変換する
+func parseScheduleTimes(times string) (int, int, error) {
+	from, to, ok := strings.Cut(times, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid time range in schedule: %s (expected format: HH:MM-HH:MM)", times)
+	}
+	start, err := parseScheduleTime(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseScheduleTime(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("invalid time range in schedule: %s (start and end are the same)", times)
+	}
+	return start, end, nil
+}
+
+// parseScheduleTime は"HH[:MM]"（00:00から24:00）を0時からの分に変換する
+func parseScheduleTime(value string) (int, error) {
+	hourText, minuteText, hasMinutes := strings.Cut(value, ":")
+	hour, err := strconv.Atoi(hourText)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time in schedule: %s", value)
+	}
+	minute := 0
+	if hasMinutes {
+		minute, err = strconv.Atoi(minuteText)
+		if err != nil || minute < 0 || minute > 59 {
+			return 0, fmt.Errorf("invalid time in schedule: %s", value)
+		}
+	}
+	if hour < 0 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time in schedule: %s", value)
+	}
+	return hour*60 + minute, nil
+}
+
+// scheduledOnDemand は常時稼働の月額のオンデマンド料金を稼働時間に合わせて換算する
+func scheduledOnDemand(onDemandMonthly, hours float64) float64 {
+	return onDemandMonthly * hours / scheduleMonthHours
+}
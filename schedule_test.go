@@ -0,0 +1,86 @@
+package awsri
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		schedule string
+		expected float64
+	}{
+		{"200", 200},
+		{"200/month", 200},
+		{"720", 720},
+		// 5日間×9時間 = 週168時間のうち45時間
+		{"mon-fri 09:00-18:00", 45.0 / 168 * 720},
+		{"Mon-Fri 9-18", 45.0 / 168 * 720},
+		{"mon-fri 09:00-18:00;sat 10:00-14:00", 49.0 / 168 * 720},
+		// 重なる時間帯は一度だけ数える
+		{"mon-fri 09:00-18:00;mon 08:00-10:00", 46.0 / 168 * 720},
+		// 終了が開始より前の時間帯は日付をまたぎ、sunはmonに続く
+		{"22:00-06:00", 56.0 / 168 * 720},
+		{"sun 22:00-02:00", 4.0 / 168 * 720},
+		{"fri-mon 00:00-24:00", 96.0 / 168 * 720},
+		{"mon,wed 08:30-09:00", 1.0 / 168 * 720},
+	}
+	for _, tt := range tests {
+		hours, err := parseSchedule(tt.schedule)
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", tt.schedule, err)
+			continue
+		}
+		if math.Abs(hours-tt.expected) > 1e-9 {
+			t.Errorf("Expected %.4f hours for %s, got %.4f", tt.expected, tt.schedule, hours)
+		}
+	}
+
+	for _, schedule := range []string{
+		"0",
+		"800/month",
+		"weekdays 09:00-18:00",
+		"mon-fri 09:00",
+		"mon-fri 09:00-09:00",
+		"mon-fri 25:00-26:00",
+		"mon-fri 09:60-18:00",
+		"mon fri 09:00-18:00",
+		";",
+	} {
+		if _, err := parseSchedule(schedule); err == nil {
+			t.Errorf("Expected error for %s", schedule)
+		}
+	}
+}
+
+func TestScheduleOption(t *testing.T) {
+	hours, err := ScheduleOption{}.scheduledHours()
+	if err != nil || hours != 720 {
+		t.Errorf("Expected 720 hours without a schedule, got %v (%v)", hours, err)
+	}
+	if note := (ScheduleOption{}).scheduleNote(hours); note != "" {
+		t.Errorf("Expected no note without a schedule, got %s", note)
+	}
+
+	// 720時間のうち180時間の稼働ではオンデマンドは常時稼働の料金の4分の1になる
+	if price := scheduledOnDemand(100, 180); price != 25 {
+		t.Errorf("Expected 25, got %v", price)
+	}
+}
+
+func TestScheduledSavings(t *testing.T) {
+	// 予約するとスケジュールの時間だけオンデマンドで動かすより高くなる
+	savings, percent := scheduledSavings(InstancePriceResult{Yearly: 1200, OnDemandYearly: 1000, HasOnDemand: true})
+	if savings != -200 || percent != -20 {
+		t.Errorf("Expected -200 (-20%%), got %v (%v%%)", savings, percent)
+	}
+	savings, percent = scheduledSavings(InstancePriceResult{Yearly: 600, OnDemandYearly: 1000, HasOnDemand: true})
+	if savings != 400 || percent != 40 {
+		t.Errorf("Expected 400 (40%%), got %v (%v%%)", savings, percent)
+	}
+	// オンデマンドと比較していない行は節約額を表示しない
+	savings, percent = scheduledSavings(InstancePriceResult{Yearly: 600})
+	if savings != 0 || percent != 0 {
+		t.Errorf("Expected no savings, got %v (%v%%)", savings, percent)
+	}
+}
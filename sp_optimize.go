@@ -0,0 +1,250 @@
+package awsri
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// spOptimizeHeadings はコミットメントカーブの表の見出し
+var spOptimizeHeadings = []string{"Commitment (USD/h)", "Utilization", "Coverage", "Net Savings (USD/month)", "Savings Rate"}
+
+type SPOptimizeOption struct {
+	File                    string  `name:"file" required:"" help:"CSV file of hourly on-demand equivalent spend (the last column of each row is the spend in USD; a header row is skipped)"`
+	Discount                float64 `name:"discount" required:"" help:"Savings Plan discount in percent from on-demand (e.g. the Savings Rate of compute-savings-plans)"`
+	Steps                   int     `name:"steps" default:"10" help:"Number of commitment levels shown in the curve"`
+	ConservativeUtilization float64 `name:"conservative-utilization" default:"95" help:"Minimum utilization in percent of the conservative commitment"`
+	AggressiveSavings       float64 `name:"aggressive-savings" default:"90" help:"Share in percent of the maximum net savings kept by the aggressive commitment"`
+}
+
+type SPOptimizeCommand struct {
+	opts SPOptimizeOption
+}
+
+// spCommitmentResult は支出の時系列に対する時間あたりのコミットメントの結果
+type spCommitmentResult struct {
+	commitment  float64 // 時間あたりのコミットメント（USD）
+	utilization float64 // コミットメントのうち使われた割合（%）
+	coverage    float64 // オンデマンド換算の支出のうちカバーされた割合（%）
+	netSavings  float64 // 時系列全体での節約額（USD）
+	savingsRate float64 // オンデマンド換算の支出に対する純節約額の割合（%）
+}
+
+func NewSPOptimizeCommand(opts SPOptimizeOption) *SPOptimizeCommand {
+	return &SPOptimizeCommand{opts: opts}
+}
+
+func (c *SPOptimizeCommand) Run(ctx context.Context) error {
+	if c.opts.Discount <= 0 || c.opts.Discount >= 100 {
+		return fmt.Errorf("discount must be between 0 and 100 percent, got: %v", c.opts.Discount)
+	}
+	if c.opts.Steps <= 0 {
+		return fmt.Errorf("steps must be positive, got: %d", c.opts.Steps)
+	}
+
+	f, err := os.Open(c.opts.File)
+	if err != nil {
+		return fmt.Errorf("failed to open spend file: %w", err)
+	}
+	defer f.Close()
+
+	spend, err := readHourlySpend(f)
+	if err != nil {
+		return err
+	}
+
+	rate := 1 - c.opts.Discount/100
+	optimal := optimalCommitment(spend, rate)
+	if optimal.commitment == 0 {
+		return fmt.Errorf("no commitment level saves money with the spend in %s", c.opts.File)
+	}
+
+	// 時系列全体の節約額を720時間の月額に換算する
+	monthly := 720.0 / float64(len(spend))
+
+	table := NewTable(spOptimizeHeadings)
+	for _, result := range commitmentCurve(spend, rate, c.opts.Steps) {
+		table.Append(spCommitmentRow(result, monthly))
+	}
+	table.Render()
+
+	conservative := conservativeCommitment(spend, rate, c.opts.ConservativeUtilization, optimal.commitment)
+	aggressive := aggressiveCommitment(spend, rate, optimal.netSavings*c.opts.AggressiveSavings/100)
+
+	fmt.Println()
+	fmt.Printf("Hours: %d, On-demand equivalent spend: %.2f USD\n", len(spend), sumSpend(spend))
+	for _, recommendation := range []struct {
+		name   string
+		result spCommitmentResult
+	}{
+		{"Conservative", conservative},
+		{"Optimal", optimal},
+		{"Aggressive", aggressive},
+	} {
+		fmt.Printf("%-12s commitment: %.5f USD/h (utilization %.1f%%, coverage %.1f%%, net savings %.0f USD/month, %.1f%%)\n",
+			recommendation.name, recommendation.result.commitment, recommendation.result.utilization,
+			recommendation.result.coverage, recommendation.result.netSavings*monthly, recommendation.result.savingsRate)
+	}
+
+	return nil
+}
+
+// readHourlySpend はCSVから時間ごとのオンデマンド換算の支出を読み込む
+// 各行の最後の列を支出とし、数値でない最初の行はヘッダーとして扱う
+func readHourlySpend(r io.Reader) ([]float64, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spend CSV: %w", err)
+	}
+
+	var spend []float64
+	for i, record := range records {
+		if len(record) == 0 {
+			continue
+		}
+		value := strings.TrimSpace(record[len(record)-1])
+		amount, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			if i == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("invalid spend on line %d: %s", i+1, value)
+		}
+		if amount < 0 {
+			return nil, fmt.Errorf("negative spend on line %d: %s", i+1, value)
+		}
+		spend = append(spend, amount)
+	}
+	if len(spend) == 0 {
+		return nil, fmt.Errorf("no hourly spend found")
+	}
+	return spend, nil
+}
+
+// evaluateCommitment は時間あたりのコミットメントの結果を計算する
+// rateはオンデマンドに対するSavings Planの価格の比率（1 - 割引率）で、コミットメントは
+// 各時間でcommitment/rateまでのオンデマンド換算の支出をカバーする
+func evaluateCommitment(spend []float64, rate, commitment float64) spCommitmentResult {
+	result := spCommitmentResult{commitment: commitment}
+	total := sumSpend(spend)
+	if commitment <= 0 || total == 0 {
+		return result
+	}
+
+	limit := commitment / rate
+	covered := 0.0
+	for _, amount := range spend {
+		if amount < limit {
+			covered += amount
+		} else {
+			covered += limit
+		}
+	}
+	hours := float64(len(spend))
+
+	result.utilization = covered * rate / (commitment * hours) * 100
+	result.coverage = covered / total * 100
+	result.netSavings = covered - commitment*hours
+	result.savingsRate = result.netSavings / total * 100
+	return result
+}
+
+// candidateCommitments は純節約額のカーブの傾きが変わるコミットメント、つまりある時間の支出をちょうどカバーするコミットメントを返す
+// 最大値はこのいずれかになる
+func candidateCommitments(spend []float64, rate float64) []float64 {
+	sorted := make([]float64, len(spend))
+	copy(sorted, spend)
+	sort.Float64s(sorted)
+
+	var candidates []float64
+	for i, amount := range sorted {
+		if amount <= 0 || (i > 0 && amount == sorted[i-1]) {
+			continue
+		}
+		candidates = append(candidates, amount*rate)
+	}
+	return candidates
+}
+
+// optimalCommitment は純節約額が最大になるコミットメントを返す
+// 同じ節約額の場合は小さいコミットメントを選ぶ
+func optimalCommitment(spend []float64, rate float64) spCommitmentResult {
+	var best spCommitmentResult
+	for _, commitment := range candidateCommitments(spend, rate) {
+		result := evaluateCommitment(spend, rate, commitment)
+		if result.netSavings > best.netSavings+1e-9 {
+			best = result
+		}
+	}
+	return best
+}
+
+// conservativeCommitment はmaxCommitment以下で利用率がminUtilization%以上になる最大のコミットメントを返す
+func conservativeCommitment(spend []float64, rate, minUtilization, maxCommitment float64) spCommitmentResult {
+	var best spCommitmentResult
+	for _, commitment := range candidateCommitments(spend, rate) {
+		if commitment > maxCommitment {
+			break
+		}
+		result := evaluateCommitment(spend, rate, commitment)
+		if result.utilization+1e-9 >= minUtilization && result.commitment > best.commitment {
+			best = result
+		}
+	}
+	return best
+}
+
+// aggressiveCommitment は純節約額がminSavings以上になる最大のコミットメントを返す
+func aggressiveCommitment(spend []float64, rate, minSavings float64) spCommitmentResult {
+	var best spCommitmentResult
+	for _, commitment := range candidateCommitments(spend, rate) {
+		result := evaluateCommitment(spend, rate, commitment)
+		if result.netSavings+1e-9 >= minSavings && result.commitment > best.commitment {
+			best = result
+		}
+	}
+	return best
+}
+
+// commitmentCurve はゼロからピークの時間をカバーするコミットメントまで等間隔のコミットメントを評価する
+func commitmentCurve(spend []float64, rate float64, steps int) []spCommitmentResult {
+	peak := 0.0
+	for _, amount := range spend {
+		if amount > peak {
+			peak = amount
+		}
+	}
+
+	var curve []spCommitmentResult
+	for i := 1; i <= steps; i++ {
+		curve = append(curve, evaluateCommitment(spend, rate, peak*rate*float64(i)/float64(steps)))
+	}
+	return curve
+}
+
+// spCommitmentRow はコミットメントの結果を720時間あたりの節約額で表の行に整形する
+func spCommitmentRow(result spCommitmentResult, monthly float64) []string {
+	return []string{
+		fmt.Sprintf("%.5f", result.commitment),
+		fmt.Sprintf("%.1f%%", result.utilization),
+		fmt.Sprintf("%.1f%%", result.coverage),
+		fmt.Sprintf("%.0f", result.netSavings*monthly),
+		fmt.Sprintf("%.1f%%", result.savingsRate),
+	}
+}
+
+// sumSpend は時間ごとの支出の合計を返す
+func sumSpend(spend []float64) float64 {
+	total := 0.0
+	for _, amount := range spend {
+		total += amount
+	}
+	return total
+}
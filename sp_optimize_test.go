@@ -0,0 +1,56 @@
+package awsri
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadHourlySpend(t *testing.T) {
+	input := "time,spend\n2026-01-01T00:00:00Z,10\n2026-01-01T01:00:00Z, 12.5\n"
+	spend, err := readHourlySpend(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []float64{10, 12.5}; !reflect.DeepEqual(spend, expected) {
+		t.Errorf("Expected %v, got %v", expected, spend)
+	}
+
+	if _, err := readHourlySpend(strings.NewReader("10\nabc\n")); err == nil {
+		t.Error("Expected an error for a non-numeric spend")
+	}
+}
+
+func TestOptimalCommitment(t *testing.T) {
+	// オンデマンド換算で10 USDの時間が6時間、20 USDの時間が4時間、割引率は30%
+	spend := []float64{10, 10, 10, 10, 10, 10, 20, 20, 20, 20}
+	rate := 0.7
+
+	// 10 USD/hをカバーすると7 USD/hかかり、すべての時間で3 USD節約する
+	base := evaluateCommitment(spend, rate, 7)
+	if base.utilization != 100 || math.Abs(base.netSavings-30) > 1e-9 {
+		t.Errorf("Unexpected result at 7 USD/h: %+v", base)
+	}
+
+	// 20 USD/hをカバーすると支出の少ない6時間で4 USDずつ損をし、多い4時間で6 USDずつ節約する
+	peak := evaluateCommitment(spend, rate, 14)
+	if math.Abs(peak.netSavings-(-6*4+4*6)) > 1e-9 || peak.coverage != 100 {
+		t.Errorf("Unexpected result at 14 USD/h: %+v", peak)
+	}
+
+	if optimal := optimalCommitment(spend, rate); math.Abs(optimal.commitment-7) > 1e-9 {
+		t.Errorf("Expected the optimal commitment to be 7 USD/h, got %+v", optimal)
+	}
+	if conservative := conservativeCommitment(spend, rate, 95, 14); math.Abs(conservative.commitment-7) > 1e-9 {
+		t.Errorf("Expected the conservative commitment to be 7 USD/h, got %+v", conservative)
+	}
+	if aggressive := aggressiveCommitment(spend, rate, 0); math.Abs(aggressive.commitment-14) > 1e-9 {
+		t.Errorf("Expected the aggressive commitment to be 14 USD/h, got %+v", aggressive)
+	}
+
+	curve := commitmentCurve(spend, rate, 2)
+	if len(curve) != 2 || math.Abs(curve[0].commitment-7) > 1e-9 || math.Abs(curve[1].commitment-14) > 1e-9 {
+		t.Errorf("Unexpected curve %+v", curve)
+	}
+}
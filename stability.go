@@ -0,0 +1,137 @@
+package awsri
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// steadyStates は定常的に稼働しているとみなすリソースの状態
+// 一時的なメンテナンスの状態でもリソースは稼働し続けるため含める
+var steadyStates = map[string]map[string]bool{
+	"rds": {
+		"available":            true,
+		"backing-up":           true,
+		"maintenance":          true,
+		"modifying":            true,
+		"rebooting":            true,
+		"storage-optimization": true,
+		"upgrading":            true,
+	},
+	"elasticache": {
+		"available":               true,
+		"modifying":               true,
+		"rebooting cluster nodes": true,
+		"snapshotting":            true,
+	},
+	"opensearch": {
+		"active":     true,
+		"processing": true,
+	},
+	"redshift": {
+		"available":     true,
+		"modifying":     true,
+		"rebooting":     true,
+		"renaming":      true,
+		"resizing":      true,
+		"rotating-keys": true,
+		"updating-hsm":  true,
+	},
+	"memorydb": {
+		"available":    true,
+		"updating":     true,
+		"snapshotting": true,
+	},
+	"dynamodb": {
+		"ACTIVE":   true,
+		"UPDATING": true,
+	},
+	"docdb": {
+		"available":   true,
+		"backing-up":  true,
+		"maintenance": true,
+		"modifying":   true,
+		"rebooting":   true,
+		"upgrading":   true,
+	},
+	"neptune": {
+		"available":   true,
+		"backing-up":  true,
+		"maintenance": true,
+		"modifying":   true,
+		"rebooting":   true,
+		"upgrading":   true,
+	},
+	"ec2": {
+		"running": true,
+	},
+}
+
+// statusReasons は状態そのものよりわかりやすいスキップの理由
+var statusReasons = map[string]string{
+	"on-demand":   "on-demand capacity mode cannot use reserved capacity",
+	"standard-ia": "Standard-IA table class cannot use reserved capacity",
+	"serverless":  "serverless instances cannot use reserved instances",
+}
+
+// parseAge は90dや720hのような最小の経過時間を解析する
+// Goのdurationの形式に加えて、dを付けた日数を受け付ける
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age: %s, expected format: 90d or 720h", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age: %s, expected format: 90d or 720h", s)
+	}
+	return d, nil
+}
+
+// filterSteadyInstances はインスタンスを予約する価値のあるものとスキップするものに分ける
+// 定常的に稼働している状態以外のインスタンスと、作成からminAgeが経過していないインスタンスをスキップする
+// 状態や作成日時がわからない場合は除外しない
+// Fargateのタスクはデプロイのたびに入れ替わるため経過時間を確認しない
+func filterSteadyInstances(instances []InstanceInfo, minAge time.Duration, now time.Time) ([]InstanceInfo, []SkippedResource) {
+	var kept []InstanceInfo
+	var skipped []SkippedResource
+	for _, instance := range instances {
+		reason := ""
+		if states, ok := steadyStates[instance.ServiceType]; ok && instance.Status != "" && !states[instance.Status] {
+			reason = fmt.Sprintf("status is %s", instance.Status)
+			if r, ok := statusReasons[instance.Status]; ok {
+				reason = r
+			}
+		} else if minAge > 0 && instance.ServiceType != "fargate" && !instance.CreatedAt.IsZero() && now.Sub(instance.CreatedAt) < minAge {
+			reason = fmt.Sprintf("created %s, younger than %s", instance.CreatedAt.UTC().Format("2006-01-02"), formatAge(minAge))
+		}
+
+		if reason == "" {
+			kept = append(kept, instance)
+			continue
+		}
+		skipped = append(skipped, SkippedResource{
+			ServiceType:  instance.ServiceType,
+			ResourceID:   instance.ResourceID,
+			InstanceType: instance.InstanceType,
+			Region:       instance.Region,
+			Account:      instance.Account,
+			Reason:       reason,
+		})
+	}
+	return kept, skipped
+}
+
+// formatAge は経過時間が日数で割り切れる場合は日数で表示する
+func formatAge(d time.Duration) string {
+	day := 24 * time.Hour
+	if d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return d.String()
+}
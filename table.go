@@ -29,17 +29,21 @@ type TableRenderer struct {
 	table *tablewriter.Table
 }
 
-// NewTableRenderer creates a new TableRenderer
-func NewTableRenderer() *TableRenderer {
+// NewTable creates a table writing to stdout with the common style and the given headings
+func NewTable(headings []string) *tablewriter.Table {
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader(HEADINGS)
+	table.SetHeader(headings)
 	table.SetAutoFormatHeaders(false)
 	table.SetAutoWrapText(false)
 	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 	table.SetCenterSeparator("|")
+	return table
+}
 
+// NewTableRenderer creates a new TableRenderer
+func NewTableRenderer() *TableRenderer {
 	return &TableRenderer{
-		table: table,
+		table: NewTable(HEADINGS),
 	}
 }
 
@@ -66,13 +70,18 @@ func (t *TableRenderer) AppendReservedRow(
 	yearlySavings float64,
 	savingsPercent float64,
 ) {
+	savings := fmt.Sprintf("%.1f (%.1f%%)", yearlySavings, savingsPercent)
+	if yearlySavings < 0 {
+		// Reserving costs more than staying on-demand (e.g. with --schedule)
+		savings += " on-demand is cheaper"
+	}
 	t.table.Append([]string{
 		fmt.Sprintf("%dy", duration),
 		offeringType,
 		fmt.Sprintf("%.1f", fixedPrice),
 		fmt.Sprintf("%.1f", monthlyRecurring),
 		fmt.Sprintf("%.1f", effectiveYearly),
-		fmt.Sprintf("%.1f (%.1f%%)", yearlySavings, savingsPercent),
+		savings,
 	})
 }
 
@@ -147,6 +156,63 @@ func FormatDuration(years int) string {
 	return strconv.Itoa(years)
 }
 
+// ReservedOfferingTypes returns the offering types that are reservations (all but On-Demand)
+func ReservedOfferingTypes() []string {
+	var offeringTypes []string
+	for _, offeringType := range OfferingTypes {
+		if offeringType != "On-Demand" {
+			offeringTypes = append(offeringTypes, offeringType)
+		}
+	}
+	return offeringTypes
+}
+
+// ReservedChargesLookup returns the upfront and monthly recurring charges of the offering for a duration and label
+// ok is false when no such offering is sold
+type ReservedChargesLookup func(duration int, label string) (fixedPrice float64, monthlyRecurring float64, ok bool, err error)
+
+// RenderReservedPriceTable renders the on-demand row followed by a row per offering label for each duration
+func RenderReservedPriceTable(onDemandPrice float64, labels []string, lookup ReservedChargesLookup) error {
+	tableRenderer := NewTableRenderer()
+
+	for i, duration := range Durations {
+		durationMonths := DurationToMonths(duration)
+
+		tableRenderer.AppendOnDemandRow(duration, onDemandPrice)
+
+		for _, label := range labels {
+			fixedPrice, monthlyRecurring, ok, err := lookup(duration, label)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				tableRenderer.AppendNotAvailableRow(duration, label)
+				continue
+			}
+
+			effectiveYearly := CalculateEffectiveMonthly(fixedPrice, monthlyRecurring, durationMonths)
+			yearlySavings, savingsPercent := CalculateSavings(onDemandPrice, effectiveYearly)
+
+			tableRenderer.AppendReservedRow(
+				duration,
+				label,
+				fixedPrice,
+				monthlyRecurring,
+				effectiveYearly,
+				yearlySavings,
+				savingsPercent,
+			)
+		}
+
+		if i < len(Durations)-1 {
+			tableRenderer.AppendSeparator()
+		}
+	}
+
+	tableRenderer.Render()
+	return nil
+}
+
 // extractPriceFromResult extracts the price from the pricing API result
 func extractPriceFromResult(result *pricing.GetProductsOutput) (float64, error) {
 	if len(result.PriceList) > 0 {
@@ -0,0 +1,82 @@
+package awsri
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// tagFilter はリソースのタグに一致するかを判定するフィルター
+// 異なるキーのフィルターはすべて一致する必要があり、同じキーの複数の値はいずれかに一致すればよい
+// 値のリストが空の場合はキーのどの値にも一致する
+type tagFilter map[string][]string
+
+// parseTagFilter はkey=valueまたはkeyの形式のタグフィルターを解析する
+func parseTagFilter(defs []string) (tagFilter, error) {
+	filter := make(tagFilter)
+	for _, def := range defs {
+		key, value, hasValue := strings.Cut(def, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("invalid tag filter: %s, expected format: key=value or key", def)
+		}
+		if _, ok := filter[key]; !ok {
+			filter[key] = nil
+		}
+		if hasValue {
+			filter[key] = append(filter[key], value)
+		}
+	}
+	return filter, nil
+}
+
+// matchesAll はタグがフィルターのすべてのキーに一致するかどうかを返す
+func (f tagFilter) matchesAll(tags map[string]string) bool {
+	for key := range f {
+		if !f.matchesKey(key, tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAny はタグがフィルターのいずれかのキーに一致するかどうかを返す
+func (f tagFilter) matchesAny(tags map[string]string) bool {
+	for key := range f {
+		if f.matchesKey(key, tags) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f tagFilter) matchesKey(key string, tags map[string]string) bool {
+	value, ok := tags[key]
+	if !ok {
+		return false
+	}
+	if len(f[key]) == 0 {
+		return true
+	}
+	for _, v := range f[key] {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// tagsKey はマップのキーに使うタグの正規化した文字列を返す
+func tagsKey(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		parts = append(parts, key+"="+tags[key])
+	}
+	return strings.Join(parts, ",")
+}
@@ -3,32 +3,48 @@ package awsri
 import (
 	"context"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
 )
 
 // InstanceInfo は複数のRIを表現するための汎用的な構造体
 type InstanceInfo struct {
-	ServiceType  string // "rds", "elasticache" など
-	InstanceType string // "m5.large" など
-	Count        int    // インスタンス数
-	Description  string // "postgresql", "redis" など
-	MultiAz      bool   // マルチAZかどうか（RDS用）
+	ServiceType  string            // "rds", "elasticache", "opensearch" など
+	InstanceType string            // "m5.large" など
+	Count        int               // インスタンス数
+	Description  string            // "postgresql", "redis" など
+	MultiAz      bool              // マルチAZかどうか（RDS用）
+	Region       string            // "ap-northeast-1" など（空の場合はデフォルトリージョン）
+	Account      string            // 取得元のアカウントID（generate用）
+	Accounts     map[string]int    // アカウントごとのインスタンス数の内訳（generate用）
+	Tags         map[string]string // リソースのタグ（generate用、集計後は--group-by-tagのタグのみ）
+	ResourceID   string            // リソースの識別子（generate用、集計前のみ）
+	Status       string            // リソースの状態（generate用、集計前のみ）
+	CreatedAt    time.Time         // リソースの作成日時（generate用、集計前のみ）
 }
 
 // InstancePriceResult は各インスタンスの料金計算結果を表す構造体
 type InstancePriceResult struct {
 	ServiceType  string
 	InstanceType string
+	Region       string
 	Count        int
 	Upfront      float64
 	Monthly      float64
 	Yearly       float64
+	// OnDemandYearly は--scheduleの稼働時間でのオンデマンド料金（年額）で、HasOnDemandがtrueの場合のみ有効
+	OnDemandYearly float64
+	HasOnDemand    bool
 }
 
 // TotalPriceResult は複数インスタンスの合計料金計算結果を表す構造体
@@ -57,19 +73,61 @@ func (c *TotalCommand) Run(ctx context.Context) error {
 		return fmt.Errorf("failed to parse instances info: %w", err)
 	}
 
+	// マニフェストが指定されている場合は読み込む
+	var manifest *Manifest
+	if c.opts.Manifest != "" {
+		manifest, err = LoadManifest(c.opts.Manifest)
+		if err != nil {
+			return err
+		}
+		for _, instance := range manifest.InstanceInfos() {
+			// totalで料金を計算できないサービスはスキップする
+			if !c.supportsServiceType(instance.ServiceType) {
+				fmt.Fprintf(os.Stderr, "Warning: skipping %s %s in manifest: not supported by total\n", instance.ServiceType, instance.InstanceType)
+				continue
+			}
+			instances = append(instances, instance)
+		}
+	}
+
+	// 期間とオファリングタイプはフラグ、マニフェスト、デフォルト値の順に決める
+	c.applyPurchaseDefaults(manifest)
+
 	// インスタンスが指定されていない場合はエラー
 	if len(instances) == 0 {
 		return fmt.Errorf("no instances specified")
 	}
 
-	// AWS設定を読み込み
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion("ap-northeast-1"))
-	if err != nil {
-		return fmt.Errorf("unable to load SDK config: %w", err)
+	// サイズフレキシブルなRDSとElastiCacheのRIはファミリーの最小のサイズでまとめて購入する
+	if c.opts.Normalize {
+		instances, err = c.normalizeInstances(ctx, instances)
+		if err != nil {
+			return fmt.Errorf("failed to normalize instances: %w", err)
+		}
+	}
+
+	// 稼働スケジュールを検証し、オンデマンド料金と比較できないサービスを明示する
+	if c.opts.Schedule != "" {
+		hours, err := c.opts.scheduledHours()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, c.opts.scheduleNote(hours))
+		for _, serviceType := range c.unscheduledServiceTypes(instances) {
+			fmt.Fprintf(os.Stderr, "Note: --schedule compares on-demand costs for RDS and ElastiCache only; %s lines are shown without savings\n", serviceDisplayName(serviceType))
+		}
+	}
+
+	// Redshift Serverlessはリザーブドノードの対象外であることを明示する
+	for _, instance := range instances {
+		if instance.ServiceType == "redshift" {
+			fmt.Fprintln(os.Stderr, redshiftServerlessNote)
+			break
+		}
 	}
 
 	// 料金計算
-	result, err := c.calculateTotalPrice(ctx, cfg, instances)
+	result, err := c.calculateTotalPrice(ctx, instances)
 	if err != nil {
 		return fmt.Errorf("failed to calculate total price: %w", err)
 	}
@@ -80,6 +138,33 @@ func (c *TotalCommand) Run(ctx context.Context) error {
 	return nil
 }
 
+// applyPurchaseDefaults は--durationと--offering-typeが指定されていない場合にマニフェストの値を使い、
+// マニフェストにもない場合は1年のPartial Upfrontにする
+func (c *TotalCommand) applyPurchaseDefaults(manifest *Manifest) {
+	if c.opts.Duration == 0 && manifest != nil {
+		c.opts.Duration = manifest.Duration
+	}
+	if c.opts.OfferingType == "" && manifest != nil {
+		c.opts.OfferingType = manifest.OfferingType
+	}
+	if c.opts.Duration == 0 {
+		c.opts.Duration = 1
+	}
+	if c.opts.OfferingType == "" {
+		c.opts.OfferingType = "Partial Upfront"
+	}
+}
+
+// supportsServiceType はtotalで料金を計算できるサービスかどうかを返す
+func (c *TotalCommand) supportsServiceType(serviceType string) bool {
+	switch serviceType {
+	case "rds", "elasticache", "opensearch", "redshift", "memorydb", "dynamodb", "docdb", "neptune":
+		return true
+	default:
+		return false
+	}
+}
+
 // parseInstancesInfo はコマンドライン引数からインスタンス情報を解析する
 func (c *TotalCommand) parseInstancesInfo() ([]InstanceInfo, error) {
 	var instances []InstanceInfo
@@ -87,8 +172,8 @@ func (c *TotalCommand) parseInstancesInfo() ([]InstanceInfo, error) {
 	// RDSインスタンスの解析
 	for _, rdsDef := range c.opts.RDSInstances {
 		parts := strings.Split(rdsDef, ":")
-		if len(parts) != 4 {
-			return nil, fmt.Errorf("invalid RDS instance format: %s, expected format: instance-type:count:product-description:multi-az", rdsDef)
+		if len(parts) != 4 && len(parts) != 5 {
+			return nil, fmt.Errorf("invalid RDS instance format: %s, expected format: instance-type:count:product-description:multi-az[:region]", rdsDef)
 		}
 
 		instanceType := parts[0]
@@ -101,6 +186,10 @@ func (c *TotalCommand) parseInstancesInfo() ([]InstanceInfo, error) {
 		if err != nil {
 			return nil, fmt.Errorf("invalid multi-az value in RDS instance: %s", parts[3])
 		}
+		region := ""
+		if len(parts) == 5 {
+			region = parts[4]
+		}
 
 		// RDSインスタンスタイプには "db." プレフィックスが必要
 		if !strings.HasPrefix(instanceType, "db.") {
@@ -113,14 +202,15 @@ func (c *TotalCommand) parseInstancesInfo() ([]InstanceInfo, error) {
 			Count:        count,
 			Description:  description,
 			MultiAz:      multiAz,
+			Region:       region,
 		})
 	}
 
 	// ElastiCacheインスタンスの解析
 	for _, cacheDef := range c.opts.ElasticacheInstances {
 		parts := strings.Split(cacheDef, ":")
-		if len(parts) != 3 {
-			return nil, fmt.Errorf("invalid ElastiCache instance format: %s, expected format: node-type:count:product-description", cacheDef)
+		if len(parts) != 3 && len(parts) != 4 {
+			return nil, fmt.Errorf("invalid ElastiCache instance format: %s, expected format: node-type:count:product-description[:region]", cacheDef)
 		}
 
 		instanceType := parts[0]
@@ -129,6 +219,10 @@ func (c *TotalCommand) parseInstancesInfo() ([]InstanceInfo, error) {
 			return nil, fmt.Errorf("invalid count in ElastiCache instance: %s", parts[1])
 		}
 		description := parts[2]
+		region := ""
+		if len(parts) == 4 {
+			region = parts[3]
+		}
 
 		// ElastiCacheインスタンスタイプには "cache." プレフィックスが必要
 		if !strings.HasPrefix(instanceType, "cache.") {
@@ -141,20 +235,199 @@ func (c *TotalCommand) parseInstancesInfo() ([]InstanceInfo, error) {
 			Count:        count,
 			Description:  description,
 			MultiAz:      false, // ElastiCacheはMultiAzの概念が異なる
+			Region:       region,
+		})
+	}
+
+	// OpenSearchインスタンスの解析
+	for _, openSearchDef := range c.opts.OpenSearchInstances {
+		parts := strings.Split(openSearchDef, ":")
+		if len(parts) != 2 && len(parts) != 3 {
+			return nil, fmt.Errorf("invalid OpenSearch instance format: %s, expected format: instance-type:count[:region]", openSearchDef)
+		}
+
+		count, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid count in OpenSearch instance: %s", parts[1])
+		}
+		region := ""
+		if len(parts) == 3 {
+			region = parts[2]
+		}
+
+		// OpenSearchインスタンスタイプには ".search" サフィックスが必要
+		instanceType := openSearchInstanceType(parts[0])
+
+		instances = append(instances, InstanceInfo{
+			ServiceType:  "opensearch",
+			InstanceType: instanceType,
+			Count:        count,
+			Region:       region,
+		})
+	}
+
+	// Redshiftノードの解析
+	for _, redshiftDef := range c.opts.RedshiftNodes {
+		parts := strings.Split(redshiftDef, ":")
+		if len(parts) != 2 && len(parts) != 3 {
+			return nil, fmt.Errorf("invalid Redshift node format: %s, expected format: node-type:count[:region]", redshiftDef)
+		}
+
+		nodeType := parts[0]
+		if !isRedshiftReservable(nodeType) {
+			return nil, fmt.Errorf("unsupported Redshift node type: %s (reserved nodes are available for RA3 and DC2 node types)", nodeType)
+		}
+		count, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid count in Redshift node: %s", parts[1])
+		}
+		region := ""
+		if len(parts) == 3 {
+			region = parts[2]
+		}
+
+		instances = append(instances, InstanceInfo{
+			ServiceType:  "redshift",
+			InstanceType: nodeType,
+			Count:        count,
+			Region:       region,
 		})
 	}
 
+	// MemoryDBノードの解析
+	for _, memoryDBDef := range c.opts.MemoryDBNodes {
+		parts := strings.Split(memoryDBDef, ":")
+		if len(parts) != 2 && len(parts) != 3 {
+			return nil, fmt.Errorf("invalid MemoryDB node format: %s, expected format: node-type:count[:region]", memoryDBDef)
+		}
+
+		count, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid count in MemoryDB node: %s", parts[1])
+		}
+		region := ""
+		if len(parts) == 3 {
+			region = parts[2]
+		}
+
+		// MemoryDBノードタイプには "db." プレフィックスが必要
+		nodeType := addInstanceTypePrefix("memorydb", parts[0])
+
+		instances = append(instances, InstanceInfo{
+			ServiceType:  "memorydb",
+			InstanceType: nodeType,
+			Count:        count,
+			Region:       region,
+		})
+	}
+
+	// DynamoDBキャパシティの解析
+	for _, dynamoDBDef := range c.opts.DynamoDBCapacity {
+		parts := strings.Split(dynamoDBDef, ":")
+		if len(parts) != 2 && len(parts) != 3 {
+			return nil, fmt.Errorf("invalid DynamoDB capacity format: %s, expected format: read|write:units[:region]", dynamoDBDef)
+		}
+
+		capacityType := parts[0]
+		if _, ok := dynamoDBCapacityGroups[capacityType]; !ok {
+			return nil, fmt.Errorf("invalid capacity type in DynamoDB capacity: %s (must be read or write)", capacityType)
+		}
+		units, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid units in DynamoDB capacity: %s", parts[1])
+		}
+		region := ""
+		if len(parts) == 3 {
+			region = parts[2]
+		}
+
+		instances = append(instances, InstanceInfo{
+			ServiceType:  "dynamodb",
+			InstanceType: capacityType,
+			Count:        units,
+			Region:       region,
+		})
+	}
+
+	// DocumentDBとNeptuneのインスタンスの解析
+	rdsCompatibleDefs := []struct {
+		engine rdsCompatibleEngine
+		name   string
+		defs   []string
+	}{
+		{docDBEngine, "DocumentDB", c.opts.DocDBInstances},
+		{neptuneEngine, "Neptune", c.opts.NeptuneInstances},
+	}
+	for _, d := range rdsCompatibleDefs {
+		for _, def := range d.defs {
+			parts := strings.Split(def, ":")
+			if len(parts) != 2 && len(parts) != 3 {
+				return nil, fmt.Errorf("invalid %s instance format: %s, expected format: instance-class:count[:region]", d.name, def)
+			}
+
+			count, err := strconv.Atoi(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid count in %s instance: %s", d.name, parts[1])
+			}
+			region := ""
+			if len(parts) == 3 {
+				region = parts[2]
+			}
+
+			// インスタンスクラスには "db." プレフィックスが必要
+			instanceClass := addInstanceTypePrefix(d.engine.serviceType, parts[0])
+
+			instances = append(instances, InstanceInfo{
+				ServiceType:  d.engine.serviceType,
+				InstanceType: instanceClass,
+				Count:        count,
+				Description:  d.engine.productDescription,
+				Region:       region,
+			})
+		}
+	}
+
 	return instances, nil
 }
 
 // calculateTotalPrice は複数インスタンスの合計料金を計算する
-func (c *TotalCommand) calculateTotalPrice(ctx context.Context, cfg aws.Config, instances []InstanceInfo) (TotalPriceResult, error) {
+func (c *TotalCommand) calculateTotalPrice(ctx context.Context, instances []InstanceInfo) (TotalPriceResult, error) {
 	result := TotalPriceResult{
 		Instances: []InstancePriceResult{},
 	}
 
+	// リージョンごとのAWS設定
+	configs := make(map[string]aws.Config)
+
 	for _, instance := range instances {
 		var upfront, monthly, yearly float64
+
+		// リージョンが指定されていない場合はデフォルトリージョンを使用
+		if instance.Region == "" {
+			instance.Region = c.opts.Region
+		}
+
+		// AWS設定を読み込み（リージョンごとに1回）
+		cfg, ok := configs[instance.Region]
+		if !ok {
+			var err error
+			cfg, err = config.LoadDefaultConfig(ctx, config.WithRegion(instance.Region))
+			if err != nil {
+				return result, fmt.Errorf("unable to load SDK config: %w", err)
+			}
+			configs[instance.Region] = cfg
+		}
+
+		// DynamoDBのリザーブドキャパシティは100ユニット単位で購入するため切り捨てる
+		if instance.ServiceType == "dynamodb" {
+			reserved := dynamoDBReservedUnits(instance.Count)
+			if reserved != instance.Count {
+				fmt.Fprintf(os.Stderr, "Note: DynamoDB %s capacity %d is rounded down to %d units (reserved in %d-unit blocks)\n",
+					instance.InstanceType, instance.Count, reserved, dynamoDBReservationBlock)
+				instance.Count = reserved
+			}
+		}
+
 		var err error
 
 		switch instance.ServiceType {
@@ -162,6 +435,16 @@ func (c *TotalCommand) calculateTotalPrice(ctx context.Context, cfg aws.Config,
 			upfront, monthly, yearly, err = c.calculateRDSPrice(ctx, cfg, instance)
 		case "elasticache":
 			upfront, monthly, yearly, err = c.calculateElastiCachePrice(ctx, cfg, instance)
+		case "opensearch":
+			upfront, monthly, yearly, err = c.calculateOpenSearchPrice(ctx, cfg, instance)
+		case "redshift":
+			upfront, monthly, yearly, err = c.calculateRedshiftPrice(ctx, cfg, instance)
+		case "memorydb":
+			upfront, monthly, yearly, err = c.calculateMemoryDBPrice(ctx, cfg, instance)
+		case "dynamodb":
+			upfront, monthly, yearly, err = c.calculateDynamoDBPrice(ctx, cfg, instance)
+		case "docdb", "neptune":
+			upfront, monthly, yearly, err = c.calculateRDSCompatiblePrice(ctx, cfg, instance)
 		default:
 			return result, fmt.Errorf("unsupported service type: %s", instance.ServiceType)
 		}
@@ -176,14 +459,24 @@ func (c *TotalCommand) calculateTotalPrice(ctx context.Context, cfg aws.Config,
 		yearly *= float64(instance.Count)
 
 		// 結果に追加
-		result.Instances = append(result.Instances, InstancePriceResult{
+		priceResult := InstancePriceResult{
 			ServiceType:  instance.ServiceType,
 			InstanceType: instance.InstanceType,
+			Region:       instance.Region,
 			Count:        instance.Count,
 			Upfront:      upfront,
 			Monthly:      monthly,
 			Yearly:       yearly,
-		})
+		}
+
+		// --scheduleが指定された場合は稼働時間分のオンデマンド料金と比較する
+		if c.opts.Schedule != "" {
+			priceResult.OnDemandYearly, priceResult.HasOnDemand, err = c.scheduledOnDemandYearly(cfg, instance)
+			if err != nil {
+				return result, err
+			}
+		}
+		result.Instances = append(result.Instances, priceResult)
 
 		// 合計に加算
 		result.TotalUpfront += upfront
@@ -194,6 +487,106 @@ func (c *TotalCommand) calculateTotalPrice(ctx context.Context, cfg aws.Config,
 	return result, nil
 }
 
+// unscheduledServiceTypes は--scheduleでオンデマンド料金と比較できないサービスタイプを返す
+func (c *TotalCommand) unscheduledServiceTypes(instances []InstanceInfo) []string {
+	var serviceTypes []string
+	seen := make(map[string]bool)
+	for _, instance := range instances {
+		if instance.ServiceType == "rds" || instance.ServiceType == "elasticache" || seen[instance.ServiceType] {
+			continue
+		}
+		seen[instance.ServiceType] = true
+		serviceTypes = append(serviceTypes, instance.ServiceType)
+	}
+	return serviceTypes
+}
+
+// scheduledOnDemandYearly は--scheduleの稼働時間でのオンデマンド料金（年額、インスタンス数分）を返す
+// オンデマンド料金と比較できるのはRDSとElastiCacheのみで、それ以外はfalseを返す
+func (c *TotalCommand) scheduledOnDemandYearly(cfg aws.Config, instance InstanceInfo) (float64, bool, error) {
+	hours, err := c.opts.scheduledHours()
+	if err != nil {
+		return 0, false, err
+	}
+
+	var onDemandMonthly float64
+	switch instance.ServiceType {
+	case "rds":
+		rdsCmd := NewRDSCommand(RDSOption{
+			DbInstanceClass:    instance.InstanceType,
+			ProductDescription: instance.Description,
+			MultiAz:            instance.MultiAz,
+		})
+		databaseEngine, err := rdsCmd.getDatabaseEngine(instance.Description)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to get database engine: %w", err)
+		}
+		onDemandMonthly, err = rdsCmd.getRdsOnDemandPrice(cfg, instance.InstanceType, databaseEngine, instance.MultiAz)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to get on-demand price for RDS %s: %w", instance.InstanceType, err)
+		}
+	case "elasticache":
+		elasticacheCmd := NewElastiCacheCommand(ElasticacheOption{
+			CacheNodeType:      instance.InstanceType,
+			ProductDescription: instance.Description,
+		})
+		onDemandMonthly, err = elasticacheCmd.getElastiCacheOnDemandPrice(cfg, instance.InstanceType, instance.Description)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to get on-demand price for ElastiCache %s: %w", instance.InstanceType, err)
+		}
+	default:
+		return 0, false, nil
+	}
+
+	return scheduledOnDemand(onDemandMonthly, hours) * 12 * float64(instance.Count), true, nil
+}
+
+// normalizeInstances はサイズフレキシブルなRDSとElastiCacheの行をファミリー・リージョン・エンジンごとの正規化ユニットに換算し、
+// ファミリーの最小のサイズの台数に置き換える
+// 購入後にインスタンスのサイズを変更してもRIの適用が外れないようにするため
+func (c *TotalCommand) normalizeInstances(ctx context.Context, instances []InstanceInfo) ([]InstanceInfo, error) {
+	pools, result := normalizeFleet(instances, c.opts.Region)
+
+	for _, pool := range pools {
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(pool.region))
+		if err != nil {
+			return nil, fmt.Errorf("unable to load SDK config: %w", err)
+		}
+
+		var hasOffering func(instanceType string) (bool, error)
+		switch pool.serviceType {
+		case "rds":
+			hasOffering = func(instanceType string) (bool, error) {
+				return rdsOfferingExists(ctx, rds.NewFromConfig(cfg), instanceType, pool.description, c.opts.Duration, c.opts.OfferingType)
+			}
+		case "elasticache":
+			hasOffering = func(instanceType string) (bool, error) {
+				return cacheOfferingExists(ctx, elasticache.NewFromConfig(cfg), instanceType, pool.description, c.opts.Duration, c.opts.OfferingType)
+			}
+		}
+
+		size, err := smallestOfferingSize(pool, hasOffering)
+		if err != nil {
+			return nil, err
+		}
+		instanceType := pool.instanceType(size)
+		count := pool.count(size, pool.units)
+
+		fmt.Fprintf(os.Stderr, "Note: %s %s %s in %s is %g normalization units (%d lines); buying %d x %s\n",
+			serviceDisplayName(pool.serviceType), pool.engine, pool.family, pool.region, pool.units, len(pool.lines), count, instanceType)
+
+		result = append(result, InstanceInfo{
+			ServiceType:  pool.serviceType,
+			InstanceType: instanceType,
+			Count:        count,
+			Description:  pool.description,
+			Region:       pool.region,
+		})
+	}
+
+	return result, nil
+}
+
 // calculateRDSPrice はRDSインスタンスの料金を計算する
 func (c *TotalCommand) calculateRDSPrice(ctx context.Context, cfg aws.Config, instance InstanceInfo) (float64, float64, float64, error) {
 	svc := rds.NewFromConfig(cfg)
@@ -205,6 +598,12 @@ func (c *TotalCommand) calculateRDSPrice(ctx context.Context, cfg aws.Config, in
 		MultiAz:            instance.MultiAz,
 	})
 
+	// OracleとSQL ServerはエディションとライセンスモデルごとのproductDescriptionでオファリングを取得する
+	productDescription, err := rdsCmd.getProductDescription()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
 	// データベースエンジンを取得
 	databaseEngine, err := rdsCmd.getDatabaseEngine(instance.Description)
 	if err != nil {
@@ -223,7 +622,7 @@ func (c *TotalCommand) calculateRDSPrice(ctx context.Context, cfg aws.Config, in
 		Duration:           aws.String(strconv.Itoa(c.opts.Duration)),
 		OfferingType:       aws.String(c.opts.OfferingType),
 		DBInstanceClass:    aws.String(instance.InstanceType),
-		ProductDescription: aws.String(instance.Description),
+		ProductDescription: aws.String(productDescription),
 		MultiAZ:            aws.Bool(instance.MultiAz),
 	}
 
@@ -238,7 +637,7 @@ func (c *TotalCommand) calculateRDSPrice(ctx context.Context, cfg aws.Config, in
 	}
 
 	// 適切なオファリングを取得
-	offering := rdsCmd.getOffering(o.ReservedDBInstancesOfferings, instance.Description, instance.MultiAz)
+	offering := rdsCmd.getOffering(o.ReservedDBInstancesOfferings, productDescription, instance.MultiAz)
 	if offering == nil {
 		// 利用可能なオファリングの説明を表示
 		availableDescriptions := []string{}
@@ -304,16 +703,144 @@ func (c *TotalCommand) calculateElastiCachePrice(ctx context.Context, cfg aws.Co
 	return fixedPrice, monthlyRecurring, effectiveYearly, nil
 }
 
+// calculateOpenSearchPrice はOpenSearchインスタンスの料金を計算する
+func (c *TotalCommand) calculateOpenSearchPrice(ctx context.Context, cfg aws.Config, instance InstanceInfo) (float64, float64, float64, error) {
+	// RIの料金情報を取得
+	offerings, err := describeOpenSearchOfferings(ctx, opensearch.NewFromConfig(cfg), instance.InstanceType)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	offering := findOpenSearchOffering(offerings, c.opts.Duration, c.opts.OfferingType)
+	if offering == nil {
+		return 0, 0, 0, fmt.Errorf("no reserved instances offerings found for OpenSearch %s", instance.InstanceType)
+	}
+
+	// 料金を計算
+	fixedPrice, monthlyRecurring := openSearchOfferingCharges(*offering)
+	durationMonths := DurationToMonths(c.opts.Duration)
+	effectiveYearly := CalculateEffectiveMonthly(fixedPrice, monthlyRecurring, durationMonths)
+
+	return fixedPrice, monthlyRecurring, effectiveYearly, nil
+}
+
+// calculateRedshiftPrice はRedshiftノードの料金を計算する
+func (c *TotalCommand) calculateRedshiftPrice(ctx context.Context, cfg aws.Config, instance InstanceInfo) (float64, float64, float64, error) {
+	// リザーブドノードの料金情報を取得
+	offerings, err := describeRedshiftOfferings(ctx, redshift.NewFromConfig(cfg), instance.InstanceType)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	offering := findRedshiftOffering(offerings, c.opts.Duration, c.opts.OfferingType)
+	if offering == nil {
+		return 0, 0, 0, fmt.Errorf("no reserved node offerings found for Redshift %s", instance.InstanceType)
+	}
+
+	// 料金を計算
+	fixedPrice, monthlyRecurring := redshiftOfferingCharges(*offering)
+	durationMonths := DurationToMonths(c.opts.Duration)
+	effectiveYearly := CalculateEffectiveMonthly(fixedPrice, monthlyRecurring, durationMonths)
+
+	return fixedPrice, monthlyRecurring, effectiveYearly, nil
+}
+
+// calculateMemoryDBPrice はMemoryDBノードの料金を計算する
+func (c *TotalCommand) calculateMemoryDBPrice(ctx context.Context, cfg aws.Config, instance InstanceInfo) (float64, float64, float64, error) {
+	// リザーブドノードの料金情報を取得
+	offering, err := describeMemoryDBOffering(ctx, memorydb.NewFromConfig(cfg), instance.InstanceType, c.opts.Duration, c.opts.OfferingType)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if offering == nil {
+		return 0, 0, 0, fmt.Errorf("no reserved node offerings found for MemoryDB %s", instance.InstanceType)
+	}
+
+	// 料金を計算
+	fixedPrice, monthlyRecurring := memoryDBOfferingCharges(*offering)
+	durationMonths := DurationToMonths(c.opts.Duration)
+	effectiveYearly := CalculateEffectiveMonthly(fixedPrice, monthlyRecurring, durationMonths)
+
+	return fixedPrice, monthlyRecurring, effectiveYearly, nil
+}
+
+// calculateDynamoDBPrice はDynamoDBのリザーブドキャパシティ1ユニットあたりの料金を計算する
+// DynamoDBのリザーブドキャパシティは前払い料金と時間単位の料金の組み合わせのみで、--offering-typeは使用しない
+func (c *TotalCommand) calculateDynamoDBPrice(ctx context.Context, cfg aws.Config, instance InstanceInfo) (float64, float64, float64, error) {
+	capacityPricing, err := getDynamoDBCapacityPricing(ctx, cfg, instance.InstanceType)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	upfront, ok := capacityPricing.ReservedUpfront[c.opts.Duration]
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("no reserved capacity pricing found for DynamoDB %s capacity with duration %dy", instance.InstanceType, c.opts.Duration)
+	}
+
+	// 料金は100ユニット単位のため1ユニットあたりに換算する
+	fixedPrice := upfront / dynamoDBReservationBlock
+	monthlyRecurring := capacityPricing.ReservedHourly[c.opts.Duration] / dynamoDBReservationBlock * 24 * 30
+	durationMonths := DurationToMonths(c.opts.Duration)
+	effectiveYearly := CalculateEffectiveMonthly(fixedPrice, monthlyRecurring, durationMonths)
+
+	return fixedPrice, monthlyRecurring, effectiveYearly, nil
+}
+
+// calculateRDSCompatiblePrice はDocumentDBまたはNeptuneのインスタンスの料金を計算する
+func (c *TotalCommand) calculateRDSCompatiblePrice(ctx context.Context, cfg aws.Config, instance InstanceInfo) (float64, float64, float64, error) {
+	engine := rdsCompatibleEngines[instance.ServiceType]
+
+	// RIの料金情報を取得（RDSのオファリングAPIで販売される）
+	offering, err := describeRDSCompatibleOffering(ctx, rds.NewFromConfig(cfg), engine, instance.InstanceType, c.opts.Duration, c.opts.OfferingType)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if offering == nil {
+		return 0, 0, 0, fmt.Errorf("no reserved instances offerings found for %s %s", serviceDisplayName(instance.ServiceType), instance.InstanceType)
+	}
+
+	// 料金を計算
+	fixedPrice, monthlyRecurring := rdsOfferingCharges(*offering)
+	durationMonths := DurationToMonths(c.opts.Duration)
+	effectiveYearly := CalculateEffectiveMonthly(fixedPrice, monthlyRecurring, durationMonths)
+
+	return fixedPrice, monthlyRecurring, effectiveYearly, nil
+}
+
+// serviceDisplayName は表示用のサービス名を返す
+func serviceDisplayName(serviceType string) string {
+	switch serviceType {
+	case "ec2":
+		return "EC2"
+	case "elasticache":
+		return "ElastiCache"
+	case "opensearch":
+		return "OpenSearch"
+	case "redshift":
+		return "Redshift"
+	case "memorydb":
+		return "MemoryDB"
+	case "dynamodb":
+		return "DynamoDB"
+	case "docdb":
+		return "DocumentDB"
+	case "neptune":
+		return "Neptune"
+	default:
+		return "RDS"
+	}
+}
+
 // renderResult は計算結果を表示する
 func (c *TotalCommand) renderResult(result TotalPriceResult) {
 	// 同じインスタンスタイプをまとめるためのマップ
-	// キー: "サービスタイプ:インスタンスタイプ" (例: "rds:db.m5.large")
+	// キー: "サービスタイプ:インスタンスタイプ:リージョン" (例: "rds:db.m5.large:ap-northeast-1")
 	// 値: まとめた結果
 	groupedInstances := make(map[string]InstancePriceResult)
 
 	// 各インスタンスの結果をグループ化
 	for _, instance := range result.Instances {
-		key := fmt.Sprintf("%s:%s", instance.ServiceType, instance.InstanceType)
+		key := fmt.Sprintf("%s:%s:%s", instance.ServiceType, instance.InstanceType, instance.Region)
 		
 		if existing, ok := groupedInstances[key]; ok {
 			// 既存のエントリがある場合は値を合算
@@ -321,6 +848,8 @@ func (c *TotalCommand) renderResult(result TotalPriceResult) {
 			existing.Upfront += instance.Upfront
 			existing.Monthly += instance.Monthly
 			existing.Yearly += instance.Yearly
+			existing.OnDemandYearly += instance.OnDemandYearly
+			existing.HasOnDemand = existing.HasOnDemand || instance.HasOnDemand
 			groupedInstances[key] = existing
 		} else {
 			// 新しいエントリを追加
@@ -344,19 +873,19 @@ func (c *TotalCommand) renderTable(result TotalPriceResult, groupedInstances map
 
 	// グループ化した結果を表示
 	for _, instance := range groupedInstances {
-		serviceName := "RDS"
-		if instance.ServiceType == "elasticache" {
-			serviceName = "ElastiCache"
-		}
+		serviceName := serviceDisplayName(instance.ServiceType)
+
+		// 節約額は--scheduleでオンデマンド料金と比較した場合のみ表示する
+		yearlySavings, savingsPercent := scheduledSavings(instance)
 
 		tableRenderer.AppendReservedRow(
 			c.opts.Duration,
-			fmt.Sprintf("%s (%s %s x%d)", c.opts.OfferingType, serviceName, instance.InstanceType, instance.Count),
+			fmt.Sprintf("%s (%s %s x%d, %s)", c.opts.OfferingType, serviceName, instance.InstanceType, instance.Count, instance.Region),
 			instance.Upfront,
 			instance.Monthly,
 			instance.Yearly,
-			0, // 節約額は表示しない
-			0, // 節約率は表示しない
+			yearlySavings,
+			savingsPercent,
 		)
 	}
 
@@ -378,37 +907,64 @@ func (c *TotalCommand) renderTable(result TotalPriceResult, groupedInstances map
 
 // renderCSV はCSV形式で結果を表示する
 func (c *TotalCommand) renderCSV(result TotalPriceResult, groupedInstances map[string]InstancePriceResult) {
-	// CSVヘッダーを出力
-	fmt.Println("Duration,OfferingType,ServiceType,InstanceType,Count,Upfront,Monthly,Yearly")
+	// CSVヘッダーを出力（--scheduleが指定された場合はオンデマンド料金との比較列を追加）
+	scheduled := c.opts.Schedule != ""
+	if scheduled {
+		fmt.Println("Duration,OfferingType,ServiceType,InstanceType,Region,Count,Upfront,Monthly,Yearly,OnDemandYearly,YearlySavings,OnDemandCheaper")
+	} else {
+		fmt.Println("Duration,OfferingType,ServiceType,InstanceType,Region,Count,Upfront,Monthly,Yearly")
+	}
 
 	// グループ化した結果を表示
 	for _, instance := range groupedInstances {
-		serviceName := "RDS"
-		if instance.ServiceType == "elasticache" {
-			serviceName = "ElastiCache"
-		}
+		serviceName := serviceDisplayName(instance.ServiceType)
 
-		fmt.Printf("%dy,%s,%s,%s,%d,%.1f,%.1f,%.1f\n",
+		fmt.Printf("%dy,%s,%s,%s,%s,%d,%.1f,%.1f,%.1f",
 			c.opts.Duration,
 			c.opts.OfferingType,
 			serviceName,
 			instance.InstanceType,
+			instance.Region,
 			instance.Count,
 			instance.Upfront,
 			instance.Monthly,
 			instance.Yearly,
 		)
+		if scheduled {
+			if instance.HasOnDemand {
+				yearlySavings, _ := scheduledSavings(instance)
+				fmt.Printf(",%.1f,%.1f,%t", instance.OnDemandYearly, yearlySavings, yearlySavings < 0)
+			} else {
+				fmt.Print(",,,")
+			}
+		}
+		fmt.Println()
 	}
 
 	// 合計を表示
-	fmt.Printf("%dy,%s,%s,%s,%s,%.1f,%.1f,%.1f\n",
+	fmt.Printf("%dy,%s,%s,%s,%s,%s,%.1f,%.1f,%.1f",
 		c.opts.Duration,
 		"Total",
 		"",
 		"",
 		"",
+		"",
 		result.TotalUpfront,
 		result.TotalMonthly,
 		result.TotalYearly,
 	)
-}
\ No newline at end of file
+	if scheduled {
+		fmt.Print(",,,")
+	}
+	fmt.Println()
+}
+
+// scheduledSavings は--scheduleの稼働時間でのオンデマンド料金と比べた年間の節約額と節約率を返す
+// オンデマンド料金と比較していない場合は0を返す
+func scheduledSavings(instance InstancePriceResult) (float64, float64) {
+	if !instance.HasOnDemand || instance.OnDemandYearly == 0 {
+		return 0, 0
+	}
+	yearlySavings := instance.OnDemandYearly - instance.Yearly
+	return yearlySavings, yearlySavings / instance.OnDemandYearly * 100
+}
@@ -0,0 +1,539 @@
+package awsri
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costexplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+// utilizationHeadings は利用率とカバレッジの表の見出し
+var utilizationHeadings = []string{"Type", "Service", "Instance", "Region", "Utilization/Coverage", "Unused/On-Demand Hours", "Unused/On-Demand Cost (USD)"}
+
+type UtilizationOption struct {
+	Start     string   `name:"start" help:"Start of the period (YYYY-MM-DD, inclusive; default: 30 days before end)"`
+	End       string   `name:"end" help:"End of the period (YYYY-MM-DD, exclusive; default: today)"`
+	Threshold float64  `name:"threshold" default:"80" help:"Utilization in percent below which reservations are reported as underused"`
+	Services  []string `name:"services" sep:"," default:"ec2,rds,elasticache,opensearch,redshift,memorydb" help:"Services of the reservations (comma separated)"`
+	Format    string   `name:"format" default:"table" help:"Output format (table, csv, json)"`
+}
+
+type UtilizationCommand struct {
+	opts UtilizationOption
+
+	// テストでスタブに差し替えられるようにAPIクライアントの生成処理を保持する
+	newCostExplorerClient func(cfg aws.Config) costExplorerAPI
+}
+
+// costExplorerAPI は利用率とカバレッジの取得に使うCost Explorer APIのサブセット
+type costExplorerAPI interface {
+	GetReservationUtilization(ctx context.Context, params *costexplorer.GetReservationUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationUtilizationOutput, error)
+	GetReservationCoverage(ctx context.Context, params *costexplorer.GetReservationCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationCoverageOutput, error)
+	GetSavingsPlansUtilization(ctx context.Context, params *costexplorer.GetSavingsPlansUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansUtilizationOutput, error)
+	GetSavingsPlansCoverage(ctx context.Context, params *costexplorer.GetSavingsPlansCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansCoverageOutput, error)
+}
+
+// costExplorerServices はサービスタイプごとのCost ExplorerのSERVICEディメンションの値
+var costExplorerServices = map[string]string{
+	"ec2":         "Amazon Elastic Compute Cloud - Compute",
+	"rds":         "Amazon Relational Database Service",
+	"elasticache": "Amazon ElastiCache",
+	"opensearch":  "Amazon OpenSearch Service",
+	"redshift":    "Amazon Redshift",
+	"memorydb":    "Amazon MemoryDB",
+}
+
+// UtilizationLine は利用率またはカバレッジの1行
+type UtilizationLine struct {
+	Type       string  `json:"type"` // ri-utilization, ri-coverage, sp-utilization, sp-coverage
+	Service    string  `json:"service"`
+	Instance   string  `json:"instance,omitempty"` // 利用率はインスタンスタイプ、カバレッジはインスタンスファミリー
+	Region     string  `json:"region,omitempty"`
+	Percentage float64 `json:"percentage"` // 利用率またはカバレッジ（%）
+	Hours      float64 `json:"hours"`      // 未使用の時間またはオンデマンドの稼働時間
+	Cost       float64 `json:"cost"`       // 未使用分のコストまたはオンデマンドのコスト（USD）
+}
+
+// UtilizationReport は期間内の利用率の低いRIとSavings Plans、カバーされていないオンデマンドの利用
+type UtilizationReport struct {
+	Start     string            `json:"start"`
+	End       string            `json:"end"`
+	Threshold float64           `json:"threshold"`
+	Lines     []UtilizationLine `json:"lines"`
+}
+
+// riUtilization はRIごとの期間内の使用時間の合計
+type riUtilization struct {
+	service        string
+	instanceType   string
+	region         string
+	purchasedHours float64
+	actualHours    float64
+	unusedHours    float64
+	unusedCost     float64
+}
+
+// onDemandUsage はファミリーごとの期間内のオンデマンドの利用の合計
+type onDemandUsage struct {
+	service       string
+	family        string
+	region        string
+	totalHours    float64
+	onDemandHours float64
+	onDemandCost  float64
+	coveredCost   float64 // Savings Plansでカバーされたコスト
+}
+
+func NewUtilizationCommand(opts UtilizationOption) *UtilizationCommand {
+	return &UtilizationCommand{
+		opts: opts,
+		newCostExplorerClient: func(cfg aws.Config) costExplorerAPI {
+			return costexplorer.NewFromConfig(cfg)
+		},
+	}
+}
+
+func (c *UtilizationCommand) Run(ctx context.Context) error {
+	for _, service := range c.opts.Services {
+		if _, ok := costExplorerServices[service]; !ok {
+			return fmt.Errorf("unsupported service: %s (must be one of: ec2, rds, elasticache, opensearch, redshift, memorydb)", service)
+		}
+	}
+	if c.opts.Format != "table" && c.opts.Format != "csv" && c.opts.Format != "json" {
+		return fmt.Errorf("unsupported output format: %s", c.opts.Format)
+	}
+	start, end, err := c.period(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	// Cost Explorerのエンドポイントはus-east-1のみ
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+	if err != nil {
+		return fmt.Errorf("unable to load SDK config: %w", err)
+	}
+
+	report, err := c.report(ctx, c.newCostExplorerClient(cfg), start, end)
+	if err != nil {
+		return err
+	}
+
+	switch c.opts.Format {
+	case "csv":
+		return renderUtilizationCSV(report)
+	case "json":
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(jsonData))
+	default:
+		fmt.Fprintf(os.Stderr, "Period: %s - %s\n", report.Start, report.End)
+		renderUtilizationTable(report)
+	}
+	return nil
+}
+
+// period は--startと--endの期間を返す（既定は直近30日）
+func (c *UtilizationCommand) period(now time.Time) (string, string, error) {
+	end := now.Truncate(24 * time.Hour)
+	if c.opts.End != "" {
+		t, err := time.Parse("2006-01-02", c.opts.End)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid end: %s (expected YYYY-MM-DD)", c.opts.End)
+		}
+		end = t
+	}
+	start := end.AddDate(0, 0, -30)
+	if c.opts.Start != "" {
+		t, err := time.Parse("2006-01-02", c.opts.Start)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid start: %s (expected YYYY-MM-DD)", c.opts.Start)
+		}
+		start = t
+	}
+	if !start.Before(end) {
+		return "", "", fmt.Errorf("start must be before end")
+	}
+	return start.Format("2006-01-02"), end.Format("2006-01-02"), nil
+}
+
+// report は期間内の利用率とカバレッジを取得して行にまとめる
+// RIは利用率が--threshold未満のもの、カバレッジはオンデマンドのコストがあるものだけを含める
+func (c *UtilizationCommand) report(ctx context.Context, client costExplorerAPI, start, end string) (UtilizationReport, error) {
+	report := UtilizationReport{Start: start, End: end, Threshold: c.opts.Threshold}
+	period := &costexplorerTypes.DateInterval{Start: aws.String(start), End: aws.String(end)}
+
+	for _, service := range c.opts.Services {
+		reservations, err := getRIUtilization(ctx, client, period, service)
+		if err != nil {
+			return report, err
+		}
+		for _, ri := range reservations {
+			utilization := 0.0
+			if ri.purchasedHours > 0 {
+				utilization = ri.actualHours / ri.purchasedHours * 100
+			}
+			if utilization >= c.opts.Threshold {
+				continue
+			}
+			report.Lines = append(report.Lines, UtilizationLine{
+				Type:       "ri-utilization",
+				Service:    serviceDisplayName(ri.service),
+				Instance:   ri.instanceType,
+				Region:     ri.region,
+				Percentage: utilization,
+				Hours:      ri.unusedHours,
+				Cost:       ri.unusedCost,
+			})
+		}
+	}
+
+	for _, service := range c.opts.Services {
+		usages, err := getRICoverage(ctx, client, period, service)
+		if err != nil {
+			return report, err
+		}
+		for _, usage := range usages {
+			coverage := 0.0
+			if usage.totalHours > 0 {
+				coverage = (usage.totalHours - usage.onDemandHours) / usage.totalHours * 100
+			}
+			report.Lines = append(report.Lines, UtilizationLine{
+				Type:       "ri-coverage",
+				Service:    serviceDisplayName(usage.service),
+				Instance:   usage.family,
+				Region:     usage.region,
+				Percentage: coverage,
+				Hours:      usage.onDemandHours,
+				Cost:       usage.onDemandCost,
+			})
+		}
+	}
+
+	spUtilization, ok, err := getSPUtilization(ctx, client, period)
+	if err != nil {
+		return report, err
+	}
+	if ok {
+		report.Lines = append(report.Lines, spUtilization)
+	}
+
+	spCoverage, err := getSPCoverage(ctx, client, period)
+	if err != nil {
+		return report, err
+	}
+	report.Lines = append(report.Lines, spCoverage...)
+
+	return report, nil
+}
+
+// getRIUtilization はサービスのRIごとの使用時間を期間全体で合計する
+func getRIUtilization(ctx context.Context, client costExplorerAPI, period *costexplorerTypes.DateInterval, service string) ([]*riUtilization, error) {
+	reservations := make(map[string]*riUtilization)
+	var keys []string
+	input := &costexplorer.GetReservationUtilizationInput{
+		TimePeriod:  period,
+		Granularity: costexplorerTypes.GranularityMonthly,
+		Filter:      costExplorerServiceFilter(service),
+		GroupBy: []costexplorerTypes.GroupDefinition{
+			{Type: costexplorerTypes.GroupDefinitionTypeDimension, Key: aws.String(string(costexplorerTypes.DimensionSubscriptionId))},
+		},
+	}
+	for {
+		output, err := client.GetReservationUtilization(ctx, input)
+		if err != nil {
+			if isDataUnavailable(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get reservation utilization for %s: %w", service, err)
+		}
+		for _, byTime := range output.UtilizationsByTime {
+			for _, group := range byTime.Groups {
+				if group.Utilization == nil {
+					continue
+				}
+				key := aws.ToString(group.Value)
+				ri, ok := reservations[key]
+				if !ok {
+					ri = &riUtilization{
+						service:      service,
+						instanceType: costExplorerAttribute(group.Attributes, "instanceType"),
+						region:       costExplorerAttribute(group.Attributes, "region"),
+					}
+					reservations[key] = ri
+					keys = append(keys, key)
+				}
+				ri.purchasedHours += parseCostExplorerAmount(group.Utilization.PurchasedHours)
+				ri.actualHours += parseCostExplorerAmount(group.Utilization.TotalActualHours)
+				ri.unusedHours += parseCostExplorerAmount(group.Utilization.UnusedHours)
+				ri.unusedCost += parseCostExplorerAmount(group.Utilization.RICostForUnusedHours)
+			}
+		}
+		if output.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = output.NextPageToken
+	}
+
+	result := make([]*riUtilization, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, reservations[key])
+	}
+	// 未使用分のコストが大きい順
+	sort.SliceStable(result, func(i, j int) bool { return result[i].unusedCost > result[j].unusedCost })
+	return result, nil
+}
+
+// getRICoverage はサービスのRIでカバーされていないオンデマンドの利用をインスタンスファミリーとリージョンごとに合計する
+func getRICoverage(ctx context.Context, client costExplorerAPI, period *costexplorerTypes.DateInterval, service string) ([]*onDemandUsage, error) {
+	usages := make(map[string]*onDemandUsage)
+	input := &costexplorer.GetReservationCoverageInput{
+		TimePeriod:  period,
+		Granularity: costexplorerTypes.GranularityMonthly,
+		Filter:      costExplorerServiceFilter(service),
+		GroupBy: []costexplorerTypes.GroupDefinition{
+			{Type: costexplorerTypes.GroupDefinitionTypeDimension, Key: aws.String(string(costexplorerTypes.DimensionInstanceType))},
+			{Type: costexplorerTypes.GroupDefinitionTypeDimension, Key: aws.String(string(costexplorerTypes.DimensionRegion))},
+		},
+	}
+	for {
+		output, err := client.GetReservationCoverage(ctx, input)
+		if err != nil {
+			if isDataUnavailable(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get reservation coverage for %s: %w", service, err)
+		}
+		for _, byTime := range output.CoveragesByTime {
+			for _, group := range byTime.Groups {
+				if group.Coverage == nil || group.Coverage.CoverageHours == nil {
+					continue
+				}
+				family := costExplorerAttribute(group.Attributes, "instanceType")
+				if f, _, ok := splitInstanceClass(family); ok {
+					family = f
+				}
+				region := costExplorerAttribute(group.Attributes, "region")
+				key := family + ":" + region
+				usage, ok := usages[key]
+				if !ok {
+					usage = &onDemandUsage{service: service, family: family, region: region}
+					usages[key] = usage
+				}
+				usage.totalHours += parseCostExplorerAmount(group.Coverage.CoverageHours.TotalRunningHours)
+				usage.onDemandHours += parseCostExplorerAmount(group.Coverage.CoverageHours.OnDemandHours)
+				if group.Coverage.CoverageCost != nil {
+					usage.onDemandCost += parseCostExplorerAmount(group.Coverage.CoverageCost.OnDemandCost)
+				}
+			}
+		}
+		if output.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = output.NextPageToken
+	}
+	return sortOnDemandUsages(usages), nil
+}
+
+// getSPUtilization は期間全体のSavings Plansの利用率を返す（Savings Plansがない場合はfalse）
+func getSPUtilization(ctx context.Context, client costExplorerAPI, period *costexplorerTypes.DateInterval) (UtilizationLine, bool, error) {
+	output, err := client.GetSavingsPlansUtilization(ctx, &costexplorer.GetSavingsPlansUtilizationInput{
+		TimePeriod:  period,
+		Granularity: costexplorerTypes.GranularityMonthly,
+	})
+	if err != nil {
+		if isDataUnavailable(err) {
+			return UtilizationLine{}, false, nil
+		}
+		return UtilizationLine{}, false, fmt.Errorf("failed to get Savings Plans utilization: %w", err)
+	}
+	if output.Total == nil || output.Total.Utilization == nil {
+		return UtilizationLine{}, false, nil
+	}
+	utilization := output.Total.Utilization
+	if parseCostExplorerAmount(utilization.TotalCommitment) == 0 {
+		return UtilizationLine{}, false, nil
+	}
+	return UtilizationLine{
+		Type:       "sp-utilization",
+		Service:    "Savings Plans",
+		Percentage: parseCostExplorerAmount(utilization.UtilizationPercentage),
+		Cost:       parseCostExplorerAmount(utilization.UnusedCommitment),
+	}, true, nil
+}
+
+// getSPCoverage はSavings Plansでカバーされていないオンデマンドのコストをサービスとインスタンスファミリーごとに返す
+func getSPCoverage(ctx context.Context, client costExplorerAPI, period *costexplorerTypes.DateInterval) ([]UtilizationLine, error) {
+	usages := make(map[string]*onDemandUsage)
+	input := &costexplorer.GetSavingsPlansCoverageInput{
+		TimePeriod:  period,
+		Granularity: costexplorerTypes.GranularityMonthly,
+		GroupBy: []costexplorerTypes.GroupDefinition{
+			{Type: costexplorerTypes.GroupDefinitionTypeDimension, Key: aws.String(string(costexplorerTypes.DimensionService))},
+			{Type: costexplorerTypes.GroupDefinitionTypeDimension, Key: aws.String(string(costexplorerTypes.DimensionInstanceTypeFamily))},
+		},
+	}
+	for {
+		output, err := client.GetSavingsPlansCoverage(ctx, input)
+		if err != nil {
+			if isDataUnavailable(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get Savings Plans coverage: %w", err)
+		}
+		for _, coverage := range output.SavingsPlansCoverages {
+			if coverage.Coverage == nil {
+				continue
+			}
+			service := costExplorerAttribute(coverage.Attributes, "service")
+			family := costExplorerAttribute(coverage.Attributes, "instanceTypeFamily")
+			key := service + ":" + family
+			usage, ok := usages[key]
+			if !ok {
+				usage = &onDemandUsage{service: service, family: family}
+				usages[key] = usage
+			}
+			usage.onDemandCost += parseCostExplorerAmount(coverage.Coverage.OnDemandCost)
+			usage.coveredCost += parseCostExplorerAmount(coverage.Coverage.SpendCoveredBySavingsPlans)
+		}
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	var lines []UtilizationLine
+	for _, usage := range sortOnDemandUsages(usages) {
+		coverage := usage.coveredCost / (usage.coveredCost + usage.onDemandCost) * 100
+		lines = append(lines, UtilizationLine{
+			Type:       "sp-coverage",
+			Service:    costExplorerServiceName(usage.service),
+			Instance:   usage.family,
+			Percentage: coverage,
+			Cost:       usage.onDemandCost,
+		})
+	}
+	return lines, nil
+}
+
+// sortOnDemandUsages はオンデマンドのコストがある利用をコストの大きい順に返す
+func sortOnDemandUsages(usages map[string]*onDemandUsage) []*onDemandUsage {
+	var result []*onDemandUsage
+	for _, usage := range usages {
+		if usage.onDemandCost > 0 {
+			result = append(result, usage)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].onDemandCost != result[j].onDemandCost {
+			return result[i].onDemandCost > result[j].onDemandCost
+		}
+		return result[i].family+result[i].region < result[j].family+result[j].region
+	})
+	return result
+}
+
+// costExplorerServiceFilter はサービスタイプのSERVICEディメンションのフィルターを返す
+func costExplorerServiceFilter(service string) *costexplorerTypes.Expression {
+	return &costexplorerTypes.Expression{
+		Dimensions: &costexplorerTypes.DimensionValues{
+			Key:    costexplorerTypes.DimensionService,
+			Values: []string{costExplorerServices[service]},
+		},
+	}
+}
+
+// costExplorerServiceName はCost Explorerのサービス名を表示名に変換する（対応するサービスタイプがない場合はそのまま返す）
+func costExplorerServiceName(name string) string {
+	for service, value := range costExplorerServices {
+		if value == name {
+			return serviceDisplayName(service)
+		}
+	}
+	return name
+}
+
+// costExplorerAttribute はグループの属性を返す
+// 属性のキーはAPIによってinstanceTypeとINSTANCE_TYPEのように表記が異なるため、大文字小文字と_を区別しない
+func costExplorerAttribute(attributes map[string]string, name string) string {
+	normalize := func(s string) string { return strings.ToLower(strings.ReplaceAll(s, "_", "")) }
+	for key, value := range attributes {
+		if normalize(key) == normalize(name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// parseCostExplorerAmount はCost Explorerの数値の文字列を返す（空の場合は0）
+func parseCostExplorerAmount(value *string) float64 {
+	amount, err := strconv.ParseFloat(aws.ToString(value), 64)
+	if err != nil {
+		return 0
+	}
+	return amount
+}
+
+// isDataUnavailable は対象のデータがない場合のエラーかどうかを返す
+func isDataUnavailable(err error) bool {
+	var dataUnavailable *costexplorerTypes.DataUnavailableException
+	return errors.As(err, &dataUnavailable)
+}
+
+// renderUtilizationTable はテーブル形式で結果を表示する
+func renderUtilizationTable(report UtilizationReport) {
+	table := NewTable(utilizationHeadings)
+
+	for _, line := range report.Lines {
+		hours := "-"
+		if line.Type != "sp-utilization" && line.Type != "sp-coverage" {
+			hours = fmt.Sprintf("%.0f", line.Hours)
+		}
+		table.Append([]string{
+			line.Type,
+			line.Service,
+			line.Instance,
+			line.Region,
+			fmt.Sprintf("%.1f%%", line.Percentage),
+			hours,
+			fmt.Sprintf("%.2f", line.Cost),
+		})
+	}
+	table.Render()
+}
+
+// renderUtilizationCSV はCSV形式で結果を表示する
+func renderUtilizationCSV(report UtilizationReport) error {
+	writer := csv.NewWriter(os.Stdout)
+	if err := writer.Write([]string{"Type", "Service", "Instance", "Region", "Percentage", "Hours", "Cost"}); err != nil {
+		return err
+	}
+	for _, line := range report.Lines {
+		record := []string{
+			line.Type,
+			line.Service,
+			line.Instance,
+			line.Region,
+			fmt.Sprintf("%.1f", line.Percentage),
+			fmt.Sprintf("%.0f", line.Hours),
+			fmt.Sprintf("%.2f", line.Cost),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,144 @@
+package awsri
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/costexplorer"
+	costexplorerTypes "github.com/aws/aws-sdk-go-v2/service/costexplorer/types"
+)
+
+// fakeCostExplorer はRDSのリザーブドインスタンス、EC2のオンデマンドの利用、Savings Planを返すCost Explorerの代わり
+type fakeCostExplorer struct{}
+
+func (f *fakeCostExplorer) GetReservationUtilization(ctx context.Context, params *costexplorer.GetReservationUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationUtilizationOutput, error) {
+	if params.Filter.Dimensions.Values[0] != costExplorerServices["rds"] {
+		return nil, &costexplorerTypes.DataUnavailableException{}
+	}
+	group := func(id, instanceType string, purchased, actual string) costexplorerTypes.ReservationUtilizationGroup {
+		return costexplorerTypes.ReservationUtilizationGroup{
+			Key:        aws.String("SUBSCRIPTION_ID"),
+			Value:      aws.String(id),
+			Attributes: map[string]string{"instanceType": instanceType, "region": "ap-northeast-1"},
+			Utilization: &costexplorerTypes.ReservationAggregates{
+				PurchasedHours:       aws.String(purchased),
+				TotalActualHours:     aws.String(actual),
+				UnusedHours:          aws.String("100"),
+				RICostForUnusedHours: aws.String("25.5"),
+			},
+		}
+	}
+	// 2ページにわたる2か月分の期間。ri-1は半分の時間、ri-2は全時間使われている
+	if params.NextPageToken == nil {
+		return &costexplorer.GetReservationUtilizationOutput{
+			UtilizationsByTime: []costexplorerTypes.UtilizationByTime{
+				{Groups: []costexplorerTypes.ReservationUtilizationGroup{group("ri-1", "db.r6g.large", "200", "100"), group("ri-2", "db.m6g.large", "200", "200")}},
+			},
+			NextPageToken: aws.String("page2"),
+		}, nil
+	}
+	return &costexplorer.GetReservationUtilizationOutput{
+		UtilizationsByTime: []costexplorerTypes.UtilizationByTime{
+			{Groups: []costexplorerTypes.ReservationUtilizationGroup{group("ri-1", "db.r6g.large", "200", "100")}},
+		},
+	}, nil
+}
+
+func (f *fakeCostExplorer) GetReservationCoverage(ctx context.Context, params *costexplorer.GetReservationCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetReservationCoverageOutput, error) {
+	if params.Filter.Dimensions.Values[0] != costExplorerServices["ec2"] {
+		return &costexplorer.GetReservationCoverageOutput{}, nil
+	}
+	group := func(instanceType, total, onDemand, cost string) costexplorerTypes.ReservationCoverageGroup {
+		return costexplorerTypes.ReservationCoverageGroup{
+			Attributes: map[string]string{"instanceType": instanceType, "region": "us-east-1"},
+			Coverage: &costexplorerTypes.Coverage{
+				CoverageHours: &costexplorerTypes.CoverageHours{TotalRunningHours: aws.String(total), OnDemandHours: aws.String(onDemand)},
+				CoverageCost:  &costexplorerTypes.CoverageCost{OnDemandCost: aws.String(cost)},
+			},
+		}
+	}
+	return &costexplorer.GetReservationCoverageOutput{
+		CoveragesByTime: []costexplorerTypes.CoverageByTime{
+			{Groups: []costexplorerTypes.ReservationCoverageGroup{
+				group("m5.large", "100", "50", "10"),
+				group("m5.2xlarge", "100", "0", "0"),
+				group("c5.large", "10", "10", "1"),
+			}},
+		},
+	}, nil
+}
+
+func (f *fakeCostExplorer) GetSavingsPlansUtilization(ctx context.Context, params *costexplorer.GetSavingsPlansUtilizationInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansUtilizationOutput, error) {
+	return &costexplorer.GetSavingsPlansUtilizationOutput{
+		Total: &costexplorerTypes.SavingsPlansUtilizationAggregates{
+			Utilization: &costexplorerTypes.SavingsPlansUtilization{
+				TotalCommitment:       aws.String("100"),
+				UsedCommitment:        aws.String("90"),
+				UnusedCommitment:      aws.String("10"),
+				UtilizationPercentage: aws.String("90"),
+			},
+		},
+	}, nil
+}
+
+func (f *fakeCostExplorer) GetSavingsPlansCoverage(ctx context.Context, params *costexplorer.GetSavingsPlansCoverageInput, optFns ...func(*costexplorer.Options)) (*costexplorer.GetSavingsPlansCoverageOutput, error) {
+	return &costexplorer.GetSavingsPlansCoverageOutput{
+		SavingsPlansCoverages: []costexplorerTypes.SavingsPlansCoverage{
+			{
+				Attributes: map[string]string{"SERVICE": costExplorerServices["ec2"], "INSTANCE_TYPE_FAMILY": "m5"},
+				Coverage:   &costexplorerTypes.SavingsPlansCoverageData{OnDemandCost: aws.String("30"), SpendCoveredBySavingsPlans: aws.String("90")},
+			},
+			{
+				Attributes: map[string]string{"SERVICE": "AWS Lambda", "INSTANCE_TYPE_FAMILY": ""},
+				Coverage:   &costexplorerTypes.SavingsPlansCoverageData{OnDemandCost: aws.String("0"), SpendCoveredBySavingsPlans: aws.String("5")},
+			},
+		},
+	}, nil
+}
+
+func TestUtilizationReport(t *testing.T) {
+	cmd := NewUtilizationCommand(UtilizationOption{
+		Threshold: 80,
+		Services:  []string{"ec2", "rds"},
+	})
+	report, err := cmd.report(context.Background(), &fakeCostExplorer{}, "2026-01-01", "2026-03-01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []UtilizationLine{
+		// ri-1は両ページで400時間のうち200時間使われた。ri-2はしきい値以上
+		{Type: "ri-utilization", Service: "RDS", Instance: "db.r6g.large", Region: "ap-northeast-1", Percentage: 50, Hours: 200, Cost: 51},
+		// m5のサイズはファミリーにまとめ、完全にカバーされたファミリーは除く
+		{Type: "ri-coverage", Service: "EC2", Instance: "m5", Region: "us-east-1", Percentage: 75, Hours: 50, Cost: 10},
+		{Type: "ri-coverage", Service: "EC2", Instance: "c5", Region: "us-east-1", Percentage: 0, Hours: 10, Cost: 1},
+		{Type: "sp-utilization", Service: "Savings Plans", Percentage: 90, Cost: 10},
+		{Type: "sp-coverage", Service: "EC2", Instance: "m5", Percentage: 75, Cost: 30},
+	}
+	if len(report.Lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d: %+v", len(expected), len(report.Lines), report.Lines)
+	}
+	for i, line := range report.Lines {
+		want := expected[i]
+		if line.Type != want.Type || line.Service != want.Service || line.Instance != want.Instance || line.Region != want.Region ||
+			math.Abs(line.Percentage-want.Percentage) > 1e-9 || line.Hours != want.Hours || math.Abs(line.Cost-want.Cost) > 1e-9 {
+			t.Errorf("line %d: expected %+v, got %+v", i, want, line)
+		}
+	}
+}
+
+func TestUtilizationPeriod(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2026-10-18T12:34:56Z")
+
+	start, end, err := NewUtilizationCommand(UtilizationOption{}).period(now)
+	if err != nil || start != "2026-09-18" || end != "2026-10-18" {
+		t.Errorf("default period: got %s - %s (%v)", start, end, err)
+	}
+
+	if _, _, err := NewUtilizationCommand(UtilizationOption{Start: "2026-02-01", End: "2026-01-01"}).period(now); err == nil {
+		t.Error("Expected an error when start is after end")
+	}
+}